@@ -0,0 +1,351 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// reviewConcurrency is how many reviews are created at the same time at most, when several namespaces are reviewed.
+const reviewConcurrency = 8
+
+// Access describes which resources a user can list, in all namespaces or per namespace.
+// An Access isn't modified once it's returned by the Authorizer, more reviews result in a new one.
+type Access struct {
+	// clusterWide are the resources that are reviewed in all namespaces, and whether they can be listed there
+	clusterWide map[schema.GroupResource]bool
+	// namespaced are the resources that are reviewed in each namespace, and whether they can be listed there
+	namespaced map[string]map[schema.GroupResource]bool
+	// rules are the rules of the user in each reviewed namespace, so that more resources can be reviewed there
+	// without reviewing the rules again
+	rules map[string]authorizationv1.SubjectRulesReviewStatus
+}
+
+func newAccess() *Access {
+	return &Access{
+		clusterWide: make(map[schema.GroupResource]bool),
+		namespaced:  make(map[string]map[schema.GroupResource]bool),
+		rules:       make(map[string]authorizationv1.SubjectRulesReviewStatus),
+	}
+}
+
+// CanList returns whether the resource can be listed in the namespace.
+func (a *Access) CanList(gr schema.GroupResource, namespace string) bool {
+	if a.clusterWide[gr] {
+		return true
+	}
+	return a.namespaced[namespace][gr]
+}
+
+// CanListAll returns whether the resource can be listed in all namespaces.
+func (a *Access) CanListAll(gr schema.GroupResource) bool {
+	return a.clusterWide[gr]
+}
+
+// covers returns whether the access is reviewed for all the given resources in all the given namespaces.
+func (a *Access) covers(resources []schema.GroupResource, namespaces []string) bool {
+	for _, gr := range resources {
+		allowed, ok := a.clusterWide[gr]
+		if !ok {
+			return false
+		}
+		if allowed {
+			continue
+		}
+		for _, ns := range namespaces {
+			if _, ok := a.namespaced[ns][gr]; !ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// clone returns a copy of the access that can be added to.
+func (a *Access) clone() *Access {
+	c := newAccess()
+	c.merge(a)
+	return c
+}
+
+// merge adds the decisions of the other access that this one doesn't have yet.
+func (a *Access) merge(other *Access) {
+	for gr, allowed := range other.clusterWide {
+		if _, ok := a.clusterWide[gr]; !ok {
+			a.clusterWide[gr] = allowed
+		}
+	}
+	for ns, resources := range other.namespaced {
+		if a.namespaced[ns] == nil {
+			a.namespaced[ns] = make(map[schema.GroupResource]bool, len(resources))
+		}
+		for gr, allowed := range resources {
+			if _, ok := a.namespaced[ns][gr]; !ok {
+				a.namespaced[ns][gr] = allowed
+			}
+		}
+	}
+	for ns, rules := range other.rules {
+		if _, ok := a.rules[ns]; !ok {
+			a.rules[ns] = rules
+		}
+	}
+}
+
+// Authorizer reviews which resources the owners of the bearer tokens can list.
+// The decisions are cached per token for a while, so that a user refreshing the Backstage catalog doesn't result
+// in a pile of reviews on every request. The decisions of the requests for different resources and namespaces are
+// merged, only what's not decided yet is reviewed.
+type Authorizer struct {
+	newClient func(token string) (kubernetes.Interface, error)
+	ttl       time.Duration
+	now       func() time.Time
+
+	lock sync.Mutex
+	// decisions are keyed by the hash of the token, so that we don't keep the tokens around
+	decisions map[string]decision
+}
+
+type decision struct {
+	access *Access
+	expiry time.Time
+}
+
+// NewAuthorizer creates an Authorizer that creates the reviews with the given config and the bearer token of the
+// user. The config must not carry any credentials.
+func NewAuthorizer(config *rest.Config, ttl time.Duration) *Authorizer {
+	return &Authorizer{
+		newClient: func(token string) (kubernetes.Interface, error) {
+			userConfig := rest.CopyConfig(config)
+			userConfig.BearerToken = token
+			return kubernetes.NewForConfig(userConfig)
+		},
+		ttl:       ttl,
+		now:       time.Now,
+		decisions: make(map[string]decision),
+	}
+}
+
+// Access reviews which of the given resources the owner of the token can list, in all namespaces or in the given
+// namespaces.
+// Reviews are done with SelfSubjectAccessReviews for all namespaces first. Only the resources that can't be listed
+// in all namespaces are reviewed per namespace with SelfSubjectRulesReviews, a few namespaces at a time. The rules
+// of a namespace are only reviewed once while the decision is cached, whichever resources are asked for.
+// Any authenticated user is allowed to create these reviews.
+func (a *Authorizer) Access(ctx context.Context, token string, resources []schema.GroupResource, namespaces []string) (*Access, error) {
+	key := tokenKey(token)
+
+	a.lock.Lock()
+	d, ok := a.decisions[key]
+	a.lock.Unlock()
+
+	cached := ok && a.now().Before(d.expiry)
+	if cached && d.access.covers(resources, namespaces) {
+		return d.access, nil
+	}
+
+	client, err := a.newClient(token)
+	if err != nil {
+		return nil, fmt.Errorf("error creating kubernetes client: %w", err)
+	}
+
+	access := newAccess()
+	if cached {
+		access = d.access.clone()
+	}
+	if err := review(ctx, client, access, resources, namespaces); err != nil {
+		return nil, err
+	}
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	now := a.now()
+	for k, d := range a.decisions {
+		if !now.Before(d.expiry) {
+			delete(a.decisions, k)
+		}
+	}
+
+	// the merged decisions expire with the oldest of them
+	expiry := now.Add(a.ttl)
+	if cached {
+		expiry = d.expiry
+	}
+	// another request of the same user may have been reviewed in the meantime
+	if current, ok := a.decisions[key]; ok && current.access != d.access {
+		access.merge(current.access)
+		if current.expiry.Before(expiry) {
+			expiry = current.expiry
+		}
+	}
+	a.decisions[key] = decision{
+		access: access,
+		expiry: expiry,
+	}
+
+	return access, nil
+}
+
+// review adds the decisions for the given resources and namespaces that the access doesn't have yet.
+func review(ctx context.Context, client kubernetes.Interface, access *Access, resources []schema.GroupResource, namespaces []string) error {
+	remaining := make([]schema.GroupResource, 0, len(resources))
+	for _, gr := range resources {
+		allowed, ok := access.clusterWide[gr]
+		if !ok {
+			var err error
+			allowed, err = canList(ctx, client, gr, metav1.NamespaceAll)
+			if err != nil {
+				return err
+			}
+			access.clusterWide[gr] = allowed
+		}
+		if !allowed {
+			remaining = append(remaining, gr)
+		}
+	}
+	if len(remaining) == 0 {
+		return nil
+	}
+
+	// the namespaces are reviewed in parallel, each into its own result, which are added to the access afterwards
+	results := make([]namespaceReview, len(namespaces))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(reviewConcurrency)
+	for i, ns := range namespaces {
+		rules, reviewed := access.rules[ns]
+		decided := access.namespaced[ns]
+		g.Go(func() error {
+			result, err := reviewNamespace(gctx, client, ns, rules, reviewed, decided, remaining)
+			results[i] = result
+			return err
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	for i, ns := range namespaces {
+		access.rules[ns] = results[i].rules
+		if access.namespaced[ns] == nil {
+			access.namespaced[ns] = make(map[schema.GroupResource]bool, len(results[i].decisions))
+		}
+		for gr, allowed := range results[i].decisions {
+			access.namespaced[ns][gr] = allowed
+		}
+	}
+	return nil
+}
+
+// namespaceReview is the result of the review of a namespace.
+type namespaceReview struct {
+	rules     authorizationv1.SubjectRulesReviewStatus
+	decisions map[schema.GroupResource]bool
+}
+
+// reviewNamespace decides whether the resources that aren't decided yet can be listed in the namespace. The rules
+// of the namespace are reviewed unless they're given.
+func reviewNamespace(ctx context.Context, client kubernetes.Interface, ns string, rules authorizationv1.SubjectRulesReviewStatus, reviewed bool, decided map[schema.GroupResource]bool, resources []schema.GroupResource) (namespaceReview, error) {
+	result := namespaceReview{rules: rules, decisions: make(map[schema.GroupResource]bool)}
+
+	undecided := make([]schema.GroupResource, 0, len(resources))
+	for _, gr := range resources {
+		if _, ok := decided[gr]; !ok {
+			undecided = append(undecided, gr)
+		}
+	}
+	if len(undecided) == 0 {
+		return result, nil
+	}
+
+	if !reviewed {
+		rulesReview := &authorizationv1.SelfSubjectRulesReview{
+			Spec: authorizationv1.SelfSubjectRulesReviewSpec{
+				Namespace: ns,
+			},
+		}
+		response, err := client.AuthorizationV1().SelfSubjectRulesReviews().Create(ctx, rulesReview, metav1.CreateOptions{})
+		if err != nil {
+			return result, fmt.Errorf("error reviewing rules in namespace %s: %w", ns, err)
+		}
+		result.rules = response.Status
+	}
+
+	for _, gr := range undecided {
+		if rulesAllowList(result.rules.ResourceRules, gr) {
+			result.decisions[gr] = true
+			continue
+		}
+
+		// the rules can be incomplete, e.g. when an authorizer other than RBAC is in use.
+		// in that case, ask about the resource explicitly.
+		allowed := false
+		if result.rules.Incomplete {
+			var err error
+			allowed, err = canList(ctx, client, gr, ns)
+			if err != nil {
+				return result, err
+			}
+		}
+		result.decisions[gr] = allowed
+	}
+	return result, nil
+}
+
+// canList checks whether the owner of the client's credentials can list the resource in the namespace.
+func canList(ctx context.Context, client kubernetes.Interface, gr schema.GroupResource, namespace string) (bool, error) {
+	accessReview := &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: namespace,
+				Verb:      "list",
+				Group:     gr.Group,
+				Resource:  gr.Resource,
+			},
+		},
+	}
+
+	response, err := client.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, accessReview, metav1.CreateOptions{})
+	if err != nil {
+		return false, fmt.Errorf("error reviewing access to %s: %w", gr, err)
+	}
+	return response.Status.Allowed, nil
+}
+
+func rulesAllowList(rules []authorizationv1.ResourceRule, gr schema.GroupResource) bool {
+	for _, rule := range rules {
+		// rules restricted to some resource names don't allow listing
+		if len(rule.ResourceNames) > 0 {
+			continue
+		}
+		if matchesRule(rule.Verbs, "list") && matchesRule(rule.APIGroups, gr.Group) && matchesRule(rule.Resources, gr.Resource) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesRule(values []string, value string) bool {
+	for _, v := range values {
+		if v == "*" || v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func tokenKey(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,255 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+var (
+	brokers  = schema.GroupResource{Group: "eventing.knative.dev", Resource: "brokers"}
+	triggers = schema.GroupResource{Group: "eventing.knative.dev", Resource: "triggers"}
+)
+
+// fakeReviews is a fake API server for the access and rules reviews.
+type fakeReviews struct {
+	// allowed are the resources that can be listed, by namespace. "" is for all namespaces.
+	allowed map[string]map[schema.GroupResource]bool
+	// rules are the rules returned by the rules reviews, by namespace
+	rules map[string][]authorizationv1.ResourceRule
+	// incomplete marks the rules reviews as incomplete
+	incomplete bool
+	err        error
+
+	accessReviews int
+	rulesReviews  int
+}
+
+func (f *fakeReviews) client() kubernetes.Interface {
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		f.accessReviews++
+		if f.err != nil {
+			return true, nil, f.err
+		}
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+		attributes := review.Spec.ResourceAttributes
+		review.Status.Allowed = attributes.Verb == "list" &&
+			f.allowed[attributes.Namespace][schema.GroupResource{Group: attributes.Group, Resource: attributes.Resource}]
+		return true, review, nil
+	})
+	client.PrependReactor("create", "selfsubjectrulesreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		f.rulesReviews++
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectRulesReview)
+		review.Status.ResourceRules = f.rules[review.Spec.Namespace]
+		review.Status.Incomplete = f.incomplete
+		return true, review, nil
+	})
+	return client
+}
+
+func TestAuthorizerAccess(t *testing.T) {
+	type check struct {
+		resource  schema.GroupResource
+		namespace string
+		want      bool
+	}
+
+	tests := []struct {
+		name              string
+		reviews           *fakeReviews
+		namespaces        []string
+		checks            []check
+		wantErr           bool
+		wantRulesReviews  int
+		wantAccessReviews int
+	}{
+		{
+			name: "everything allowed in all namespaces",
+			reviews: &fakeReviews{
+				allowed: map[string]map[schema.GroupResource]bool{"": {brokers: true, triggers: true}},
+			},
+			namespaces: []string{"ns-1", "ns-2"},
+			checks: []check{
+				{resource: brokers, namespace: "ns-1", want: true},
+				{resource: triggers, namespace: "ns-2", want: true},
+			},
+			wantAccessReviews: 2,
+		},
+		{
+			name: "triggers allowed in a single namespace",
+			reviews: &fakeReviews{
+				allowed: map[string]map[schema.GroupResource]bool{"": {brokers: true}},
+				rules: map[string][]authorizationv1.ResourceRule{
+					"ns-1": {{Verbs: []string{"get", "list"}, APIGroups: []string{"eventing.knative.dev"}, Resources: []string{"triggers"}}},
+					"ns-2": {{Verbs: []string{"get"}, APIGroups: []string{"eventing.knative.dev"}, Resources: []string{"triggers"}}},
+				},
+			},
+			namespaces: []string{"ns-1", "ns-2"},
+			checks: []check{
+				{resource: brokers, namespace: "ns-2", want: true},
+				{resource: triggers, namespace: "ns-1", want: true},
+				{resource: triggers, namespace: "ns-2", want: false},
+				{resource: triggers, namespace: "ns-3", want: false},
+			},
+			wantAccessReviews: 2,
+			wantRulesReviews:  2,
+		},
+		{
+			name: "wildcard rules",
+			reviews: &fakeReviews{
+				rules: map[string][]authorizationv1.ResourceRule{
+					"ns-1": {{Verbs: []string{"*"}, APIGroups: []string{"*"}, Resources: []string{"*"}}},
+				},
+			},
+			namespaces: []string{"ns-1"},
+			checks: []check{
+				{resource: brokers, namespace: "ns-1", want: true},
+				{resource: triggers, namespace: "ns-1", want: true},
+			},
+			wantAccessReviews: 2,
+			wantRulesReviews:  1,
+		},
+		{
+			name: "rules restricted to resource names don't allow listing",
+			reviews: &fakeReviews{
+				rules: map[string][]authorizationv1.ResourceRule{
+					"ns-1": {{Verbs: []string{"list"}, APIGroups: []string{"eventing.knative.dev"}, Resources: []string{"brokers"}, ResourceNames: []string{"default"}}},
+				},
+			},
+			namespaces: []string{"ns-1"},
+			checks: []check{
+				{resource: brokers, namespace: "ns-1", want: false},
+			},
+			wantAccessReviews: 2,
+			wantRulesReviews:  1,
+		},
+		{
+			name: "incomplete rules fall back to access reviews",
+			reviews: &fakeReviews{
+				allowed:    map[string]map[schema.GroupResource]bool{"ns-1": {brokers: true}},
+				incomplete: true,
+			},
+			namespaces: []string{"ns-1"},
+			checks: []check{
+				{resource: brokers, namespace: "ns-1", want: true},
+				{resource: triggers, namespace: "ns-1", want: false},
+			},
+			wantAccessReviews: 4,
+			wantRulesReviews:  1,
+		},
+		{
+			name: "review error",
+			reviews: &fakeReviews{
+				err: errors.New("boom"),
+			},
+			namespaces:        []string{"ns-1"},
+			wantErr:           true,
+			wantAccessReviews: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authorizer := &Authorizer{
+				newClient: func(string) (kubernetes.Interface, error) { return tt.reviews.client(), nil },
+				ttl:       time.Minute,
+				now:       time.Now,
+				decisions: make(map[string]decision),
+			}
+
+			access, err := authorizer.Access(context.Background(), "token", []schema.GroupResource{brokers, triggers}, tt.namespaces)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Access() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			for _, c := range tt.checks {
+				if got := access.CanList(c.resource, c.namespace); got != c.want {
+					t.Errorf("CanList(%v, %q) = %v, want %v", c.resource, c.namespace, got, c.want)
+				}
+			}
+
+			if tt.reviews.accessReviews != tt.wantAccessReviews {
+				t.Errorf("access reviews = %d, want %d", tt.reviews.accessReviews, tt.wantAccessReviews)
+			}
+			if tt.reviews.rulesReviews != tt.wantRulesReviews {
+				t.Errorf("rules reviews = %d, want %d", tt.reviews.rulesReviews, tt.wantRulesReviews)
+			}
+		})
+	}
+}
+
+func TestAuthorizerCachesDecisions(t *testing.T) {
+	reviews := &fakeReviews{
+		allowed: map[string]map[schema.GroupResource]bool{"": {brokers: true}},
+	}
+
+	now := time.Now()
+	authorizer := &Authorizer{
+		newClient: func(string) (kubernetes.Interface, error) { return reviews.client(), nil },
+		ttl:       time.Minute,
+		now:       func() time.Time { return now },
+		decisions: make(map[string]decision),
+	}
+
+	access := func(token string, resources []schema.GroupResource, namespaces []string) {
+		t.Helper()
+		if _, err := authorizer.Access(context.Background(), token, resources, namespaces); err != nil {
+			t.Fatalf("Access() error = %v", err)
+		}
+	}
+	wantReviews := func(want int) {
+		t.Helper()
+		if got := reviews.accessReviews + reviews.rulesReviews; got != want {
+			t.Errorf("reviews = %d, want %d", got, want)
+		}
+	}
+
+	access("token-1", []schema.GroupResource{brokers}, []string{"ns-1"})
+	wantReviews(1)
+
+	// same token, the decision is cached
+	access("token-1", []schema.GroupResource{brokers}, []string{"ns-1"})
+	wantReviews(1)
+
+	// another token is reviewed separately
+	access("token-2", []schema.GroupResource{brokers}, []string{"ns-1"})
+	wantReviews(2)
+
+	// a resource that's not reviewed yet, only that resource is reviewed
+	access("token-1", []schema.GroupResource{brokers, triggers}, []string{"ns-1"})
+	wantReviews(4)
+
+	// a namespace that's not reviewed yet, only that namespace is reviewed
+	access("token-1", []schema.GroupResource{brokers, triggers}, []string{"ns-1", "ns-2"})
+	wantReviews(5)
+
+	access("token-1", []schema.GroupResource{brokers, triggers}, []string{"ns-1", "ns-2"})
+	wantReviews(5)
+
+	// the decisions for other namespaces are merged, they don't replace the earlier ones
+	access("token-1", []schema.GroupResource{brokers, triggers}, []string{"ns-3"})
+	wantReviews(6)
+
+	access("token-1", []schema.GroupResource{brokers, triggers}, []string{"ns-1"})
+	wantReviews(6)
+
+	access("token-1", []schema.GroupResource{brokers, triggers}, []string{"ns-1", "ns-2", "ns-3"})
+	wantReviews(6)
+
+	// the decision expires
+	now = now.Add(2 * time.Minute)
+	access("token-1", []schema.GroupResource{brokers, triggers}, []string{"ns-1", "ns-2"})
+	wantReviews(10)
+
+	if len(authorizer.decisions) != 1 {
+		t.Errorf("decisions = %d, want the expired ones to be dropped", len(authorizer.decisions))
+	}
+}
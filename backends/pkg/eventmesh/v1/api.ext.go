@@ -1,7 +1,16 @@
 package v1
 
-import "knative.dev/backstage-plugins/backends/pkg/util"
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"knative.dev/backstage-plugins/backends/pkg/util"
+)
 
 func (gknn GroupKindNamespacedName) String() string {
 	return util.GKNamespacedName(gknn.Group, gknn.Kind, gknn.Namespace, gknn.Name)
 }
+
+// GroupKind returns the kind of the referenced resource.
+func (gknn GroupKindNamespacedName) GroupKind() schema.GroupKind {
+	return schema.GroupKind{Group: gknn.Group, Kind: gknn.Kind}
+}
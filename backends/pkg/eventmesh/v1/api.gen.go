@@ -3,10 +3,50 @@
 // Code generated by github.com/oapi-codegen/oapi-codegen/v2 version v2.4.1 DO NOT EDIT.
 package v1
 
+import (
+	"time"
+)
+
 const (
 	BearerAuthScopes = "bearerAuth.Scopes"
 )
 
+// Defines values for EventMeshKind.
+const (
+	MeshKindBrokers       EventMeshKind = "brokers"
+	MeshKindEventTypes    EventMeshKind = "eventTypes"
+	MeshKindParallels     EventMeshKind = "parallels"
+	MeshKindSequences     EventMeshKind = "sequences"
+	MeshKindSinks         EventMeshKind = "sinks"
+	MeshKindSources       EventMeshKind = "sources"
+	MeshKindSubscribables EventMeshKind = "subscribables"
+	MeshKindSubscriptions EventMeshKind = "subscriptions"
+	MeshKindTriggers      EventMeshKind = "triggers"
+)
+
+// Defines values for EventMeshWatchEventKind.
+const (
+	WatchKindBroker       EventMeshWatchEventKind = "Broker"
+	WatchKindConsumer     EventMeshWatchEventKind = "Consumer"
+	WatchKindEventType    EventMeshWatchEventKind = "EventType"
+	WatchKindParallel     EventMeshWatchEventKind = "Parallel"
+	WatchKindSequence     EventMeshWatchEventKind = "Sequence"
+	WatchKindSink         EventMeshWatchEventKind = "Sink"
+	WatchKindSource       EventMeshWatchEventKind = "Source"
+	WatchKindSubscribable EventMeshWatchEventKind = "Subscribable"
+	WatchKindSubscription EventMeshWatchEventKind = "Subscription"
+	WatchKindTrigger      EventMeshWatchEventKind = "Trigger"
+)
+
+// Defines values for EventMeshWatchEventType.
+const (
+	WatchEventAdded    EventMeshWatchEventType = "ADDED"
+	WatchEventBookmark EventMeshWatchEventType = "BOOKMARK"
+	WatchEventDeleted  EventMeshWatchEventType = "DELETED"
+	WatchEventModified EventMeshWatchEventType = "MODIFIED"
+	WatchEventSnapshot EventMeshWatchEventType = "SNAPSHOT"
+)
+
 // Broker Broker is a simplified representation of a Knative Eventing Broker that is easier to consume by the Backstage plugin.
 type Broker struct {
 	// Annotations Annotations of the broker.
@@ -24,25 +64,181 @@ type Broker struct {
 	// ProvidedEventTypes List of event types provided by the broker.
 	ProvidedEventTypes []string `json:"providedEventTypes"`
 
+	// Status ResourceStatus is the normalized status of a Kubernetes resource. It's taken from the `Ready` condition and the address of the resource. Not set when the resource doesn't report any status yet.
+	Status *ResourceStatus `json:"status,omitempty"`
+
 	// UID UID of the broker.
 	UID string `json:"uid"`
 }
 
+// Condition Condition is a status condition of a Kubernetes resource.
+type Condition struct {
+	// LastTransitionTime LastTransitionTime is the last time the condition transitioned from one status to another.
+	LastTransitionTime *time.Time `json:"lastTransitionTime,omitempty"`
+
+	// Message Message is a human-readable message about the last transition of the condition.
+	Message string `json:"message,omitempty"`
+
+	// Reason Reason is a one-word, CamelCase reason for the last transition of the condition.
+	Reason string `json:"reason,omitempty"`
+
+	// Status Status of the condition, True, False or Unknown.
+	Status string `json:"status"`
+
+	// Type Type of the condition.
+	Type string `json:"type"`
+}
+
+// ConsumerEdge ConsumerEdge connects an event type to one of its consumers. It's the watch stream counterpart of an entry in the consumers of an event type.
+type ConsumerEdge struct {
+	// Consumer EventTypeConsumer is a consumer of an event type and the way the events reach it. The hop is the subscriber of the trigger or the subscription the events are delivered to first, which is either the consumer itself or a broker, channel, sequence or parallel that forwards them. It's not set when that subscriber is only known by its URI.
+	Consumer EventTypeConsumer `json:"consumer"`
+
+	// EventType The `<namespace>/<name>` of the event type.
+	EventType string `json:"eventType"`
+}
+
+// Delivery Delivery is the delivery spec, i.e. how the events are retried and where they are sent when they can't be delivered.
+type Delivery struct {
+	// BackoffDelay BackoffDelay is the delay before retrying, as an ISO 8601 duration.
+	BackoffDelay string `json:"backoffDelay,omitempty"`
+
+	// BackoffPolicy BackoffPolicy is the retry backoff policy, linear or exponential.
+	BackoffPolicy string `json:"backoffPolicy,omitempty"`
+
+	// DeadLetterSink Destination is where events are delivered to. It's either a reference to an addressable resource, a URI, or a URI relative to the resource.
+	DeadLetterSink *Destination `json:"deadLetterSink,omitempty"`
+
+	// DeadLetterSinkURI DeadLetterSinkURI is the resolved URI of the dead letter sink, as reported in the status.
+	DeadLetterSinkURI string `json:"deadLetterSinkUri,omitempty"`
+
+	// Retry Retry is the minimum number of retries in addition to the initial attempt.
+	Retry *int32 `json:"retry,omitempty"`
+
+	// Timeout Timeout of each delivery attempt, as an ISO 8601 duration.
+	Timeout string `json:"timeout,omitempty"`
+}
+
+// Destination Destination is where events are delivered to. It's either a reference to an addressable resource, a URI, or a URI relative to the resource.
+type Destination struct {
+	// Ref GroupKindNamespacedName is a struct that holds the group, kind, namespace, and name of a Kubernetes resource.
+	Ref *GroupKindNamespacedName `json:"ref,omitempty"`
+
+	// URI URI of the destination. When the reference is set too, it's relative to the address of the referenced resource.
+	URI string `json:"uri,omitempty"`
+}
+
+// DiscoveredKind DiscoveredKind is a kind of resources that is discovered from its CRD. The API version that the resources are listed with is the storage version of the CRD if it's served, otherwise the served version that the API discovery prefers.
+type DiscoveredKind struct {
+	// Group API group of the kind.
+	Group string `json:"group"`
+
+	// Kind Name of the kind.
+	Kind string `json:"kind"`
+
+	// Resource Resource of the kind, i.e. the plural name.
+	Resource string `json:"resource"`
+
+	// Version API version that the resources of the kind are listed with.
+	Version string `json:"version"`
+}
+
 // EventMesh EventMesh is the top-level struct that holds the event mesh data. It's the struct that's serialized and sent to the Backstage plugin.
 type EventMesh struct {
 	// Brokers Brokers is a list of all brokers in the cluster.
 	Brokers []Broker `json:"brokers"`
 
+	// Continue Continue is the token to fetch the next page of the EventMesh with. It's only set when the EventMesh is paged with a limit and there are more objects. The warnings and the discovered kinds are returned with every page.
+	Continue string `json:"continue,omitempty"`
+
+	// DiscoveredKinds DiscoveredKinds is a list of the kinds of the sources, subscribables and sinks that are discovered from their CRDs, along with the API version that is used to list them.
+	DiscoveredKinds []DiscoveredKind `json:"discoveredKinds,omitempty"`
+
 	// EventTypes EventTypes is a list of all event types in the cluster. While we can embed the event types in the brokers, we keep them separate because not every event type is tied to a broker.
 	EventTypes []EventType `json:"eventTypes"`
 
+	// Parallels Parallels is a list of all parallels in the cluster.
+	Parallels []Parallel `json:"parallels"`
+
+	// Sequences Sequences is a list of all sequences in the cluster.
+	Sequences []Sequence `json:"sequences"`
+
+	// Sinks Sinks is a list of all sinks in the cluster.
+	Sinks []Sink `json:"sinks"`
+
 	// Sources Sources is a list of all sources in the cluster.
 	Sources []Source `json:"sources"`
 
 	// Subscribables Subscribables is a list of all subscribables in the cluster.
 	Subscribables []Subscribable `json:"subscribables"`
+
+	// Subscriptions Subscriptions is a list of all subscriptions in the cluster. They connect the channels to the subscribers.
+	Subscriptions []Subscription `json:"subscriptions"`
+
+	// Triggers Triggers is a list of all triggers in the cluster. They connect the brokers to the subscribers.
+	Triggers []Trigger `json:"triggers"`
+
+	// Warnings Warnings is a list of the problems that prevented parts of the event mesh from being built. The rest of the event mesh is still returned.
+	Warnings []Warning `json:"warnings,omitempty"`
 }
 
+// EventMeshKind EventMeshKind is a list of the EventMesh. The EventMesh is paged through its lists in the order of this enum.
+type EventMeshKind string
+
+// EventMeshWatchEvent EventMeshWatchEvent is an event of the EventMesh watch stream. Exactly one of the objects is set, depending on the type and the kind of the event. BOOKMARK events don't have any.
+type EventMeshWatchEvent struct {
+	// Broker Broker is a simplified representation of a Knative Eventing Broker that is easier to consume by the Backstage plugin.
+	Broker *Broker `json:"broker,omitempty"`
+
+	// Consumer ConsumerEdge connects an event type to one of its consumers. It's the watch stream counterpart of an entry in the consumers of an event type.
+	Consumer *ConsumerEdge `json:"consumer,omitempty"`
+
+	// EventMesh EventMesh is the top-level struct that holds the event mesh data. It's the struct that's serialized and sent to the Backstage plugin.
+	EventMesh *EventMesh `json:"eventMesh,omitempty"`
+
+	// EventType EventType is a simplified representation of a Knative Eventing EventType that is easier to consume by the Backstage plugin.
+	EventType *EventType `json:"eventType,omitempty"`
+
+	// Key Key of the object that is added, modified or deleted. It's `<group>/<kind>/<namespace>/<name>` for the resources, and `<eventType>/<backstageId>` followed by the hop, if any, for the consumers.
+	Key string `json:"key,omitempty"`
+
+	// Kind Kind of the object that is added, modified or deleted.
+	Kind EventMeshWatchEventKind `json:"kind,omitempty"`
+
+	// Parallel Parallel is a simplified representation of a Knative Flows Parallel that is easier to consume by the Backstage plugin. The events that are sent to a parallel are delivered to all of its branches.
+	Parallel *Parallel `json:"parallel,omitempty"`
+
+	// ResourceVersion Version of the EventMesh after the event. It can be used to resume the stream.
+	ResourceVersion string `json:"resourceVersion"`
+
+	// Sequence Sequence is a simplified representation of a Knative Flows Sequence that is easier to consume by the Backstage plugin. The events that are sent to a sequence go through its steps one after the other.
+	Sequence *Sequence `json:"sequence,omitempty"`
+
+	// Sink Sink is a simplified representation of a Knative Eventing sink, e.g. a JobSink, an IntegrationSink or a KafkaSink, that is easier to consume by the Backstage plugin. Sinks are where the events end up, they don't forward them any further.
+	Sink *Sink `json:"sink,omitempty"`
+
+	// Source Source is a simplified representation of a Knative Eventing Source that is easier to consume by the Backstage plugin.
+	Source *Source `json:"source,omitempty"`
+
+	// Subscribable Subscribable is a simplified representation of a Knative Eventing Subscribable that is easier to consume by the Backstage plugin. These subscribables can be channels at the moment.
+	Subscribable *Subscribable `json:"subscribable,omitempty"`
+
+	// Subscription Subscription is a simplified representation of a Knative Messaging Subscription that is easier to consume by the Backstage plugin.
+	Subscription *Subscription `json:"subscription,omitempty"`
+
+	// Trigger Trigger is a simplified representation of a Knative Eventing Trigger that is easier to consume by the Backstage plugin.
+	Trigger *Trigger `json:"trigger,omitempty"`
+
+	// Type Type of the event.
+	Type EventMeshWatchEventType `json:"type"`
+}
+
+// EventMeshWatchEventKind Kind of the object that is added, modified or deleted.
+type EventMeshWatchEventKind string
+
+// EventMeshWatchEventType Type of the event.
+type EventMeshWatchEventType string
+
 // EventType EventType is a simplified representation of a Knative Eventing EventType that is easier to consume by the Backstage plugin.
 type EventType struct {
 	// Annotations Annotations of the event type. These are passed as is, except that are filtered out by the `FilterAnnotations` function.
@@ -51,9 +247,15 @@ type EventType struct {
 	// ConsumedBy ConsumedBy is a `<namespace/name>` list of the consumers of the event type.
 	ConsumedBy []string `json:"consumedBy"`
 
+	// Consumers Consumers are the consumers in ConsumedBy along with the subscribers the events reach them through. The events are followed through the brokers, channels, sequences and parallels they are forwarded to, up to the subscribers and the sinks that consume them.
+	Consumers []EventTypeConsumer `json:"consumers,omitempty"`
+
 	// Description Description of the event type.
 	Description *string `json:"description,omitempty"`
 
+	// IndeterminateConsumedBy IndeterminateConsumedBy is the subset of ConsumedBy whose trigger filters depend on event attributes that are only known at runtime (e.g. `id`, `subject` or extensions), so it can't be decided up front whether they receive events of this type.
+	IndeterminateConsumedBy []string `json:"indeterminateConsumedBy,omitempty"`
+
 	// Labels Labels of the event type. These are passed as is.
 	Labels map[string]string `json:"labels"`
 
@@ -73,6 +275,12 @@ type EventType struct {
 	// SchemaURL URL to the schema.
 	SchemaURL *string `json:"schemaURL,omitempty"`
 
+	// Source Source of the event, i.e. the `spec.source` of the EventType. Not set when the event type can be produced by any source.
+	Source *string `json:"source,omitempty"`
+
+	// Status ResourceStatus is the normalized status of a Kubernetes resource. It's taken from the `Ready` condition and the address of the resource. Not set when the resource doesn't report any status yet.
+	Status *ResourceStatus `json:"status,omitempty"`
+
 	// Type Type of the event.
 	Type string `json:"type"`
 
@@ -80,6 +288,18 @@ type EventType struct {
 	Uid string `json:"uid"`
 }
 
+// EventTypeConsumer EventTypeConsumer is a consumer of an event type and the way the events reach it. The hop is the subscriber of the trigger or the subscription the events are delivered to first, which is either the consumer itself or a broker, channel, sequence or parallel that forwards them. It's not set when that subscriber is only known by its URI.
+type EventTypeConsumer struct {
+	// BackstageID Backstage ID of the consumer that eventually receives the events.
+	BackstageID string `json:"backstageId"`
+
+	// Hop GroupKindNamespacedName is a struct that holds the group, kind, namespace, and name of a Kubernetes resource.
+	Hop *GroupKindNamespacedName `json:"hop,omitempty"`
+
+	// Indeterminate Whether the filters on the way to the consumer depend on event attributes that are only known at runtime, so the consumer may or may not receive the events.
+	Indeterminate bool `json:"indeterminate,omitempty"`
+}
+
 // GroupKindNamespacedName GroupKindNamespacedName is a struct that holds the group, kind, namespace, and name of a Kubernetes resource.
 type GroupKindNamespacedName struct {
 	// Group Kubernetes API group of the resource, without the version.
@@ -95,6 +315,171 @@ type GroupKindNamespacedName struct {
 	Namespace string `json:"namespace"`
 }
 
+// Parallel Parallel is a simplified representation of a Knative Flows Parallel that is easier to consume by the Backstage plugin. The events that are sent to a parallel are delivered to all of its branches.
+type Parallel struct {
+	// Annotations Annotations of the parallel.
+	Annotations map[string]string `json:"annotations"`
+
+	// Branches Branches of the parallel.
+	Branches []ParallelBranch `json:"branches"`
+
+	// IngressChannel GroupKindNamespacedName is a struct that holds the group, kind, namespace, and name of a Kubernetes resource.
+	IngressChannel *GroupKindNamespacedName `json:"ingressChannel,omitempty"`
+
+	// Labels Labels of the parallel.
+	Labels map[string]string `json:"labels"`
+
+	// Name Name of the parallel.
+	Name string `json:"name"`
+
+	// Namespace Namespace of the parallel.
+	Namespace string `json:"namespace"`
+
+	// ProvidedEventTypes List of the event types that are sent to the parallel, by triggers, subscriptions or as declared by the event types themselves. They reach all the branches.
+	ProvidedEventTypes []string `json:"providedEventTypes"`
+
+	// Reply Destination is where events are delivered to. It's either a reference to an addressable resource, a URI, or a URI relative to the resource.
+	Reply *Destination `json:"reply,omitempty"`
+
+	// Status ResourceStatus is the normalized status of a Kubernetes resource. It's taken from the `Ready` condition and the address of the resource. Not set when the resource doesn't report any status yet.
+	Status *ResourceStatus `json:"status,omitempty"`
+
+	// UID UID of the parallel.
+	UID string `json:"uid"`
+}
+
+// ParallelBranch ParallelBranch is a branch of a parallel, along with the channels and the subscriptions that deliver the events to its filter and subscriber. The events only reach the subscriber if the filter lets them through.
+type ParallelBranch struct {
+	// BackstageID Backstage ID of the subscriber of the branch. Not set when the subscriber is not registered on Backstage, or when it forwards the events to other subscribers.
+	BackstageID string `json:"backstageId,omitempty"`
+
+	// Delivery Delivery is the delivery spec, i.e. how the events are retried and where they are sent when they can't be delivered.
+	Delivery *Delivery `json:"delivery,omitempty"`
+
+	// Filter Destination is where events are delivered to. It's either a reference to an addressable resource, a URI, or a URI relative to the resource.
+	Filter *Destination `json:"filter,omitempty"`
+
+	// FilterChannel GroupKindNamespacedName is a struct that holds the group, kind, namespace, and name of a Kubernetes resource.
+	FilterChannel *GroupKindNamespacedName `json:"filterChannel,omitempty"`
+
+	// FilterSubscription GroupKindNamespacedName is a struct that holds the group, kind, namespace, and name of a Kubernetes resource.
+	FilterSubscription *GroupKindNamespacedName `json:"filterSubscription,omitempty"`
+
+	// Reply Destination is where events are delivered to. It's either a reference to an addressable resource, a URI, or a URI relative to the resource.
+	Reply *Destination `json:"reply,omitempty"`
+
+	// Subscriber Destination is where events are delivered to. It's either a reference to an addressable resource, a URI, or a URI relative to the resource.
+	Subscriber Destination `json:"subscriber"`
+
+	// Subscription GroupKindNamespacedName is a struct that holds the group, kind, namespace, and name of a Kubernetes resource.
+	Subscription *GroupKindNamespacedName `json:"subscription,omitempty"`
+}
+
+// ResourceStatus ResourceStatus is the normalized status of a Kubernetes resource. It's taken from the `Ready` condition and the address of the resource. Not set when the resource doesn't report any status yet.
+type ResourceStatus struct {
+	// Address Address is the URL of the resource, i.e. the `status.address.url`, if the resource is addressable.
+	Address string `json:"address,omitempty"`
+
+	// LastTransitionTime LastTransitionTime is the last time the `Ready` condition transitioned from one status to another.
+	LastTransitionTime *time.Time `json:"lastTransitionTime,omitempty"`
+
+	// Message Message is the message of the `Ready` condition.
+	Message string `json:"message,omitempty"`
+
+	// Ready Ready is the status of the `Ready` condition, True, False or Unknown. It's Unknown when the resource has no `Ready` condition.
+	Ready string `json:"ready"`
+
+	// Reason Reason is the reason of the `Ready` condition.
+	Reason string `json:"reason,omitempty"`
+}
+
+// Sequence Sequence is a simplified representation of a Knative Flows Sequence that is easier to consume by the Backstage plugin. The events that are sent to a sequence go through its steps one after the other.
+type Sequence struct {
+	// Annotations Annotations of the sequence.
+	Annotations map[string]string `json:"annotations"`
+
+	// Labels Labels of the sequence.
+	Labels map[string]string `json:"labels"`
+
+	// Name Name of the sequence.
+	Name string `json:"name"`
+
+	// Namespace Namespace of the sequence.
+	Namespace string `json:"namespace"`
+
+	// ProvidedEventTypes List of the event types that are sent to the sequence, by triggers, subscriptions or as declared by the event types themselves.
+	ProvidedEventTypes []string `json:"providedEventTypes"`
+
+	// Reply Destination is where events are delivered to. It's either a reference to an addressable resource, a URI, or a URI relative to the resource.
+	Reply *Destination `json:"reply,omitempty"`
+
+	// Status ResourceStatus is the normalized status of a Kubernetes resource. It's taken from the `Ready` condition and the address of the resource. Not set when the resource doesn't report any status yet.
+	Status *ResourceStatus `json:"status,omitempty"`
+
+	// Steps Steps of the sequence, in order.
+	Steps []SequenceStep `json:"steps"`
+
+	// UID UID of the sequence.
+	UID string `json:"uid"`
+}
+
+// SequenceStep SequenceStep is a step of a sequence, along with the channel and the subscription that deliver the events to it.
+type SequenceStep struct {
+	// BackstageID Backstage ID of the subscriber of the step. Not set when the subscriber is not registered on Backstage, or when it forwards the events to other subscribers.
+	BackstageID string `json:"backstageId,omitempty"`
+
+	// Channel GroupKindNamespacedName is a struct that holds the group, kind, namespace, and name of a Kubernetes resource.
+	Channel *GroupKindNamespacedName `json:"channel,omitempty"`
+
+	// Delivery Delivery is the delivery spec, i.e. how the events are retried and where they are sent when they can't be delivered.
+	Delivery *Delivery `json:"delivery,omitempty"`
+
+	// ProvidedEventTypes List of the event types that reach the step. The event types that are sent to the sequence are assumed to flow through all of its steps.
+	ProvidedEventTypes []string `json:"providedEventTypes"`
+
+	// Subscriber Destination is where events are delivered to. It's either a reference to an addressable resource, a URI, or a URI relative to the resource.
+	Subscriber Destination `json:"subscriber"`
+
+	// Subscription GroupKindNamespacedName is a struct that holds the group, kind, namespace, and name of a Kubernetes resource.
+	Subscription *GroupKindNamespacedName `json:"subscription,omitempty"`
+}
+
+// Sink Sink is a simplified representation of a Knative Eventing sink, e.g. a JobSink, an IntegrationSink or a KafkaSink, that is easier to consume by the Backstage plugin. Sinks are where the events end up, they don't forward them any further.
+type Sink struct {
+	// Address Address of the sink, where the events are sent to. Empty if the sink isn't addressable yet.
+	Address string `json:"address,omitempty"`
+
+	// Annotations Annotations of the sink.
+	Annotations map[string]string `json:"annotations"`
+
+	// BackstageID Backstage ID of the sink. Empty if the sink isn't in Backstage.
+	BackstageID string `json:"backstageId,omitempty"`
+
+	// ConsumedEventTypes List of EventTypes that are sent to the sink through the triggers and the subscriptions. These are the `<namespace/name>` of the EventTypes.
+	ConsumedEventTypes []string `json:"consumedEventTypes"`
+
+	// Group Kubernetes API group of the sink, without the version.
+	Group string `json:"group"`
+
+	// Kind Kubernetes API kind of the sink.
+	Kind string `json:"kind"`
+
+	// Labels Labels of the sink.
+	Labels map[string]string `json:"labels"`
+
+	// Name Name of the sink.
+	Name string `json:"name"`
+
+	// Namespace Namespace of the sink.
+	Namespace string `json:"namespace"`
+
+	// Status ResourceStatus is the normalized status of a Kubernetes resource. It's taken from the `Ready` condition and the address of the resource. Not set when the resource doesn't report any status yet.
+	Status *ResourceStatus `json:"status,omitempty"`
+
+	// UID UID of the sink.
+	UID string `json:"uid"`
+}
+
 // Source Source is a simplified representation of a Knative Eventing Source that is easier to consume by the Backstage plugin.
 type Source struct {
 	// Annotations Annotations of the source.
@@ -124,6 +509,15 @@ type Source struct {
 	// Sink GroupKindNamespacedName is a struct that holds the group, kind, namespace, and name of a Kubernetes resource.
 	Sink *GroupKindNamespacedName `json:"sink,omitempty"`
 
+	// SinkBackstageID Backstage ID of the sink, if the sink consumes the events itself. Empty for the brokers, channels, sequences and parallels, which forward the events to their subscribers.
+	SinkBackstageID string `json:"sinkBackstageId,omitempty"`
+
+	// SinkURI URI that the events of the source are sent to. This is the resolved URI from the status of the source, or the URI in the spec if the source isn't resolved yet. Sinks that are only given by a URI are linked in `sink` to the broker, channel, sink or service with that address, if there's one.
+	SinkURI string `json:"sinkUri,omitempty"`
+
+	// Status ResourceStatus is the normalized status of a Kubernetes resource. It's taken from the `Ready` condition and the address of the resource. Not set when the resource doesn't report any status yet.
+	Status *ResourceStatus `json:"status,omitempty"`
+
 	// UID UID of the source.
 	UID string `json:"uid"`
 }
@@ -151,6 +545,168 @@ type Subscribable struct {
 	// ProvidedEventTypes List of event types provided by the subscribable.
 	ProvidedEventTypes []string `json:"providedEventTypes"`
 
+	// Status ResourceStatus is the normalized status of a Kubernetes resource. It's taken from the `Ready` condition and the address of the resource. Not set when the resource doesn't report any status yet.
+	Status *ResourceStatus `json:"status,omitempty"`
+
 	// UID UID of the subscribable.
 	UID string `json:"uid"`
 }
+
+// Subscription Subscription is a simplified representation of a Knative Messaging Subscription that is easier to consume by the Backstage plugin.
+type Subscription struct {
+	// BackstageID BackstageID is the Backstage ID of the subscriber, if the subscriber is registered in Backstage.
+	BackstageID string `json:"backstageId,omitempty"`
+
+	// Channel GroupKindNamespacedName is a struct that holds the group, kind, namespace, and name of a Kubernetes resource.
+	Channel GroupKindNamespacedName `json:"channel"`
+
+	// Conditions Conditions are the status conditions of the subscription.
+	Conditions []Condition `json:"conditions"`
+
+	// Delivery Delivery is the delivery spec, i.e. how the events are retried and where they are sent when they can't be delivered.
+	Delivery *Delivery `json:"delivery,omitempty"`
+
+	// Name Name of the subscription.
+	Name string `json:"name"`
+
+	// Namespace Namespace of the subscription.
+	Namespace string `json:"namespace"`
+
+	// Reply Destination is where events are delivered to. It's either a reference to an addressable resource, a URI, or a URI relative to the resource.
+	Reply *Destination `json:"reply,omitempty"`
+
+	// ReplyURI ReplyURI is the resolved URI of the reply, i.e. the `status.physicalSubscription.replyUri`.
+	ReplyURI string `json:"replyUri,omitempty"`
+
+	// Subscriber Destination is where events are delivered to. It's either a reference to an addressable resource, a URI, or a URI relative to the resource.
+	Subscriber *Destination `json:"subscriber,omitempty"`
+
+	// SubscriberURI SubscriberURI is the resolved URI of the subscriber, i.e. the `status.physicalSubscription.subscriberUri`.
+	SubscriberURI string `json:"subscriberUri,omitempty"`
+
+	// UID UID of the subscription.
+	UID string `json:"uid"`
+}
+
+// SubscriptionsAPIFilter SubscriptionsAPIFilter is a filter of the CloudEvents Subscriptions API. Only one of the fields is set.
+type SubscriptionsAPIFilter struct {
+	// All All of the filters must match.
+	All []SubscriptionsAPIFilter `json:"all,omitempty"`
+
+	// Any Any of the filters must match.
+	Any []SubscriptionsAPIFilter `json:"any,omitempty"`
+
+	// CESQL CESQL expression that must evaluate to true.
+	CESQL string `json:"cesql,omitempty"`
+
+	// Exact The attributes must be equal to the values.
+	Exact map[string]string `json:"exact,omitempty"`
+
+	// Not SubscriptionsAPIFilter is a filter of the CloudEvents Subscriptions API. Only one of the fields is set.
+	Not *SubscriptionsAPIFilter `json:"not,omitempty"`
+
+	// Prefix The attributes must start with the values.
+	Prefix map[string]string `json:"prefix,omitempty"`
+
+	// Suffix The attributes must end with the values.
+	Suffix map[string]string `json:"suffix,omitempty"`
+}
+
+// Trigger Trigger is a simplified representation of a Knative Eventing Trigger that is easier to consume by the Backstage plugin.
+type Trigger struct {
+	// BackstageID BackstageID is the Backstage ID of the subscriber, if the subscriber is registered in Backstage.
+	BackstageID string `json:"backstageId,omitempty"`
+
+	// Broker GroupKindNamespacedName is a struct that holds the group, kind, namespace, and name of a Kubernetes resource.
+	Broker GroupKindNamespacedName `json:"broker"`
+
+	// Conditions Conditions are the status conditions of the trigger.
+	Conditions []Condition `json:"conditions"`
+
+	// Delivery Delivery is the delivery spec, i.e. how the events are retried and where they are sent when they can't be delivered.
+	Delivery *Delivery `json:"delivery,omitempty"`
+
+	// Filter Filter is the attribute filter of the trigger, i.e. the `spec.filter.attributes`.
+	Filter map[string]string `json:"filter,omitempty"`
+
+	// Filters Filters are the Subscriptions API filters of the trigger, i.e. the `spec.filters`. When set, they take precedence over the attribute filter.
+	Filters []SubscriptionsAPIFilter `json:"filters,omitempty"`
+
+	// Name Name of the trigger.
+	Name string `json:"name"`
+
+	// Namespace Namespace of the trigger.
+	Namespace string `json:"namespace"`
+
+	// Subscriber Destination is where events are delivered to. It's either a reference to an addressable resource, a URI, or a URI relative to the resource.
+	Subscriber Destination `json:"subscriber"`
+
+	// SubscriberURI SubscriberURI is the resolved URI of the subscriber, i.e. the `status.subscriberUri`.
+	SubscriberURI string `json:"subscriberUri,omitempty"`
+
+	// UID UID of the trigger.
+	UID string `json:"uid"`
+}
+
+// Warning Warning describes a resource, or a kind of resources, that couldn't be processed while building the event mesh.
+type Warning struct {
+	// Group API group of the resource.
+	Group string `json:"group,omitempty"`
+
+	// Kind Kind of the resource.
+	Kind string `json:"kind"`
+
+	// Name Name of the resource. Empty when the warning is not about a single resource.
+	Name string `json:"name,omitempty"`
+
+	// Namespace Namespace of the resource. Empty when the warning is not about a single resource, e.g. when a kind of resources couldn't be listed.
+	Namespace string `json:"namespace,omitempty"`
+
+	// Reason Reason describes what went wrong.
+	Reason string `json:"reason"`
+
+	// StatusClass StatusClass is the class of the HTTP status that the Kubernetes API server responded with, e.g. 4xx or 5xx. Empty when the problem is not caused by an API call.
+	StatusClass string `json:"statusClass,omitempty"`
+}
+
+// GetEventMeshParams defines parameters for GetEventMesh.
+type GetEventMeshParams struct {
+	// Namespaces Namespaces to restrict the EventMesh to. When not set, the EventMesh is built from all namespaces.
+	Namespaces []string `form:"namespaces,omitempty" json:"namespaces,omitempty"`
+
+	// Kinds Lists of the EventMesh to return. When not set, all of them are returned. The other lists are returned empty.
+	Kinds []EventMeshKind `form:"kinds,omitempty" json:"kinds,omitempty"`
+
+	// Limit Maximum number of objects to return, counted across the lists of the EventMesh. When there are more, the EventMesh has a continue token to fetch the next page with. When not set, all objects are returned.
+	Limit int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Continue Continue token of the previous page, to fetch the next page of the EventMesh with.
+	Continue string `form:"continue,omitempty" json:"continue,omitempty"`
+
+	// IfNoneMatch ETag of an EventMesh that the caller has already. When it's still current, the EventMesh isn't sent again.
+	IfNoneMatch *string `json:"If-None-Match,omitempty"`
+}
+
+// GetNamespacedEventMeshParams defines parameters for GetNamespacedEventMesh.
+type GetNamespacedEventMeshParams struct {
+	// Kinds Lists of the EventMesh to return. When not set, all of them are returned. The other lists are returned empty.
+	Kinds []EventMeshKind `form:"kinds,omitempty" json:"kinds,omitempty"`
+
+	// Limit Maximum number of objects to return, counted across the lists of the EventMesh. When there are more, the EventMesh has a continue token to fetch the next page with. When not set, all objects are returned.
+	Limit int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Continue Continue token of the previous page, to fetch the next page of the EventMesh with.
+	Continue string `form:"continue,omitempty" json:"continue,omitempty"`
+
+	// IfNoneMatch ETag of an EventMesh that the caller has already. When it's still current, the EventMesh isn't sent again.
+	IfNoneMatch *string `json:"If-None-Match,omitempty"`
+}
+
+// WatchEventMeshParams defines parameters for WatchEventMesh.
+type WatchEventMeshParams struct {
+	// Namespaces Namespaces to restrict the EventMesh to. When not set, the EventMesh is built from all namespaces.
+	Namespaces []string `form:"namespaces,omitempty" json:"namespaces,omitempty"`
+
+	// ResourceVersion The resourceVersion of the last event that was received, to resume the stream from.
+	ResourceVersion string `form:"resourceVersion,omitempty" json:"resourceVersion,omitempty"`
+}
@@ -0,0 +1,409 @@
+package v1
+
+import (
+	"slices"
+	"sort"
+
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	eventingv1beta2 "knative.dev/eventing/pkg/apis/eventing/v1beta2"
+	flowsv1 "knative.dev/eventing/pkg/apis/flows/v1"
+	messagingv1 "knative.dev/eventing/pkg/apis/messaging/v1"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// staticKindResources maps the kinds that the event mesh is always built from to their resources.
+var staticKindResources = map[schema.GroupKind]schema.GroupResource{
+	eventingv1.Kind("Broker"):         brokersGVR.GroupResource(),
+	eventingv1beta2.Kind("EventType"): eventTypesGVR.GroupResource(),
+	eventingv1.Kind("Trigger"):        triggersGVR.GroupResource(),
+	messagingv1.Kind("Subscription"):  subscriptionsGVR.GroupResource(),
+	flowsv1.Kind("Sequence"):          sequencesGVR.GroupResource(),
+	flowsv1.Kind("Parallel"):          parallelsGVR.GroupResource(),
+}
+
+// meshResource is a resource that the events go through on the way to a consumer, or the consumer itself. A user
+// has to be able to list all of them to see the consumer.
+type meshResource struct {
+	gk        schema.GroupKind
+	namespace string
+}
+
+// consumerPath is one of the ways the events of an event type reach a consumer.
+type consumerPath struct {
+	// via are the resources on the way, from the trigger or the subscription that the events leave the broker or the
+	// channel of the event type through, to the consumer itself
+	via           []meshResource
+	indeterminate bool
+}
+
+// consumerPaths records the ways the events reach the consumers while the event mesh is built.
+type consumerPaths struct {
+	// paths are keyed by "<namespace>/<name>" of the event type, then by the consumer, see consumerKey
+	paths map[string]map[string][]consumerPath
+	// kindResources maps the kinds of the resources on the way, and of the resources that the event mesh refers to,
+	// to their resources
+	kindResources map[schema.GroupKind]schema.GroupResource
+}
+
+func newConsumerPaths() *consumerPaths {
+	return &consumerPaths{
+		paths:         make(map[string]map[string][]consumerPath),
+		kindResources: make(map[schema.GroupKind]schema.GroupResource),
+	}
+}
+
+// add records a way that the events of the event type reach the consumer through the hop.
+func (p *consumerPaths) add(et *EventType, hop *GroupKindNamespacedName, backstageId string, path consumerPath) {
+	if p == nil {
+		return
+	}
+	name := et.NamespacedName()
+	if p.paths[name] == nil {
+		p.paths[name] = make(map[string][]consumerPath)
+	}
+	key := consumerKey(backstageId, hop)
+	p.paths[name][key] = append(p.paths[name][key], path)
+}
+
+// mapped returns whether the resource of the kind is known already.
+func (p *consumerPaths) mapped(gk schema.GroupKind) bool {
+	if _, ok := staticKindResources[gk]; ok {
+		return true
+	}
+	_, ok := p.kindResources[gk]
+	return ok
+}
+
+// consumerKey identifies a consumer of an event type, the same way the consumers are merged in normalizeConsumers.
+func consumerKey(backstageId string, hop *GroupKindNamespacedName) string {
+	return backstageId + "|" + hopKey(hop)
+}
+
+// listAccess tells which resources a user can list in which namespaces.
+type listAccess interface {
+	CanList(gr schema.GroupResource, namespace string) bool
+}
+
+// namespacedAccess restricts an access to the given namespaces.
+type namespacedAccess struct {
+	access     listAccess
+	namespaces map[string]bool
+}
+
+func newNamespacedAccess(access listAccess, namespaces []string) namespacedAccess {
+	a := namespacedAccess{
+		access:     access,
+		namespaces: make(map[string]bool, len(namespaces)),
+	}
+	for _, ns := range namespaces {
+		a.namespaces[ns] = true
+	}
+	return a
+}
+
+func (a namespacedAccess) CanList(gr schema.GroupResource, namespace string) bool {
+	return a.namespaces[namespace] && a.access.CanList(gr, namespace)
+}
+
+// resources returns the resources that the event mesh is built from, which a user needs to be able to list to see
+// the corresponding parts of the event mesh.
+func (s *meshSnapshot) resources() []schema.GroupResource {
+	resources := []schema.GroupResource{
+		brokersGVR.GroupResource(),
+		eventTypesGVR.GroupResource(),
+		triggersGVR.GroupResource(),
+		subscriptionsGVR.GroupResource(),
+		sequencesGVR.GroupResource(),
+		parallelsGVR.GroupResource(),
+	}
+
+	seen := make(map[schema.GroupResource]bool, len(resources))
+	for _, gr := range resources {
+		seen[gr] = true
+	}
+	for _, gr := range s.kindResources {
+		if !seen[gr] {
+			seen[gr] = true
+			resources = append(resources, gr)
+		}
+	}
+	return resources
+}
+
+// namespaces returns the namespaces that have resources in the event mesh.
+func (s *meshSnapshot) namespaces() []string {
+	seen := make(map[string]bool)
+	for _, br := range s.eventMesh.Brokers {
+		seen[br.Namespace] = true
+	}
+	for _, et := range s.eventMesh.EventTypes {
+		seen[et.Namespace] = true
+	}
+	for _, sb := range s.eventMesh.Subscribables {
+		seen[sb.Namespace] = true
+	}
+	for _, src := range s.eventMesh.Sources {
+		seen[src.Namespace] = true
+	}
+	for _, sk := range s.eventMesh.Sinks {
+		seen[sk.Namespace] = true
+	}
+	for _, tr := range s.eventMesh.Triggers {
+		seen[tr.Namespace] = true
+	}
+	for _, sub := range s.eventMesh.Subscriptions {
+		seen[sub.Namespace] = true
+	}
+	for _, sq := range s.eventMesh.Sequences {
+		seen[sq.Namespace] = true
+	}
+	for _, p := range s.eventMesh.Parallels {
+		seen[p.Namespace] = true
+	}
+
+	namespaces := make([]string, 0, len(seen))
+	for ns := range seen {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+// meshVisibility tells which resources a user with the given access can see.
+type meshVisibility struct {
+	kindResources map[schema.GroupKind]schema.GroupResource
+	access        listAccess
+}
+
+// visible returns whether the user can list the resources of the kind in the namespace. The kinds whose resources
+// aren't known are not visible to anyone.
+func (v meshVisibility) visible(gk schema.GroupKind, namespace string) bool {
+	gr, ok := staticKindResources[gk]
+	if !ok {
+		gr, ok = v.kindResources[gk]
+	}
+	return ok && v.access.CanList(gr, namespace)
+}
+
+// visibleRef returns whether the user can see the referenced resource.
+func (v meshVisibility) visibleRef(ref *GroupKindNamespacedName) bool {
+	return ref != nil && v.visible(ref.GroupKind(), ref.Namespace)
+}
+
+// visiblePath returns whether the user can see all the resources on the way to a consumer.
+func (v meshVisibility) visiblePath(path consumerPath) bool {
+	for _, r := range path.via {
+		if !v.visible(r.gk, r.namespace) {
+			return false
+		}
+	}
+	return true
+}
+
+// filterEventMesh returns the part of the event mesh that a user with the given access is allowed to see.
+// The consumers are only visible if the user can list all the resources on the way to them, see consumerPaths.
+func filterEventMesh(eventMesh EventMesh, kindResources map[schema.GroupKind]schema.GroupResource, paths *consumerPaths, access listAccess) EventMesh {
+	v := meshVisibility{kindResources: kindResources, access: access}
+
+	visibleEventTypes := make(map[string]bool)
+	eventTypes := make([]EventType, 0, len(eventMesh.EventTypes))
+	for _, et := range eventMesh.EventTypes {
+		if !access.CanList(eventTypesGVR.GroupResource(), et.Namespace) {
+			continue
+		}
+		visibleEventTypes[et.NamespacedName()] = true
+
+		var etPaths map[string][]consumerPath
+		if paths != nil {
+			etPaths = paths.paths[et.NamespacedName()]
+		}
+		eventTypes = append(eventTypes, filterConsumers(et, etPaths, v))
+	}
+
+	brokers := make([]Broker, 0, len(eventMesh.Brokers))
+	for _, br := range eventMesh.Brokers {
+		if !access.CanList(brokersGVR.GroupResource(), br.Namespace) {
+			continue
+		}
+		br.ProvidedEventTypes = filterVisible(br.ProvidedEventTypes, visibleEventTypes)
+		brokers = append(brokers, br)
+	}
+
+	subscribables := make([]Subscribable, 0, len(eventMesh.Subscribables))
+	for _, sb := range eventMesh.Subscribables {
+		gr, ok := kindResources[schema.GroupKind{Group: sb.Group, Kind: sb.Kind}]
+		if !ok || !access.CanList(gr, sb.Namespace) {
+			continue
+		}
+		sb.ProvidedEventTypes = filterVisible(sb.ProvidedEventTypes, visibleEventTypes)
+		subscribables = append(subscribables, sb)
+	}
+
+	sources := make([]Source, 0, len(eventMesh.Sources))
+	for _, src := range eventMesh.Sources {
+		gr, ok := kindResources[schema.GroupKind{Group: src.Group, Kind: src.Kind}]
+		if !ok || !access.CanList(gr, src.Namespace) {
+			continue
+		}
+		src.ProvidedEventTypes = filterVisible(src.ProvidedEventTypes, visibleEventTypes)
+		if src.Sink != nil && !v.visibleRef(src.Sink) {
+			src.Sink = nil
+			src.SinkBackstageID = ""
+		}
+		sources = append(sources, src)
+	}
+
+	sinks := make([]Sink, 0, len(eventMesh.Sinks))
+	for _, sk := range eventMesh.Sinks {
+		gr, ok := kindResources[schema.GroupKind{Group: sk.Group, Kind: sk.Kind}]
+		if !ok || !access.CanList(gr, sk.Namespace) {
+			continue
+		}
+		sk.ConsumedEventTypes = filterVisible(sk.ConsumedEventTypes, visibleEventTypes)
+		sinks = append(sinks, sk)
+	}
+
+	triggers := make([]Trigger, 0, len(eventMesh.Triggers))
+	for _, tr := range eventMesh.Triggers {
+		if !access.CanList(triggersGVR.GroupResource(), tr.Namespace) {
+			continue
+		}
+		tr.BackstageID = v.visibleBackstageID(tr.BackstageID, tr.Subscriber.Ref)
+		triggers = append(triggers, tr)
+	}
+
+	subscriptions := make([]Subscription, 0, len(eventMesh.Subscriptions))
+	for _, sub := range eventMesh.Subscriptions {
+		if !access.CanList(subscriptionsGVR.GroupResource(), sub.Namespace) {
+			continue
+		}
+		if sub.Subscriber != nil {
+			sub.BackstageID = v.visibleBackstageID(sub.BackstageID, sub.Subscriber.Ref)
+		}
+		subscriptions = append(subscriptions, sub)
+	}
+
+	sequences := make([]Sequence, 0, len(eventMesh.Sequences))
+	for _, sq := range eventMesh.Sequences {
+		if !access.CanList(sequencesGVR.GroupResource(), sq.Namespace) {
+			continue
+		}
+		sq.ProvidedEventTypes = filterVisible(sq.ProvidedEventTypes, visibleEventTypes)
+		steps := make([]SequenceStep, 0, len(sq.Steps))
+		for _, step := range sq.Steps {
+			step.ProvidedEventTypes = filterVisible(step.ProvidedEventTypes, visibleEventTypes)
+			step.BackstageID = v.visibleBackstageID(step.BackstageID, step.Subscriber.Ref)
+			steps = append(steps, step)
+		}
+		sq.Steps = steps
+		sequences = append(sequences, sq)
+	}
+
+	parallels := make([]Parallel, 0, len(eventMesh.Parallels))
+	for _, p := range eventMesh.Parallels {
+		if !access.CanList(parallelsGVR.GroupResource(), p.Namespace) {
+			continue
+		}
+		p.ProvidedEventTypes = filterVisible(p.ProvidedEventTypes, visibleEventTypes)
+		p.Branches = slices.Clone(p.Branches)
+		for i := range p.Branches {
+			p.Branches[i].BackstageID = v.visibleBackstageID(p.Branches[i].BackstageID, p.Branches[i].Subscriber.Ref)
+		}
+		parallels = append(parallels, p)
+	}
+
+	var warnings []Warning
+	for _, w := range eventMesh.Warnings {
+		// warnings about a kind of resources don't reveal any resources, but the ones about a single resource are
+		// only visible to the users who can see that resource
+		if w.Namespace != "" && !v.visible(schema.GroupKind{Group: w.Group, Kind: w.Kind}, w.Namespace) {
+			continue
+		}
+		warnings = append(warnings, w)
+	}
+
+	return EventMesh{
+		Brokers:       brokers,
+		EventTypes:    eventTypes,
+		Subscribables: subscribables,
+		Sources:       sources,
+		Sinks:         sinks,
+		Triggers:      triggers,
+		Subscriptions: subscriptions,
+		Sequences:     sequences,
+		Parallels:     parallels,
+		Warnings:      warnings,
+		// the kinds don't reveal any resources
+		DiscoveredKinds: eventMesh.DiscoveredKinds,
+	}
+}
+
+// filterConsumers returns the event type with the consumers that the user can see all the way to.
+func filterConsumers(et EventType, paths map[string][]consumerPath, v meshVisibility) EventType {
+	if len(et.Consumers) == 0 {
+		return et
+	}
+
+	consumers := make([]EventTypeConsumer, 0, len(et.Consumers))
+	consumedBy := make([]string, 0, len(et.Consumers))
+	determinate := make(map[string]bool)
+	for _, c := range et.Consumers {
+		visible, indeterminate := false, true
+		for _, path := range paths[consumerKey(c.BackstageID, c.Hop)] {
+			if v.visiblePath(path) {
+				visible = true
+				indeterminate = indeterminate && path.indeterminate
+			}
+		}
+		if !visible {
+			continue
+		}
+
+		c.Indeterminate = indeterminate
+		consumers = append(consumers, c)
+		consumedBy = append(consumedBy, c.BackstageID)
+		if !indeterminate {
+			determinate[c.BackstageID] = true
+		}
+	}
+
+	var indeterminateConsumedBy []string
+	for _, c := range consumers {
+		if !determinate[c.BackstageID] {
+			indeterminateConsumedBy = append(indeterminateConsumedBy, c.BackstageID)
+		}
+	}
+
+	et.ConsumedBy = sortedUnique(consumedBy)
+	et.IndeterminateConsumedBy = sortedUnique(indeterminateConsumedBy)
+	et.Consumers = consumers
+	if len(consumers) == 0 {
+		et.Consumers = nil
+	}
+	return et
+}
+
+// visibleBackstageID returns the Backstage ID of the subscriber if the user can see the subscriber.
+func (v meshVisibility) visibleBackstageID(backstageId string, ref *GroupKindNamespacedName) string {
+	if ref != nil && !v.visibleRef(ref) {
+		return ""
+	}
+	return backstageId
+}
+
+// filterVisible returns the event type references that point to visible event types.
+// map key: "<namespace>/<name>"
+func filterVisible(eventTypeRefs []string, visibleEventTypes map[string]bool) []string {
+	if eventTypeRefs == nil {
+		return nil
+	}
+
+	visible := make([]string, 0, len(eventTypeRefs))
+	for _, ref := range eventTypeRefs {
+		if visibleEventTypes[ref] {
+			visible = append(visible, ref)
+		}
+	}
+	return visible
+}
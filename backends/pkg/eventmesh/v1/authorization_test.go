@@ -0,0 +1,379 @@
+package v1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	messagingv1 "knative.dev/eventing/pkg/apis/messaging/v1"
+	fakeclientset "knative.dev/eventing/pkg/client/clientset/versioned/fake"
+	testingv1 "knative.dev/eventing/pkg/reconciler/testing/v1"
+	testingv1beta2 "knative.dev/eventing/pkg/reconciler/testing/v1beta2"
+)
+
+// fakeAccess allows listing the resources in the namespaces, where "" is for all namespaces.
+type fakeAccess map[string][]string
+
+func (a fakeAccess) CanList(gr schema.GroupResource, namespace string) bool {
+	for _, ns := range []string{"", namespace} {
+		for _, resource := range a[ns] {
+			if resource == gr.Resource {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func TestFilterEventMesh(t *testing.T) {
+	kindResources := map[schema.GroupKind]schema.GroupResource{
+		{Group: "sources.knative.dev", Kind: "PingSource"}:        {Group: "sources.knative.dev", Resource: "pingsources"},
+		{Group: "messaging.knative.dev", Kind: "InMemoryChannel"}: {Group: "messaging.knative.dev", Resource: "inmemorychannels"},
+		{Group: "sinks.knative.dev", Kind: "JobSink"}:             {Group: "sinks.knative.dev", Resource: "jobsinks"},
+		{Kind: "Service"}: {Resource: "services"},
+	}
+
+	var (
+		trigger1     = meshResource{gk: eventingv1.Kind("Trigger"), namespace: "ns-1"}
+		trigger2     = meshResource{gk: eventingv1.Kind("Trigger"), namespace: "ns-2"}
+		subscription = meshResource{gk: messagingv1.Kind("Subscription"), namespace: "ns-1"}
+		service1     = meshResource{gk: schema.GroupKind{Kind: "Service"}, namespace: "ns-1"}
+		service2     = meshResource{gk: schema.GroupKind{Kind: "Service"}, namespace: "ns-2"}
+		// consumer-5 is reached through a channel in another namespace
+		channel3      = meshResource{gk: schema.GroupKind{Group: "messaging.knative.dev", Kind: "InMemoryChannel"}, namespace: "ns-3"}
+		subscription3 = meshResource{gk: messagingv1.Kind("Subscription"), namespace: "ns-3"}
+		service3      = meshResource{gk: schema.GroupKind{Kind: "Service"}, namespace: "ns-3"}
+
+		hop1 = &GroupKindNamespacedName{Kind: "Service", Namespace: "ns-1", Name: "consumer-1"}
+		hop2 = &GroupKindNamespacedName{Kind: "Service", Namespace: "ns-2", Name: "consumer-2"}
+		hop3 = &GroupKindNamespacedName{Kind: "Service", Namespace: "ns-1", Name: "consumer-3"}
+		hop5 = &GroupKindNamespacedName{Group: "messaging.knative.dev", Kind: "InMemoryChannel", Namespace: "ns-3", Name: "channel"}
+	)
+
+	eventMesh := EventMesh{
+		Brokers: []Broker{
+			{Namespace: "ns-1", Name: "broker", ProvidedEventTypes: []string{"ns-1/et-1"}},
+			{Namespace: "ns-2", Name: "broker", ProvidedEventTypes: []string{"ns-2/et-2"}},
+		},
+		EventTypes: []EventType{
+			{
+				Namespace:               "ns-1",
+				Name:                    "et-1",
+				Reference:               &GroupKindNamespacedName{Group: "eventing.knative.dev", Kind: "Broker", Namespace: "ns-1", Name: "broker"},
+				ConsumedBy:              []string{"consumer-1", "consumer-5"},
+				IndeterminateConsumedBy: []string{"consumer-1"},
+				Consumers: []EventTypeConsumer{
+					{BackstageID: "consumer-1", Hop: hop1, Indeterminate: true},
+					{BackstageID: "consumer-5", Hop: hop5},
+				},
+			},
+			{
+				Namespace:  "ns-2",
+				Name:       "et-2",
+				Reference:  &GroupKindNamespacedName{Group: "eventing.knative.dev", Kind: "Broker", Namespace: "ns-2", Name: "broker"},
+				ConsumedBy: []string{"consumer-2"},
+				Consumers:  []EventTypeConsumer{{BackstageID: "consumer-2", Hop: hop2}},
+			},
+			{
+				Namespace:  "ns-1",
+				Name:       "et-3",
+				Reference:  &GroupKindNamespacedName{Group: "messaging.knative.dev", Kind: "InMemoryChannel", Namespace: "ns-1", Name: "channel"},
+				ConsumedBy: []string{"consumer-3"},
+				Consumers:  []EventTypeConsumer{{BackstageID: "consumer-3", Hop: hop3}},
+			},
+		},
+		Subscribables: []Subscribable{
+			{Namespace: "ns-1", Name: "channel", Group: "messaging.knative.dev", Kind: "InMemoryChannel", ProvidedEventTypes: []string{"ns-1/et-3"}},
+		},
+		Sources: []Source{
+			{
+				Namespace:          "ns-1",
+				Name:               "source",
+				Group:              "sources.knative.dev",
+				Kind:               "PingSource",
+				ProvidedEventTypes: []string{"ns-1/et-1", "ns-2/et-2"},
+				Sink:               &GroupKindNamespacedName{Kind: "Service", Namespace: "ns-3", Name: "consumer-5"},
+				SinkBackstageID:    "consumer-5",
+			},
+			{Namespace: "ns-1", Name: "unknown", Group: "example.com", Kind: "UnknownSource", ProvidedEventTypes: []string{}},
+		},
+		Sinks: []Sink{
+			{Namespace: "ns-1", Name: "sink", Group: "sinks.knative.dev", Kind: "JobSink", ConsumedEventTypes: []string{"ns-1/et-1", "ns-2/et-2"}},
+		},
+		Triggers: []Trigger{
+			{Namespace: "ns-1", Name: "trigger", Broker: GroupKindNamespacedName{Group: "eventing.knative.dev", Kind: "Broker", Namespace: "ns-1", Name: "broker"}, BackstageID: "consumer-1"},
+			{Namespace: "ns-2", Name: "trigger", Broker: GroupKindNamespacedName{Group: "eventing.knative.dev", Kind: "Broker", Namespace: "ns-2", Name: "broker"}, BackstageID: "consumer-2"},
+			{
+				Namespace:   "ns-1",
+				Name:        "cross-namespace",
+				Broker:      GroupKindNamespacedName{Group: "eventing.knative.dev", Kind: "Broker", Namespace: "ns-1", Name: "broker"},
+				Subscriber:  Destination{Ref: &GroupKindNamespacedName{Kind: "Service", Namespace: "ns-3", Name: "consumer-5"}},
+				BackstageID: "consumer-5",
+			},
+		},
+		Subscriptions: []Subscription{
+			{Namespace: "ns-1", Name: "subscription", Channel: GroupKindNamespacedName{Group: "messaging.knative.dev", Kind: "InMemoryChannel", Namespace: "ns-1", Name: "channel"}, BackstageID: "consumer-3"},
+		},
+		Sequences: []Sequence{
+			{
+				Namespace:          "ns-1",
+				Name:               "sequence",
+				ProvidedEventTypes: []string{"ns-1/et-1", "ns-2/et-2"},
+				Steps: []SequenceStep{
+					{BackstageID: "consumer-4", ProvidedEventTypes: []string{"ns-1/et-1", "ns-2/et-2"}},
+					{BackstageID: "consumer-5", ProvidedEventTypes: []string{"ns-1/et-1", "ns-2/et-2"}, Subscriber: Destination{Ref: &GroupKindNamespacedName{Kind: "Service", Namespace: "ns-3", Name: "consumer-5"}}},
+				},
+			},
+		},
+		Parallels: []Parallel{
+			{Namespace: "ns-2", Name: "parallel", ProvidedEventTypes: []string{"ns-2/et-2"}},
+		},
+		Warnings: []Warning{
+			{Group: "sources.knative.dev", Kind: "PingSource", Reason: "error listing pingsources.sources.knative.dev", StatusClass: "5xx"},
+			{Group: "eventing.knative.dev", Kind: "Trigger", Namespace: "ns-2", Name: "trigger", Reason: "error getting subscriber backstage id"},
+			{Group: "example.com", Kind: "UnknownSource", Namespace: "ns-1", Name: "unknown", Reason: "failed to unmarshal the event types"},
+		},
+	}
+
+	et1, et2, et3 := &eventMesh.EventTypes[0], &eventMesh.EventTypes[1], &eventMesh.EventTypes[2]
+	paths := newConsumerPaths()
+	paths.add(et1, hop1, "consumer-1", consumerPath{via: []meshResource{trigger1, service1}, indeterminate: true})
+	paths.add(et1, hop5, "consumer-5", consumerPath{via: []meshResource{trigger1, channel3, subscription3, service3}})
+	paths.add(et2, hop2, "consumer-2", consumerPath{via: []meshResource{trigger2, service2}})
+	paths.add(et3, hop3, "consumer-3", consumerPath{via: []meshResource{subscription, service1}})
+
+	tests := []struct {
+		name   string
+		access listAccess
+		want   EventMesh
+	}{
+		{
+			name: "everything",
+			access: fakeAccess{
+				"": {"brokers", "eventtypes", "triggers", "subscriptions", "sequences", "parallels", "pingsources", "inmemorychannels", "jobsinks", "services"},
+			},
+			want: EventMesh{
+				Brokers:       eventMesh.Brokers,
+				EventTypes:    eventMesh.EventTypes,
+				Subscribables: eventMesh.Subscribables,
+				// sources of unknown kinds can't be authorized
+				Sources:       eventMesh.Sources[:1],
+				Sinks:         eventMesh.Sinks,
+				Triggers:      eventMesh.Triggers,
+				Subscriptions: eventMesh.Subscriptions,
+				Sequences:     eventMesh.Sequences,
+				Parallels:     eventMesh.Parallels,
+				Warnings:      eventMesh.Warnings[:2],
+			},
+		},
+		{
+			name:   "nothing",
+			access: fakeAccess{},
+			want: EventMesh{
+				Brokers:       []Broker{},
+				EventTypes:    []EventType{},
+				Subscribables: []Subscribable{},
+				Sources:       []Source{},
+				Sinks:         []Sink{},
+				Triggers:      []Trigger{},
+				Subscriptions: []Subscription{},
+				Sequences:     []Sequence{},
+				Parallels:     []Parallel{},
+				// warnings about a kind of resources are visible to everyone
+				Warnings: eventMesh.Warnings[:1],
+			},
+		},
+		{
+			name: "single namespace without triggers",
+			access: fakeAccess{
+				"ns-1": {"brokers", "eventtypes", "subscriptions", "sequences", "pingsources", "jobsinks", "services"},
+			},
+			want: EventMesh{
+				Brokers: []Broker{
+					{Namespace: "ns-1", Name: "broker", ProvidedEventTypes: []string{"ns-1/et-1"}},
+				},
+				EventTypes: []EventType{
+					{
+						Namespace:  "ns-1",
+						Name:       "et-1",
+						Reference:  &GroupKindNamespacedName{Group: "eventing.knative.dev", Kind: "Broker", Namespace: "ns-1", Name: "broker"},
+						ConsumedBy: []string{},
+					},
+					eventMesh.EventTypes[2],
+				},
+				Subscribables: []Subscribable{},
+				Sinks: []Sink{
+					{Namespace: "ns-1", Name: "sink", Group: "sinks.knative.dev", Kind: "JobSink", ConsumedEventTypes: []string{"ns-1/et-1"}},
+				},
+				Sources: []Source{
+					{Namespace: "ns-1", Name: "source", Group: "sources.knative.dev", Kind: "PingSource", ProvidedEventTypes: []string{"ns-1/et-1"}},
+				},
+				Triggers:      []Trigger{},
+				Subscriptions: eventMesh.Subscriptions,
+				Sequences: []Sequence{
+					{
+						Namespace:          "ns-1",
+						Name:               "sequence",
+						ProvidedEventTypes: []string{"ns-1/et-1"},
+						Steps: []SequenceStep{
+							{BackstageID: "consumer-4", ProvidedEventTypes: []string{"ns-1/et-1"}},
+							{ProvidedEventTypes: []string{"ns-1/et-1"}, Subscriber: Destination{Ref: &GroupKindNamespacedName{Kind: "Service", Namespace: "ns-3", Name: "consumer-5"}}},
+						},
+					},
+				},
+				Parallels: []Parallel{},
+				Warnings:  eventMesh.Warnings[:1],
+			},
+		},
+		{
+			name: "consumers in a namespace that can't be seen",
+			access: fakeAccess{
+				"ns-1": {"brokers", "eventtypes", "triggers", "subscriptions", "sequences", "pingsources", "inmemorychannels", "jobsinks", "services"},
+				// the channel and the subscription to consumer-5 can be seen, consumer-5 itself can't
+				"ns-3": {"inmemorychannels", "subscriptions"},
+			},
+			want: EventMesh{
+				Brokers: []Broker{
+					{Namespace: "ns-1", Name: "broker", ProvidedEventTypes: []string{"ns-1/et-1"}},
+				},
+				EventTypes: []EventType{
+					{
+						Namespace:               "ns-1",
+						Name:                    "et-1",
+						Reference:               &GroupKindNamespacedName{Group: "eventing.knative.dev", Kind: "Broker", Namespace: "ns-1", Name: "broker"},
+						ConsumedBy:              []string{"consumer-1"},
+						IndeterminateConsumedBy: []string{"consumer-1"},
+						Consumers:               []EventTypeConsumer{{BackstageID: "consumer-1", Hop: hop1, Indeterminate: true}},
+					},
+					eventMesh.EventTypes[2],
+				},
+				Subscribables: eventMesh.Subscribables,
+				Sinks: []Sink{
+					{Namespace: "ns-1", Name: "sink", Group: "sinks.knative.dev", Kind: "JobSink", ConsumedEventTypes: []string{"ns-1/et-1"}},
+				},
+				Sources: []Source{
+					{Namespace: "ns-1", Name: "source", Group: "sources.knative.dev", Kind: "PingSource", ProvidedEventTypes: []string{"ns-1/et-1"}},
+				},
+				Triggers: []Trigger{
+					eventMesh.Triggers[0],
+					{
+						Namespace:  "ns-1",
+						Name:       "cross-namespace",
+						Broker:     GroupKindNamespacedName{Group: "eventing.knative.dev", Kind: "Broker", Namespace: "ns-1", Name: "broker"},
+						Subscriber: Destination{Ref: &GroupKindNamespacedName{Kind: "Service", Namespace: "ns-3", Name: "consumer-5"}},
+					},
+				},
+				Subscriptions: eventMesh.Subscriptions,
+				Sequences: []Sequence{
+					{
+						Namespace:          "ns-1",
+						Name:               "sequence",
+						ProvidedEventTypes: []string{"ns-1/et-1"},
+						Steps: []SequenceStep{
+							{BackstageID: "consumer-4", ProvidedEventTypes: []string{"ns-1/et-1"}},
+							{ProvidedEventTypes: []string{"ns-1/et-1"}, Subscriber: Destination{Ref: &GroupKindNamespacedName{Kind: "Service", Namespace: "ns-3", Name: "consumer-5"}}},
+						},
+					},
+				},
+				Parallels: []Parallel{},
+				Warnings:  eventMesh.Warnings[:1],
+			},
+		},
+		{
+			name: "restricted to a namespace",
+			access: newNamespacedAccess(fakeAccess{
+				"": {"brokers", "eventtypes", "triggers", "subscriptions", "sequences", "parallels", "pingsources", "inmemorychannels", "jobsinks", "services"},
+			}, []string{"ns-2"}),
+			want: EventMesh{
+				Brokers:       eventMesh.Brokers[1:],
+				EventTypes:    eventMesh.EventTypes[1:2],
+				Subscribables: []Subscribable{},
+				Sources:       []Source{},
+				Sinks:         []Sink{},
+				Triggers:      eventMesh.Triggers[1:2],
+				Subscriptions: []Subscription{},
+				Sequences:     []Sequence{},
+				Parallels:     eventMesh.Parallels,
+				Warnings:      eventMesh.Warnings[:2],
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := filterEventMesh(eventMesh, kindResources, paths, tt.access)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Error("filterEventMesh() (-want, +got):", diff)
+			}
+		})
+	}
+
+	// the shared event mesh is not modified
+	if len(eventMesh.EventTypes[0].ConsumedBy) != 2 || len(eventMesh.EventTypes[0].Consumers) != 2 || eventMesh.Triggers[2].BackstageID == "" || len(eventMesh.Sources[0].ProvidedEventTypes) != 2 ||
+		len(eventMesh.Sequences[0].Steps[0].ProvidedEventTypes) != 2 {
+		t.Errorf("filterEventMesh() modified the given event mesh: %+v", eventMesh)
+	}
+}
+
+func TestBuildEventMeshRecordsConsumerPaths(t *testing.T) {
+	// the events go through a broker in another namespace to the consumer
+	subscriber := backstageService("test-subscriber")
+	subscriber.Namespace = "other-ns"
+
+	objects := []runtime.Object{
+		testingv1.NewBroker("test-broker", "test-ns"),
+		testingv1.NewBroker("other-broker", "other-ns"),
+		testingv1beta2.NewEventType("test-eventtype", "test-ns",
+			testingv1beta2.WithEventTypeReference(brokerReference("test-broker", "test-ns")),
+		),
+		testingv1.NewTrigger("test-trigger", "test-ns", "test-broker",
+			WithTriggerSubscriber(brokerReference("other-broker", "other-ns")),
+		),
+		testingv1.NewTrigger("other-trigger", "other-ns", "other-broker",
+			WithTriggerSubscriber(reference("v1", "Service", "other-ns", "test-subscriber")),
+		),
+	}
+
+	sc := runtime.NewScheme()
+	_ = corev1.AddToScheme(sc)
+	_ = apiextensionsv1.AddToScheme(sc)
+
+	lister := &clientLister{
+		clientset:     fakeclientset.NewSimpleClientset(objects...),
+		dynamicClient: dynamicfake.NewSimpleDynamicClient(sc, subscriber),
+	}
+
+	paths := newConsumerPaths()
+	if _, err := buildEventMesh(context.TODO(), lister, nil, nil, paths, zap.NewNop().Sugar()); err != nil {
+		t.Fatalf("buildEventMesh() error = %v", err)
+	}
+
+	hop := &GroupKindNamespacedName{Group: "eventing.knative.dev", Kind: "Broker", Namespace: "other-ns", Name: "other-broker"}
+	want := map[string]map[string][]consumerPath{
+		"test-ns/test-eventtype": {
+			consumerKey("test-subscriber", hop): {{
+				via: []meshResource{
+					{gk: eventingv1.Kind("Trigger"), namespace: "test-ns"},
+					{gk: eventingv1.Kind("Broker"), namespace: "other-ns"},
+					{gk: eventingv1.Kind("Trigger"), namespace: "other-ns"},
+					{gk: schema.GroupKind{Kind: "Service"}, namespace: "other-ns"},
+				},
+			}},
+		},
+	}
+	if diff := cmp.Diff(want, paths.paths, cmp.AllowUnexported(consumerPath{}, meshResource{})); diff != "" {
+		t.Error("consumer paths (-want, +got):", diff)
+	}
+
+	if got := paths.kindResources[schema.GroupKind{Kind: "Service"}]; got != (schema.GroupResource{Resource: "services"}) {
+		t.Errorf("resource of the services = %v, want services", got)
+	}
+}
@@ -0,0 +1,336 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/configmap"
+)
+
+const (
+	// ConfigMapName is the name of the ConfigMap that configures the event mesh backend.
+	ConfigMapName = "config-eventmesh"
+
+	// backstageIDAnnotationsKey lists the annotations of the subscribers whose values are used as Backstage IDs,
+	// separated by commas. They're checked in order, after the BackstageKubernetesIDLabel.
+	backstageIDAnnotationsKey = "backstage-id-annotations"
+	// backstageIDOwnerDepthKey is how many levels of owners of a subscriber are checked for the Backstage ID.
+	// 0 disables the owner reference walk.
+	backstageIDOwnerDepthKey = "backstage-id-owner-depth"
+	// backstageIDNamespaceLabelsKey enables using the BackstageKubernetesIDLabel of the namespace of a subscriber.
+	backstageIDNamespaceLabelsKey = "backstage-id-namespace-labels"
+	// backstageIDURIMappingsKey maps the URIs of the subscribers to Backstage IDs, as a YAML map.
+	backstageIDURIMappingsKey = "backstage-id-uri-mappings"
+)
+
+var namespacesGVR = corev1.SchemeGroupVersion.WithResource("namespaces")
+
+// BackstageIDConfig configures how the Backstage IDs of the subscribers are resolved.
+// The resolvers are tried in order, the first one that finds a Backstage ID wins:
+// - the BackstageKubernetesIDLabel of the subscriber
+// - the annotations of the subscriber
+// - the labels and annotations of the owners of the subscriber
+// - the BackstageKubernetesIDLabel of the namespace of the subscriber
+// - the URI mappings, for the subscribers that are referenced by a URI or whose address is known
+type BackstageIDConfig struct {
+	Annotations     []string
+	OwnerDepth      int
+	NamespaceLabels bool
+	URIMappings     map[string]string
+}
+
+// DefaultBackstageIDConfig returns the configuration that's used when the ConfigMap doesn't configure anything.
+// The annotations, the owners and the namespaces of the subscribers aren't looked at by default, they have to be
+// enabled.
+func DefaultBackstageIDConfig() *BackstageIDConfig {
+	return &BackstageIDConfig{
+		Annotations: []string{},
+		URIMappings: map[string]string{},
+	}
+}
+
+// NewBackstageIDConfigFromConfigMap creates a BackstageIDConfig from the ConfigMap, with the defaults for the keys
+// that are not set.
+func NewBackstageIDConfigFromConfigMap(cm *corev1.ConfigMap) (*BackstageIDConfig, error) {
+	config := DefaultBackstageIDConfig()
+
+	var annotations, uriMappings string
+	err := configmap.Parse(cm.Data,
+		configmap.AsString(backstageIDAnnotationsKey, &annotations),
+		configmap.AsInt(backstageIDOwnerDepthKey, &config.OwnerDepth),
+		configmap.AsBool(backstageIDNamespaceLabelsKey, &config.NamespaceLabels),
+		configmap.AsString(backstageIDURIMappingsKey, &uriMappings),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.OwnerDepth < 0 {
+		return nil, fmt.Errorf("%s must not be negative, got %d", backstageIDOwnerDepthKey, config.OwnerDepth)
+	}
+
+	if _, ok := cm.Data[backstageIDAnnotationsKey]; ok {
+		config.Annotations = []string{}
+		for _, annotation := range strings.Split(annotations, ",") {
+			if annotation = strings.TrimSpace(annotation); annotation != "" {
+				config.Annotations = append(config.Annotations, annotation)
+			}
+		}
+	}
+
+	if uriMappings != "" {
+		if err := yaml.Unmarshal([]byte(uriMappings), &config.URIMappings); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", backstageIDURIMappingsKey, err)
+		}
+	}
+
+	return config, nil
+}
+
+// BackstageIDConfigStore holds the current BackstageIDConfig, which is updated whenever its ConfigMap changes.
+type BackstageIDConfigStore struct {
+	logger *zap.SugaredLogger
+
+	lock     sync.RWMutex
+	config   *BackstageIDConfig
+	onChange []func()
+}
+
+func NewBackstageIDConfigStore(logger *zap.SugaredLogger) *BackstageIDConfigStore {
+	return &BackstageIDConfigStore{
+		logger: logger,
+		config: DefaultBackstageIDConfig(),
+	}
+}
+
+// Load returns the current configuration, or the defaults without a store.
+func (s *BackstageIDConfigStore) Load() *BackstageIDConfig {
+	if s == nil {
+		return DefaultBackstageIDConfig()
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.config
+}
+
+// OnConfigMapChanged is a configmap.Observer that updates the configuration. Invalid configurations are logged and
+// ignored, the previous configuration is kept.
+func (s *BackstageIDConfigStore) OnConfigMapChanged(cm *corev1.ConfigMap) {
+	config, err := NewBackstageIDConfigFromConfigMap(cm)
+	if err != nil {
+		s.logger.Errorw("Invalid Backstage ID configuration, keeping the previous one", "configmap", cm.Name, "error", err)
+		return
+	}
+
+	s.lock.Lock()
+	s.config = config
+	onChange := s.onChange
+	s.lock.Unlock()
+
+	s.logger.Infow("Updated Backstage ID configuration", "config", config)
+	for _, f := range onChange {
+		f()
+	}
+}
+
+// notify registers a function that's called whenever the configuration changes.
+func (s *BackstageIDConfigStore) notify(f func()) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.onChange = append(s.onChange, f)
+}
+
+// subscriber is a subscriber of a trigger or a subscription whose Backstage ID is resolved.
+type subscriber struct {
+	// ref is the reference to the subscriber, with the namespace defaulted. nil when the subscriber is a URI.
+	ref *duckv1.KReference
+	// object is the subscriber resource. nil when the subscriber is a URI or when the resource doesn't exist.
+	object *unstructured.Unstructured
+	// fetched is true when the subscriber resource was looked up already, so that it's not fetched again when it
+	// doesn't exist.
+	fetched bool
+	// gvr is the resource of the referenced subscriber, once it's mapped from the kind. See resourceLister.ResourceFor.
+	gvr schema.GroupVersionResource
+	// uris are the known URIs of the subscriber, e.g. from the spec and the resolved one from the status
+	uris []string
+}
+
+// backstageIDResolver is a step in the chain that resolves the Backstage ID of a subscriber.
+// It returns an empty string if it can't tell the Backstage ID, so that the next resolver is tried.
+type backstageIDResolver func(ctx context.Context, lister resourceLister, sub *subscriber, logger *zap.SugaredLogger) string
+
+// resolvers returns the chain of resolvers for the configuration.
+func (c *BackstageIDConfig) resolvers() []backstageIDResolver {
+	resolvers := []backstageIDResolver{
+		func(_ context.Context, _ resourceLister, sub *subscriber, _ *zap.SugaredLogger) string {
+			if sub.object == nil {
+				return ""
+			}
+			return sub.object.GetLabels()[BackstageKubernetesIDLabel]
+		},
+		func(_ context.Context, _ resourceLister, sub *subscriber, _ *zap.SugaredLogger) string {
+			if sub.object == nil {
+				return ""
+			}
+			return c.annotatedBackstageID(sub.object)
+		},
+	}
+	if c.OwnerDepth > 0 {
+		resolvers = append(resolvers, c.resolveFromOwners)
+	}
+	if c.NamespaceLabels {
+		resolvers = append(resolvers, resolveFromNamespace)
+	}
+	if len(c.URIMappings) > 0 {
+		resolvers = append(resolvers, func(_ context.Context, _ resourceLister, sub *subscriber, _ *zap.SugaredLogger) string {
+			for _, uri := range sub.uris {
+				if backstageId, ok := c.URIMappings[uri]; ok {
+					return backstageId
+				}
+			}
+			return ""
+		})
+	}
+	return resolvers
+}
+
+// annotatedBackstageID returns the Backstage ID in the first configured annotation that the object has.
+func (c *BackstageIDConfig) annotatedBackstageID(object *unstructured.Unstructured) string {
+	annotations := object.GetAnnotations()
+	for _, key := range c.Annotations {
+		if backstageId := annotations[key]; backstageId != "" {
+			return backstageId
+		}
+	}
+	return ""
+}
+
+// resolveFromOwners walks up the owner references of the subscriber, e.g. from a Knative Service to a Deployment
+// that's labeled, and returns the first Backstage ID it finds.
+// Owners that can't be fetched end the walk, this is best effort.
+func (c *BackstageIDConfig) resolveFromOwners(ctx context.Context, lister resourceLister, sub *subscriber, logger *zap.SugaredLogger) string {
+	object := sub.object
+	for depth := 0; object != nil && depth < c.OwnerDepth; depth++ {
+		owners := object.GetOwnerReferences()
+		if len(owners) == 0 {
+			return ""
+		}
+
+		// prefer the controller, if there are multiple owners
+		owner := owners[0]
+		for _, o := range owners {
+			if o.Controller != nil && *o.Controller {
+				owner = o
+				break
+			}
+		}
+
+		gvr, err := lister.ResourceFor(schema.FromAPIVersionAndKind(owner.APIVersion, owner.Kind))
+		if err != nil {
+			logger.Debugw("Error mapping the kind of the owner of the subscriber", "owner", owner.Name, "kind", owner.Kind, "apiVersion", owner.APIVersion, "error", err)
+			return ""
+		}
+		ownerObject, err := lister.GetResource(ctx, gvr, object.GetNamespace(), owner.Name)
+		if err != nil {
+			logger.Debugw("Error fetching the owner of the subscriber", "namespace", object.GetNamespace(), "owner", owner.Name, "kind", owner.Kind, "error", err)
+			return ""
+		}
+
+		if backstageId := ownerObject.GetLabels()[BackstageKubernetesIDLabel]; backstageId != "" {
+			return backstageId
+		}
+		if backstageId := c.annotatedBackstageID(ownerObject); backstageId != "" {
+			return backstageId
+		}
+		object = ownerObject
+	}
+	return ""
+}
+
+// resolveFromNamespace returns the Backstage ID that the namespace of the subscriber is labeled with.
+// The namespace of a subscriber that's only given by a URI is not known.
+func resolveFromNamespace(ctx context.Context, lister resourceLister, sub *subscriber, logger *zap.SugaredLogger) string {
+	if sub.ref == nil {
+		return ""
+	}
+	namespace := sub.ref.Namespace
+
+	ns, err := lister.GetResource(ctx, namespacesGVR, "", namespace)
+	if err != nil {
+		logger.Debugw("Error fetching the namespace of the subscriber", "namespace", namespace, "error", err)
+		return ""
+	}
+	return ns.GetLabels()[BackstageKubernetesIDLabel]
+}
+
+// resolveBackstageID fetches the subscriber resource, if it's referenced and not known yet, and returns the Backstage
+// ID that the first resolver in the chain finds.
+// It returns an error if the subscriber resource can't be fetched, unless it doesn't exist.
+func (c *BackstageIDConfig) resolveBackstageID(ctx context.Context, lister resourceLister, sub *subscriber, logger *zap.SugaredLogger) (string, error) {
+	if sub.ref != nil && sub.object == nil && !sub.fetched {
+		if sub.gvr.Empty() {
+			gvr, err := lister.ResourceFor(sub.gvk())
+			if err != nil {
+				return "", fmt.Errorf("error mapping the kind of the subscriber to its resource: %w", err)
+			}
+			sub.gvr = gvr
+		}
+
+		resource, err := lister.GetResource(ctx, sub.gvr, sub.ref.Namespace, sub.ref.Name)
+		switch {
+		case apierrors.IsNotFound(err):
+			// the resolvers that don't need the resource can still tell the Backstage ID
+			logger.Debugw("Subscriber resource not found", "resource", sub.ref.Name)
+		case err != nil:
+			logger.Errorw("Error fetching resource", "error", err)
+			return "", err
+		default:
+			sub.object = resource
+		}
+	}
+
+	for _, resolve := range c.resolvers() {
+		if backstageId := resolve(ctx, lister, sub, logger); backstageId != "" {
+			return backstageId, nil
+		}
+	}
+	return "", nil
+}
+
+// gvk returns the kind of the referenced subscriber.
+func (sub *subscriber) gvk() schema.GroupVersionKind {
+	return schema.FromAPIVersionAndKind(sub.ref.APIVersion, sub.ref.Kind)
+}
+
+// newSubscriber creates a subscriber from the destination of a trigger or a subscription in the given namespace,
+// and the URI it's resolved to.
+func newSubscriber(destination duckv1.Destination, resolvedURI string, namespace string) *subscriber {
+	sub := &subscriber{}
+	if destination.Ref != nil {
+		ref := *destination.Ref
+		if ref.Namespace == "" {
+			ref.Namespace = namespace
+		}
+		sub.ref = &ref
+	}
+	// a URI in the destination is relative to the referenced resource, if there's any
+	if destination.URI != nil && destination.Ref == nil {
+		sub.uris = append(sub.uris, destination.URI.String())
+	}
+	if resolvedURI != "" {
+		sub.uris = append(sub.uris, resolvedURI)
+	}
+	return sub
+}
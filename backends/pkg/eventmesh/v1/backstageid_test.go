@@ -0,0 +1,311 @@
+package v1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/ptr"
+)
+
+func TestNewBackstageIDConfigFromConfigMap(t *testing.T) {
+	tests := []struct {
+		name  string
+		data  map[string]string
+		want  *BackstageIDConfig
+		error bool
+	}{
+		{
+			name: "Defaults",
+			data: map[string]string{},
+			want: DefaultBackstageIDConfig(),
+		},
+		{
+			name: "All keys",
+			data: map[string]string{
+				"backstage-id-annotations":      "backstage.io/kubernetes-label-selector, example.com/backstage-id",
+				"backstage-id-owner-depth":      "1",
+				"backstage-id-namespace-labels": "false",
+				"backstage-id-uri-mappings":     "http://example.com/events: example-service\n",
+			},
+			want: &BackstageIDConfig{
+				Annotations:     []string{"backstage.io/kubernetes-label-selector", "example.com/backstage-id"},
+				OwnerDepth:      1,
+				NamespaceLabels: false,
+				URIMappings:     map[string]string{"http://example.com/events": "example-service"},
+			},
+		},
+		{
+			name: "No annotations",
+			data: map[string]string{
+				"backstage-id-annotations": "",
+			},
+			want: &BackstageIDConfig{
+				Annotations: []string{},
+				URIMappings: map[string]string{},
+			},
+		},
+		{
+			name: "Negative owner depth",
+			data: map[string]string{
+				"backstage-id-owner-depth": "-1",
+			},
+			error: true,
+		},
+		{
+			name: "Malformed URI mappings",
+			data: map[string]string{
+				"backstage-id-uri-mappings": "- not a map",
+			},
+			error: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewBackstageIDConfigFromConfigMap(&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: ConfigMapName},
+				Data:       tt.data,
+			})
+			if (err != nil) != tt.error {
+				t.Fatalf("NewBackstageIDConfigFromConfigMap() error = %v, error %v", err, tt.error)
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Error("NewBackstageIDConfigFromConfigMap() (-want, +got):", diff)
+			}
+		})
+	}
+}
+
+func TestResolveBackstageID(t *testing.T) {
+	serviceRef := &duckv1.KReference{APIVersion: "v1", Kind: "Service", Name: "test-subscriber"}
+
+	tests := []struct {
+		name        string
+		config      *BackstageIDConfig
+		destination duckv1.Destination
+		resolvedURI string
+		objects     []runtime.Object
+		want        string
+	}{
+		{
+			name:        "Labeled subscriber",
+			destination: duckv1.Destination{Ref: serviceRef},
+			objects: []runtime.Object{
+				newService(map[string]string{BackstageKubernetesIDLabel: "test-subscriber"}, nil),
+			},
+			want: "test-subscriber",
+		},
+		{
+			name: "Annotated subscriber",
+			config: &BackstageIDConfig{
+				Annotations: []string{"example.com/backstage-id"},
+			},
+			destination: duckv1.Destination{Ref: serviceRef},
+			objects: []runtime.Object{
+				newService(nil, map[string]string{"example.com/backstage-id": "test-annotated"}),
+			},
+			want: "test-annotated",
+		},
+		{
+			name: "Label of a subscriber that's also annotated",
+			config: &BackstageIDConfig{
+				Annotations: []string{"example.com/backstage-id"},
+			},
+			destination: duckv1.Destination{Ref: serviceRef},
+			objects: []runtime.Object{
+				newService(map[string]string{BackstageKubernetesIDLabel: "test-subscriber"}, map[string]string{"example.com/backstage-id": "test-annotated"}),
+			},
+			want: "test-subscriber",
+		},
+		{
+			name:        "Annotations are not checked by default",
+			destination: duckv1.Destination{Ref: serviceRef},
+			objects: []runtime.Object{
+				newService(nil, map[string]string{BackstageKubernetesIDLabel: "test-annotated"}),
+			},
+			want: "",
+		},
+		{
+			name: "Subscriber owned by a labeled deployment",
+			config: &BackstageIDConfig{
+				OwnerDepth: 3,
+			},
+			destination: duckv1.Destination{Ref: serviceRef},
+			objects: []runtime.Object{
+				newService(nil, nil, ownedBy("ReplicaSet", "test-rs")),
+				newReplicaSet("test-rs", ownedBy("Deployment", "test-deployment")),
+				newDeployment("test-deployment", map[string]string{BackstageKubernetesIDLabel: "test-deployment"}),
+			},
+			want: "test-deployment",
+		},
+		{
+			name:        "Owners are not checked by default",
+			destination: duckv1.Destination{Ref: serviceRef},
+			objects: []runtime.Object{
+				newService(nil, nil, ownedBy("ReplicaSet", "test-rs")),
+				newReplicaSet("test-rs", ownedBy("Deployment", "test-deployment")),
+				newDeployment("test-deployment", map[string]string{BackstageKubernetesIDLabel: "test-deployment"}),
+			},
+			want: "",
+		},
+		{
+			name: "Owners beyond the configured depth are not checked",
+			config: &BackstageIDConfig{
+				OwnerDepth: 1,
+			},
+			destination: duckv1.Destination{Ref: serviceRef},
+			objects: []runtime.Object{
+				newService(nil, nil, ownedBy("ReplicaSet", "test-rs")),
+				newReplicaSet("test-rs", ownedBy("Deployment", "test-deployment")),
+				newDeployment("test-deployment", map[string]string{BackstageKubernetesIDLabel: "test-deployment"}),
+			},
+			want: "",
+		},
+		{
+			name: "Subscriber in a labeled namespace",
+			config: &BackstageIDConfig{
+				NamespaceLabels: true,
+			},
+			destination: duckv1.Destination{Ref: serviceRef},
+			objects: []runtime.Object{
+				newService(nil, nil),
+				newNamespace(map[string]string{BackstageKubernetesIDLabel: "test-ns"}),
+			},
+			want: "test-ns",
+		},
+		{
+			name: "Subscriber that doesn't exist in a labeled namespace",
+			config: &BackstageIDConfig{
+				NamespaceLabels: true,
+			},
+			destination: duckv1.Destination{Ref: serviceRef},
+			objects: []runtime.Object{
+				newNamespace(map[string]string{BackstageKubernetesIDLabel: "test-ns"}),
+			},
+			want: "test-ns",
+		},
+		{
+			name:        "Namespace labels are not used by default",
+			destination: duckv1.Destination{Ref: serviceRef},
+			objects: []runtime.Object{
+				newService(nil, nil),
+				newNamespace(map[string]string{BackstageKubernetesIDLabel: "test-ns"}),
+			},
+			want: "",
+		},
+		{
+			name: "URI subscriber",
+			config: &BackstageIDConfig{
+				URIMappings: map[string]string{"http://example.com/events": "example-service"},
+			},
+			destination: duckv1.Destination{URI: &apis.URL{Scheme: "http", Host: "example.com", Path: "/events"}},
+			want:        "example-service",
+		},
+		{
+			name: "Resolved URI of the subscriber",
+			config: &BackstageIDConfig{
+				URIMappings: map[string]string{"http://test-subscriber.test-ns.svc.cluster.local": "test-subscriber"},
+			},
+			destination: duckv1.Destination{Ref: serviceRef},
+			resolvedURI: "http://test-subscriber.test-ns.svc.cluster.local",
+			objects: []runtime.Object{
+				newService(nil, nil),
+			},
+			want: "test-subscriber",
+		},
+		{
+			name:        "Unknown subscriber",
+			destination: duckv1.Destination{Ref: serviceRef},
+			objects: []runtime.Object{
+				newService(nil, nil),
+				newNamespace(nil),
+			},
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sc := runtime.NewScheme()
+			_ = corev1.AddToScheme(sc)
+			_ = appsv1.AddToScheme(sc)
+
+			lister := &clientLister{
+				dynamicClient: dynamicfake.NewSimpleDynamicClient(sc, tt.objects...),
+			}
+
+			config := tt.config
+			if config == nil {
+				config = DefaultBackstageIDConfig()
+			}
+
+			got, err := config.resolveBackstageID(context.TODO(), lister, newSubscriber(tt.destination, tt.resolvedURI, "test-ns"), zap.NewNop().Sugar())
+			if err != nil {
+				t.Fatalf("resolveBackstageID() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("resolveBackstageID() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func ownedBy(kind, name string) metav1.OwnerReference {
+	return metav1.OwnerReference{
+		APIVersion: "apps/v1",
+		Kind:       kind,
+		Name:       name,
+		Controller: ptr.Bool(true),
+	}
+}
+
+func newService(labels, annotations map[string]string, owners ...metav1.OwnerReference) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "test-subscriber",
+			Namespace:       "test-ns",
+			Labels:          labels,
+			Annotations:     annotations,
+			OwnerReferences: owners,
+		},
+	}
+}
+
+func newReplicaSet(name string, owners ...metav1.OwnerReference) *appsv1.ReplicaSet {
+	return &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       "test-ns",
+			OwnerReferences: owners,
+		},
+	}
+}
+
+func newDeployment(name string, labels map[string]string) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "test-ns",
+			Labels:    labels,
+		},
+	}
+}
+
+func newNamespace(labels map[string]string) *corev1.Namespace {
+	return &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "test-ns",
+			Labels: labels,
+		},
+	}
+}
@@ -0,0 +1,98 @@
+package v1
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// BackstageIDCache keeps the Backstage IDs of the subscribers across the builds of the event mesh for a while.
+// They're cached per token and per the configuration they were resolved with.
+type BackstageIDCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	lock sync.Mutex
+	// entries are keyed by the scope and the subscriber key, see scopedBackstageIDs
+	entries map[string]backstageIDEntry
+	// swept is when the expired entries were dropped the last time
+	swept time.Time
+}
+
+type backstageIDEntry struct {
+	backstageId string
+	config      *BackstageIDConfig
+	expiry      time.Time
+}
+
+func NewBackstageIDCache(ttl time.Duration) *BackstageIDCache {
+	return &BackstageIDCache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]backstageIDEntry),
+	}
+}
+
+// scoped returns the part of the cache that the builds of the event mesh with the same credentials share.
+func (c *BackstageIDCache) scoped(token string) *scopedBackstageIDs {
+	if c == nil {
+		return nil
+	}
+	scope := ""
+	if token != "" {
+		// the entries are keyed by the hash of the token, so that we don't keep the tokens around
+		hash := sha256.Sum256([]byte(token))
+		scope = hex.EncodeToString(hash[:])
+	}
+	return &scopedBackstageIDs{cache: c, scope: scope}
+}
+
+// scopedBackstageIDs are the Backstage IDs in the cache that were resolved with the same credentials.
+type scopedBackstageIDs struct {
+	cache *BackstageIDCache
+	scope string
+}
+
+// get returns the cached Backstage ID of the subscriber, if it was resolved with the configuration and hasn't expired.
+func (s *scopedBackstageIDs) get(key string, config *BackstageIDConfig) (string, bool) {
+	if s == nil {
+		return "", false
+	}
+	c := s.cache
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	entry, ok := c.entries[s.scope+"/"+key]
+	if !ok || entry.config != config || !c.now().Before(entry.expiry) {
+		return "", false
+	}
+	return entry.backstageId, true
+}
+
+// put caches the Backstage ID of the subscriber, which is empty if it's not in Backstage.
+func (s *scopedBackstageIDs) put(key string, config *BackstageIDConfig, backstageId string) {
+	if s == nil {
+		return
+	}
+	c := s.cache
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	now := c.now()
+	if !now.Before(c.swept.Add(c.ttl)) {
+		for k, entry := range c.entries {
+			if !now.Before(entry.expiry) {
+				delete(c.entries, k)
+			}
+		}
+		c.swept = now
+	}
+	c.entries[s.scope+"/"+key] = backstageIDEntry{
+		backstageId: backstageId,
+		config:      config,
+		expiry:      now.Add(c.ttl),
+	}
+}
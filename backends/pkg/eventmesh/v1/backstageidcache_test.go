@@ -0,0 +1,161 @@
+package v1
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.uber.org/zap"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	fakeclientset "knative.dev/eventing/pkg/client/clientset/versioned/fake"
+	testingv1 "knative.dev/eventing/pkg/reconciler/testing/v1"
+)
+
+func TestBackstageIDCache(t *testing.T) {
+	config := DefaultBackstageIDConfig()
+
+	tests := []struct {
+		name    string
+		token   string
+		config  *BackstageIDConfig
+		elapsed time.Duration
+		want    string
+		found   bool
+	}{
+		{
+			name:   "The Backstage ID is served to the same credentials",
+			token:  "test-token",
+			config: config,
+			want:   "test-subscriber",
+			found:  true,
+		},
+		{
+			name:   "The Backstage ID is not served to other credentials",
+			token:  "other-token",
+			config: config,
+		},
+		{
+			name:   "The Backstage ID is not served for another configuration",
+			token:  "test-token",
+			config: DefaultBackstageIDConfig(),
+		},
+		{
+			name:    "The Backstage ID expires",
+			token:   "test-token",
+			config:  config,
+			elapsed: time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Now()
+			c := NewBackstageIDCache(time.Minute)
+			c.now = func() time.Time { return now }
+
+			c.scoped("test-token").put("/v1/Service/test-ns/test-subscriber", config, "test-subscriber")
+
+			now = now.Add(tt.elapsed)
+			got, found := c.scoped(tt.token).get("/v1/Service/test-ns/test-subscriber", tt.config)
+			if got != tt.want || found != tt.found {
+				t.Errorf("get() = (%q, %v), want (%q, %v)", got, found, tt.want, tt.found)
+			}
+		})
+	}
+
+	t.Run("A nil cache doesn't cache anything", func(t *testing.T) {
+		var c *BackstageIDCache
+		c.scoped("test-token").put("/v1/Service/test-ns/test-subscriber", config, "test-subscriber")
+		if _, found := c.scoped("test-token").get("/v1/Service/test-ns/test-subscriber", config); found {
+			t.Error("get() found a Backstage ID in a nil cache")
+		}
+	})
+}
+
+func TestBuildEventMeshSharesBackstageIDs(t *testing.T) {
+	reader := setupTestMeterProvider(t)
+
+	objects := []runtime.Object{
+		testingv1.NewBroker("test-broker", "test-ns"),
+		testingv1.NewTrigger("test-trigger-1", "test-ns", "test-broker",
+			WithTriggerSubscriber(reference("v1", "Service", "test-ns", "test-subscriber")),
+		),
+		testingv1.NewTrigger("test-trigger-2", "test-ns", "test-broker",
+			WithTriggerSubscriber(reference("v1", "Service", "test-ns", "test-subscriber")),
+		),
+	}
+
+	sc := runtime.NewScheme()
+	_ = corev1.AddToScheme(sc)
+	_ = eventingv1.AddToScheme(sc)
+	_ = apiextensionsv1.AddToScheme(sc)
+
+	fakeDynamicClient := dynamicfake.NewSimpleDynamicClient(sc, backstageService("test-subscriber"))
+
+	var mu sync.Mutex
+	calls := 0
+	fakeDynamicClient.PrependReactor("*", "services", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		return false, nil, nil
+	})
+
+	lister := &clientLister{
+		clientset:     fakeclientset.NewSimpleClientset(objects...),
+		dynamicClient: fakeDynamicClient,
+	}
+	config := DefaultBackstageIDConfig()
+	cache := NewBackstageIDCache(time.Minute)
+
+	for build := 0; build < 2; build++ {
+		eventMesh, err := buildEventMesh(context.TODO(), lister, config, cache.scoped("test-token"), nil, zap.NewNop().Sugar())
+		if err != nil {
+			t.Fatalf("buildEventMesh() error = %v", err)
+		}
+		for _, trigger := range eventMesh.Triggers {
+			if trigger.BackstageID != "test-subscriber" {
+				t.Errorf("build %d: trigger %s has Backstage ID %q, want %q", build, trigger.Name, trigger.BackstageID, "test-subscriber")
+			}
+		}
+	}
+
+	if calls != 1 {
+		t.Errorf("calls for the subscriber = %d, want 1", calls)
+	}
+
+	// the subscriber is resolved by the first build only, the triggers find it in the cache of the build.
+	want := map[string]int64{
+		"build/false":  2,
+		"build/true":   4,
+		"shared/false": 1,
+		"shared/true":  1,
+	}
+	if diff := cmp.Diff(want, backstageIDLookupCounts(t, reader)); diff != "" {
+		t.Error("Backstage ID lookups (-want, +got):", diff)
+	}
+}
+
+// backstageIDLookupCounts returns the recorded lookups of the Backstage IDs, keyed by "<cache>/<hit>".
+func backstageIDLookupCounts(t *testing.T, reader sdkmetric.Reader) map[string]int64 {
+	t.Helper()
+
+	counts := make(map[string]int64)
+	for _, dp := range collectMetric(t, reader, "kn.eventmesh.backstage_id.lookups").(metricdata.Sum[int64]).DataPoints {
+		cache, _ := dp.Attributes.Value("kn.eventmesh.backstage_id.cache")
+		hit, _ := dp.Attributes.Value("kn.eventmesh.backstage_id.cache.hit")
+		counts[cache.Emit()+"/"+hit.Emit()] = dp.Value
+	}
+	return counts
+}
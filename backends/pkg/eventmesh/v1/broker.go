@@ -22,5 +22,6 @@ func convertBroker(br *eventingv1.Broker) Broker {
 		Annotations: util.FilterAnnotations(br.Annotations),
 		// this field will be populated later on, when we have the list of event types
 		ProvidedEventTypes: []string{},
+		Status:             convertStatus(br.Status.Conditions, br.Status.AddressStatus),
 	}
 }
@@ -3,21 +3,25 @@ package v1
 import (
 	"context"
 	"fmt"
+	"slices"
 	"sort"
+	"sync"
+	"time"
 
 	"go.uber.org/zap"
 
 	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	eventingv1beta2 "knative.dev/eventing/pkg/apis/eventing/v1beta2"
+	flowsv1 "knative.dev/eventing/pkg/apis/flows/v1"
 	v1 "knative.dev/eventing/pkg/apis/messaging/v1"
 	"knative.dev/eventing/pkg/client/clientset/versioned"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/observability/semconv"
 
 	"knative.dev/backstage-plugins/backends/pkg/util"
 
-	apierrors "k8s.io/apimachinery/pkg/api/errors"
-	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
 )
@@ -34,24 +38,100 @@ const BackstageKubernetesIDLabel = "backstage.io/kubernetes-id"
 // - Do the same for event types.
 // - Fetch the triggers, find out what event types they're subscribed to and find out the resources that are receiving the events.
 // - Make a connection between the event types and the subscribers. Store this connection in the eventType struct.
-func BuildEventMesh(ctx context.Context, clientset versioned.Interface, dynamicClient dynamic.Interface, logger *zap.SugaredLogger) (EventMesh, error) {
-	// fetch the brokers and convert them to the representation that's consumed by the Backstage plugin.
-	convertedBrokers, err := fetchBrokers(clientset, logger)
-	if err != nil {
-		logger.Errorw("Error fetching and converting brokers", "error", err)
-		return EventMesh{}, err
-	}
-
-	convertedSubscribables, err := fetchSubscribables(ctx, dynamicClient, logger)
-	if err != nil {
-		logger.Errorw("Error fetching and converting subscribables", "error", err)
-		return EventMesh{}, err
-	}
+// - Do the same for the subscriptions of the channels.
+// - Return the triggers and the subscriptions as well, along with the Backstage IDs of their subscribers.
+// The resources are listed in the given namespaces only, or in all namespaces if none are given.
+// The kinds of resources, and the subscribers, are fetched in parallel with a bounded number of calls at a time.
+func BuildEventMesh(ctx context.Context, clientset versioned.Interface, dynamicClient dynamic.Interface, namespaces []string, backstageIDConfig *BackstageIDConfig, logger *zap.SugaredLogger) (EventMesh, error) {
+	lister := &clientLister{
+		clientset:     clientset,
+		dynamicClient: dynamicClient,
+		namespaces:    namespaces,
+	}
+	return buildEventMesh(ctx, lister, backstageIDConfig, nil, nil, logger)
+}
 
-	convertedSourceEntries, err := fetchSources(ctx, dynamicClient, logger)
-	if err != nil {
-		logger.Errorw("Error fetching and converting sources", "error", err)
-		return EventMesh{}, err
+// buildEventMesh builds the event mesh data from the resources provided by the lister.
+// The parts of the event mesh that can't be built, e.g. because a kind of resources can't be listed, are skipped and
+// reported in the warnings of the event mesh. The rest of the event mesh is still returned.
+// The Backstage IDs of the subscribers are shared with the other builds with the same credentials, if sharedIds is
+// given.
+func buildEventMesh(ctx context.Context, lister resourceLister, backstageIDConfig *BackstageIDConfig, sharedIds *scopedBackstageIDs, paths *consumerPaths, logger *zap.SugaredLogger) (EventMesh, error) {
+	ctx, span := startSpan(ctx, "buildEventMesh")
+	defer span.End()
+
+	if backstageIDConfig == nil {
+		backstageIDConfig = DefaultBackstageIDConfig()
+	}
+
+	warnings := &warnings{}
+	discovered := &discoveredKinds{}
+
+	// the kinds of resources don't depend on each other, so they're fetched in parallel.
+	// the number of calls that are made to the API server at the same time is limited, though.
+	lister = newLimitedLister(lister, fetchConcurrency)
+
+	var (
+		convertedBrokers       []*Broker
+		convertedSubscribables []*Subscribable
+		convertedSourceEntries []*sourceEntry
+		sinks                  []*unstructured.Unstructured
+		sequences              []*flowsv1.Sequence
+		parallels              []*flowsv1.Parallel
+		convertedEventTypes    []*EventType
+		eventTypeAttributes    map[string]map[string]string
+		triggers               []*eventingv1.Trigger
+		subscriptions          []*v1.Subscription
+	)
+	var wg sync.WaitGroup
+	// fetch the brokers and convert them to the representation that's consumed by the Backstage plugin.
+	wg.Go(timedBuildPhase(ctx, buildPhaseBrokers, func() {
+		convertedBrokers = fetchBrokers(ctx, lister, warnings, logger)
+	}))
+	wg.Go(timedBuildPhase(ctx, buildPhaseSubscribables, func() {
+		convertedSubscribables = fetchSubscribables(ctx, lister, warnings, discovered, logger)
+	}))
+	wg.Go(timedBuildPhase(ctx, buildPhaseSources, func() {
+		convertedSourceEntries = fetchSources(ctx, lister, warnings, discovered, logger)
+	}))
+	wg.Go(timedBuildPhase(ctx, buildPhaseSinks, func() {
+		sinks = fetchSinks(ctx, lister, warnings, discovered, logger)
+	}))
+	wg.Go(timedBuildPhase(ctx, buildPhaseSequences, func() {
+		sequences = listSequences(ctx, lister, warnings, logger)
+	}))
+	wg.Go(timedBuildPhase(ctx, buildPhaseParallels, func() {
+		parallels = listParallels(ctx, lister, warnings, logger)
+	}))
+	// fetch the event types and convert them to the representation that's consumed by the Backstage plugin.
+	wg.Go(timedBuildPhase(ctx, buildPhaseEventTypes, func() {
+		convertedEventTypes = fetchEventTypes(ctx, lister, warnings, logger)
+		eventTypeAttributes = fetchEventTypeAttributes(ctx, lister, logger)
+	}))
+	// fetch the triggers and the subscriptions, we will process them later
+	wg.Go(timedBuildPhase(ctx, buildPhaseTriggers, func() {
+		triggers = listTriggers(ctx, lister, warnings, logger)
+	}))
+	wg.Go(timedBuildPhase(ctx, buildPhaseSubscriptions, func() {
+		subscriptions = listSubscriptions(ctx, lister, warnings, logger)
+	}))
+	wg.Wait()
+
+	convertedSinks := make([]*Sink, 0, len(sinks))
+	for _, sink := range sinks {
+		convertedSink := convertSink(sink)
+		convertedSinks = append(convertedSinks, &convertedSink)
+	}
+
+	convertedSequences := make([]*Sequence, 0, len(sequences))
+	for _, sequence := range sequences {
+		convertedSequence := convertSequence(sequence)
+		convertedSequences = append(convertedSequences, &convertedSequence)
+	}
+	convertedParallels := make([]*Parallel, 0, len(parallels))
+	for _, parallel := range parallels {
+		convertedParallel := convertParallel(parallel)
+		convertedParallels = append(convertedParallels, &convertedParallel)
 	}
 
 	// build a broker map and a subscribable map for easier access.
@@ -69,11 +149,43 @@ func BuildEventMesh(ctx context.Context, clientset versioned.Interface, dynamicC
 		subscribableMap[key] = s
 	}
 
-	// fetch the event types and convert them to the representation that's consumed by the Backstage plugin.
-	convertedEventTypes, err := fetchEventTypes(clientset, logger)
-	if err != nil {
-		logger.Errorw("Error fetching and converting event types", "error", err)
-		return EventMesh{}, err
+	// same for the flows, the event types can be declared to be sent to them as well.
+	// map key: "<group>/<kind>/<namespace>/<name>"
+	sequenceMap := make(map[string]*Sequence)
+	for _, s := range convertedSequences {
+		sequenceMap[util.GKNamespacedName(flowsv1.SchemeGroupVersion.Group, "Sequence", s.Namespace, s.Name)] = s
+	}
+
+	parallelMap := make(map[string]*Parallel)
+	for _, p := range convertedParallels {
+		parallelMap[util.GKNamespacedName(flowsv1.SchemeGroupVersion.Group, "Parallel", p.Namespace, p.Name)] = p
+	}
+
+	// the sources can send the events to the addresses of the brokers, channels, flows and sinks instead of referencing
+	// them, these are resolved to the resources later on.
+	addresses := addressables{}
+	for _, br := range convertedBrokers {
+		if br.Status != nil {
+			addresses.add(br.Status.Address, GroupKindNamespacedName{Group: eventingv1.SchemeGroupVersion.Group, Kind: "Broker", Namespace: br.Namespace, Name: br.Name})
+		}
+	}
+	for _, sb := range convertedSubscribables {
+		if sb.Status != nil {
+			addresses.add(sb.Status.Address, GroupKindNamespacedName{Group: sb.Group, Kind: sb.Kind, Namespace: sb.Namespace, Name: sb.Name})
+		}
+	}
+	for _, sq := range convertedSequences {
+		if sq.Status != nil {
+			addresses.add(sq.Status.Address, GroupKindNamespacedName{Group: flowsv1.SchemeGroupVersion.Group, Kind: "Sequence", Namespace: sq.Namespace, Name: sq.Name})
+		}
+	}
+	for _, p := range convertedParallels {
+		if p.Status != nil {
+			addresses.add(p.Status.Address, GroupKindNamespacedName{Group: flowsv1.SchemeGroupVersion.Group, Kind: "Parallel", Namespace: p.Namespace, Name: p.Name})
+		}
+	}
+	for _, sk := range convertedSinks {
+		addresses.add(sk.Address, GroupKindNamespacedName{Group: sk.Group, Kind: sk.Kind, Namespace: sk.Namespace, Name: sk.Name})
 	}
 
 	// register the event types in the brokers and channels
@@ -83,6 +195,10 @@ func BuildEventMesh(ctx context.Context, clientset versioned.Interface, dynamicC
 				br.ProvidedEventTypes = append(br.ProvidedEventTypes, et.NamespacedName())
 			} else if subscribable, ok := subscribableMap[et.Reference.String()]; ok {
 				subscribable.ProvidedEventTypes = append(subscribable.ProvidedEventTypes, et.NamespacedName())
+			} else if sequence, ok := sequenceMap[et.Reference.String()]; ok {
+				sequence.ProvidedEventTypes = append(sequence.ProvidedEventTypes, et.NamespacedName())
+			} else if parallel, ok := parallelMap[et.Reference.String()]; ok {
+				parallel.ProvidedEventTypes = append(parallel.ProvidedEventTypes, et.NamespacedName())
 			} else {
 				logger.Infow("Event type reference not found", "eventType", et.NamespacedName(), "reference", *et.Reference)
 			}
@@ -121,41 +237,81 @@ func BuildEventMesh(ctx context.Context, clientset versioned.Interface, dynamicC
 		}
 	}
 
-	// fetch the triggers we will process them later
-	triggers, err := clientset.EventingV1().Triggers(metav1.NamespaceAll).List(context.Background(), metav1.ListOptions{})
-	if err != nil {
-		logger.Errorw("Error listing triggers", "error", err)
-		return EventMesh{}, err
-	}
+	// the tracer follows the events through the brokers, channels, sequences and parallels to their consumers
+	tracer := newConsumerTracer(lister, backstageIDConfig, sharedIds, paths, warnings, eventTypeAttributes, convertedSubscribables, triggers, subscriptions, sequences, parallels, sinks, logger)
+	// the subscribers are looked up in parallel up front, each one once, rather than one by one while processing
+	lookupStart := time.Now()
+	tracer.prefetchBackstageIDs(ctx, subscribersOf(triggers, subscriptions, sequences, parallels))
+	recordBuildPhase(ctx, buildPhaseSubscriberLookups, lookupStart)
 
-	for _, trigger := range triggers.Items {
-		err := processTrigger(ctx, &trigger, brokerMap, etByNamespacedName, dynamicClient, logger)
+	consumersStart := time.Now()
+
+	outputTriggers := make([]Trigger, 0, len(triggers))
+	for _, trigger := range triggers {
+		convertedTrigger := convertTrigger(trigger)
+
+		subscriberBackstageId, err := processTrigger(ctx, trigger, brokerMap, etByNamespacedName, tracer, logger)
 		if err != nil {
 			logger.Errorw("Error processing trigger", "error", err)
 			// do not stop the Backstage plugin from rendering the rest of the data, e.g. because
 			// there are no permissions to get a single subscriber resource
+			warnings.add(eventingv1.Kind("Trigger"), trigger.Namespace, trigger.Name, err)
 		}
-	}
 
-	subscriptions, err := clientset.MessagingV1().Subscriptions(metav1.NamespaceAll).List(context.Background(), metav1.ListOptions{})
-	if err != nil {
-		logger.Errorw("Error listing subscriptions", "error", err)
-		return EventMesh{}, err
+		convertedTrigger.BackstageID = subscriberBackstageId
+		outputTriggers = append(outputTriggers, convertedTrigger)
 	}
 
-	for _, subscription := range subscriptions.Items {
-		err := processSubscription(ctx, &subscription, subscribableMap, etByNamespacedName, dynamicClient, logger)
-		if apierrors.IsUnauthorized(err) {
-			logger.Errorw("Error processing subscription", "error", err)
-			// do not stop the Backstage plugin from rendering the rest of the data, e.g. because
-			// there are no permissions to get a single subscriber resource
-			continue
-		}
+	outputSubscriptions := make([]Subscription, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		convertedSubscription := convertSubscription(subscription)
 
+		subscriberBackstageId, err := processSubscription(ctx, subscription, subscribableMap, etByNamespacedName, tracer, logger)
 		if err != nil {
 			logger.Errorw("Error processing subscription", "error", err)
-			return EventMesh{}, fmt.Errorf("error processing subscription: %w", err)
+			// same as the triggers, a single subscription must not break the whole event mesh
+			warnings.add(v1.Kind("Subscription"), subscription.Namespace, subscription.Name, err)
 		}
+
+		convertedSubscription.BackstageID = subscriberBackstageId
+		outputSubscriptions = append(outputSubscriptions, convertedSubscription)
+	}
+
+	// the event types that the tracer followed into the flows are sent to them, on top of the declared ones
+	for i, sequence := range sequences {
+		if err := processSequence(ctx, sequence, convertedSequences[i], tracer); err != nil {
+			logger.Errorw("Error processing sequence", "error", err)
+			warnings.add(flowsv1.Kind("Sequence"), sequence.Namespace, sequence.Name, err)
+		}
+	}
+	for i, parallel := range parallels {
+		if err := processParallel(ctx, parallel, convertedParallels[i], tracer); err != nil {
+			logger.Errorw("Error processing parallel", "error", err)
+			warnings.add(flowsv1.Kind("Parallel"), parallel.Namespace, parallel.Name, err)
+		}
+	}
+
+	// link the sources to the resources their sinks point to
+	for _, entry := range convertedSourceEntries {
+		if err := processSource(ctx, entry, addresses, tracer); err != nil {
+			logger.Errorw("Error processing source", "error", err)
+			warnings.add(schema.GroupKind{Group: entry.Group, Kind: entry.Kind}, entry.Namespace, entry.Name, err)
+		}
+	}
+
+	// the sinks consume the event types that the tracer followed to them
+	for i, sink := range sinks {
+		if err := processSink(ctx, sink, convertedSinks[i], tracer); err != nil {
+			logger.Errorw("Error processing sink", "error", err)
+			warnings.add(sink.GroupVersionKind().GroupKind(), sink.GetNamespace(), sink.GetName(), err)
+		}
+	}
+
+	recordBuildPhase(ctx, buildPhaseConsumers, consumersStart)
+
+	// if the request is gone, most of the resources are missing because of that, not because of actual problems
+	if err := ctx.Err(); err != nil {
+		return EventMesh{}, err
 	}
 
 	outputEventTypes := make([]EventType, 0, len(convertedEventTypes))
@@ -170,80 +326,129 @@ func BuildEventMesh(ctx context.Context, clientset versioned.Interface, dynamicC
 	for _, s := range convertedSubscribables {
 		outputSubscribables = append(outputSubscribables, *s)
 	}
+	outputSequences := make([]Sequence, 0, len(convertedSequences))
+	for _, s := range convertedSequences {
+		outputSequences = append(outputSequences, *s)
+	}
+	outputParallels := make([]Parallel, 0, len(convertedParallels))
+	for _, p := range convertedParallels {
+		outputParallels = append(outputParallels, *p)
+	}
 	outputSources := make([]Source, 0, len(convertedSourceEntries))
 	for _, s := range convertedSourceEntries {
-		outputSources = append(outputSources, *s)
+		outputSources = append(outputSources, *s.Source)
 	}
 
-	eventMesh := EventMesh{
-		EventTypes:    outputEventTypes,
-		Brokers:       outputBrokers,
-		Subscribables: outputSubscribables,
-		Sources:       outputSources,
+	outputSinks := make([]Sink, 0, len(convertedSinks))
+	for _, s := range convertedSinks {
+		outputSinks = append(outputSinks, *s)
 	}
 
+	eventMesh := EventMesh{
+		EventTypes:      outputEventTypes,
+		Brokers:         outputBrokers,
+		Subscribables:   outputSubscribables,
+		Sources:         outputSources,
+		Sinks:           outputSinks,
+		Triggers:        outputTriggers,
+		Subscriptions:   outputSubscriptions,
+		Sequences:       outputSequences,
+		Parallels:       outputParallels,
+		Warnings:        warnings.list(),
+		DiscoveredKinds: discovered.list(),
+	}
+	normalizeEventMesh(&eventMesh)
+
 	return eventMesh, nil
 }
 
 // processTrigger processes the trigger and updates the ETs that the trigger is subscribed to.
-// The consumedBy fields of ETs are updated with the subscriber's Backstage ID.
-func processTrigger(ctx context.Context, trigger *eventingv1.Trigger, brokerMap map[string]*Broker, etByNamespacedName map[string]*EventType, dynamicClient dynamic.Interface, logger *zap.SugaredLogger) error {
+// The events are followed through the brokers, channels, sequences and parallels that the subscriber forwards them to,
+// and the consumedBy fields of the ETs are updated with the Backstage IDs of the consumers they eventually reach.
+// The Backstage ID of the subscriber itself is returned, which is empty if the subscriber only forwards the events.
+func processTrigger(ctx context.Context, trigger *eventingv1.Trigger, brokerMap map[string]*Broker, etByNamespacedName map[string]*EventType, tracer *consumerTracer, logger *zap.SugaredLogger) (_ string, err error) {
+	ctx, span := startSpan(ctx, "processTrigger", semconv.K8SNamespaceName(trigger.Namespace), triggerNameAttr.With(trigger.Name))
+	defer func() {
+		if err != nil {
+			recordSpanError(span, err)
+		}
+		span.End()
+	}()
+
 	// if the trigger has no subscriber, we can skip it, there's no relation to show on Backstage side
-	if trigger.Spec.Subscriber.Ref == nil {
-		logger.Debugw("Trigger has no subscriber ref; cannot process this trigger", "namespace", trigger.Namespace, "trigger", trigger.Name)
-		return nil
+	sub := newSubscriber(trigger.Spec.Subscriber, urlString(trigger.Status.SubscriberURI), trigger.Namespace)
+	if sub.ref == nil && len(sub.uris) == 0 {
+		logger.Debugw("Trigger has no subscriber; cannot process this trigger", "namespace", trigger.Namespace, "trigger", trigger.Name)
+		return "", nil
 	}
 
-	subscriberBackstageId, err := getSubscriberBackstageId(ctx, dynamicClient, trigger.Spec.Subscriber.Ref, logger)
+	subscriberBackstageId, err := tracer.subscriberBackstageID(ctx, sub)
 	if err != nil {
 		// wrap the error to provide more context
-		return fmt.Errorf("error getting subscriber backstage id: %w", err)
-	}
-
-	// we only care about subscribers that are in Backstage
-	if len(subscriberBackstageId) == 0 {
-		logger.Debugw("Subscriber has no backstage id", "namespace", trigger.Namespace, "trigger", trigger.Name)
-		return nil
+		return "", fmt.Errorf("error getting subscriber backstage id: %w", err)
 	}
 
 	// if the trigger's broker is not set or if we haven't processed the broker, we can skip the trigger
 	if trigger.Spec.Broker == "" {
 		logger.Errorw("Trigger has no broker", "namespace", trigger.Namespace, "trigger", trigger.Name)
-		return nil
+		return subscriberBackstageId, nil
 	}
 	brokerRef := util.GKNamespacedName("eventing.knative.dev", "Broker", trigger.Namespace, trigger.Spec.Broker)
 	if _, ok := brokerMap[brokerRef]; !ok {
 		logger.Infow("Broker not found", "namespace", trigger.Namespace, "trigger", trigger.Name, "broker", trigger.Spec.Broker)
-		return nil
+		return subscriberBackstageId, nil
 	}
 
-	eventTypes := collectSubscribedEventTypes(trigger, brokerMap[brokerRef], etByNamespacedName, logger)
-	logger.Debugw("Collected subscribed event types", "namespace", trigger.Namespace, "trigger", trigger.Name, "broker", trigger.Spec.Broker, "eventTypes", eventTypes)
+	eventTypes, indeterminateEventTypes := collectSubscribedEventTypes(trigger, brokerMap[brokerRef], etByNamespacedName, tracer.eventTypeAttributes, logger)
+	logger.Debugw("Collected subscribed event types", "namespace", trigger.Namespace, "trigger", trigger.Name, "broker", trigger.Spec.Broker, "eventTypes", eventTypes, "indeterminateEventTypes", indeterminateEventTypes)
 
+	indeterminate := make(map[*EventType]bool, len(indeterminateEventTypes))
+	for _, eventType := range indeterminateEventTypes {
+		indeterminate[eventType] = true
+	}
+
+	hop := convertDestination(trigger.Spec.Subscriber, trigger.Namespace).Ref
 	for _, eventType := range eventTypes {
-		eventType.ConsumedBy = append(eventType.ConsumedBy, subscriberBackstageId)
+		// the events that come back to the broker of the trigger are not followed
+		consumers, err := tracer.trace(ctx, trigger.Spec.Subscriber, urlString(trigger.Status.SubscriberURI), trigger.Namespace, eventType, map[string]bool{brokerRef: true})
+		if err != nil {
+			return subscriberBackstageId, fmt.Errorf("error tracing the consumers of event type %s: %w", eventType.NamespacedName(), err)
+		}
+		tracer.addConsumers(eventType, meshResource{gk: eventingv1.Kind("Trigger"), namespace: trigger.Namespace}, hop, consumers, indeterminate[eventType])
 	}
 
-	return nil
+	return subscriberBackstageId, nil
 }
 
-func processSubscription(ctx context.Context, subscription *v1.Subscription, subscribableMap map[string]*Subscribable, etByNamespacedName map[string]*EventType, dynamicClient dynamic.Interface, logger *zap.SugaredLogger) error {
+// processSubscription processes the subscription and updates the ETs that the channel of the subscription provides.
+// Like for the triggers, the events are followed to the consumers they eventually reach, whose Backstage IDs are
+// added to the consumedBy fields of the ETs.
+// The Backstage ID of the subscriber itself is returned, which is empty if the subscriber only forwards the events.
+func processSubscription(ctx context.Context, subscription *v1.Subscription, subscribableMap map[string]*Subscribable, etByNamespacedName map[string]*EventType, tracer *consumerTracer, logger *zap.SugaredLogger) (_ string, err error) {
+	ctx, span := startSpan(ctx, "processSubscription", semconv.K8SNamespaceName(subscription.Namespace), subscriptionNameAttr.With(subscription.Name))
+	defer func() {
+		if err != nil {
+			recordSpanError(span, err)
+		}
+		span.End()
+	}()
+
 	// if the subscription has no subscriber, we can skip it, there's no relation to show on Backstage side
-	if subscription.Spec.Subscriber.Ref == nil {
-		logger.Debugw("Subscription has no subscriber ref; cannot process this subscription", "namespace", subscription.Namespace, "subscription", subscription.Name)
-		return nil
+	if subscription.Spec.Subscriber == nil {
+		logger.Debugw("Subscription has no subscriber; cannot process this subscription", "namespace", subscription.Namespace, "subscription", subscription.Name)
+		return "", nil
+	}
+	subscriberURI := urlString(subscription.Status.PhysicalSubscription.SubscriberURI)
+	sub := newSubscriber(*subscription.Spec.Subscriber, subscriberURI, subscription.Namespace)
+	if sub.ref == nil && len(sub.uris) == 0 {
+		logger.Debugw("Subscription has no subscriber; cannot process this subscription", "namespace", subscription.Namespace, "subscription", subscription.Name)
+		return "", nil
 	}
 
-	subscriberBackstageId, err := getSubscriberBackstageId(ctx, dynamicClient, subscription.Spec.Subscriber.Ref, logger)
+	subscriberBackstageId, err := tracer.subscriberBackstageID(ctx, sub)
 	if err != nil {
 		// wrap the error to provide more context
-		return fmt.Errorf("error getting subscriber backstage id: %w", err)
-	}
-
-	// we only care about subscribers that are in Backstage
-	if len(subscriberBackstageId) == 0 {
-		logger.Debugw("Subscriber has no backstage id", "namespace", subscription.Namespace, "subscription", subscription.Name)
-		return nil
+		return "", fmt.Errorf("error getting subscriber backstage id: %w", err)
 	}
 
 	// if we haven't processed the channel, we can skip the subscription
@@ -251,196 +456,361 @@ func processSubscription(ctx context.Context, subscription *v1.Subscription, sub
 	channelRef := util.GKNamespacedName(util.APIVersionToGroup(channel.APIVersion), channel.Kind, subscription.Namespace, channel.Name)
 	if _, ok := subscribableMap[channelRef]; !ok {
 		logger.Infow("Channel not found", "namespace", subscription.Namespace, "subscription", subscription.Name, "channel", channel.Name)
-		return nil
+		return subscriberBackstageId, nil
 	}
 
 	eventTypes := subscribableMap[channelRef].ProvidedEventTypes
 	logger.Infow("Collected provided event types", "namespace", subscription.Namespace, "subscription", subscription.Name, "channel", channel.Name, "eventTypes", eventTypes)
 
+	hop := convertDestination(*subscription.Spec.Subscriber, subscription.Namespace).Ref
+	// the provided event types are already "<namespace>/<name>" references
 	for _, eventType := range eventTypes {
-		key := util.NamespacedName(subscription.Namespace, eventType)
-		if et, ok := etByNamespacedName[key]; ok {
-			et.ConsumedBy = append(et.ConsumedBy, subscriberBackstageId)
+		et, ok := etByNamespacedName[eventType]
+		if !ok {
+			continue
+		}
+
+		// the events that come back to the channel of the subscription are not followed
+		consumers, err := tracer.trace(ctx, *subscription.Spec.Subscriber, subscriberURI, subscription.Namespace, et, map[string]bool{channelRef: true})
+		if err != nil {
+			return subscriberBackstageId, fmt.Errorf("error tracing the consumers of event type %s: %w", et.NamespacedName(), err)
 		}
+		tracer.addConsumers(et, meshResource{gk: v1.Kind("Subscription"), namespace: subscription.Namespace}, hop, consumers, false)
+	}
+
+	return subscriberBackstageId, nil
+}
+
+// processSequence fills in the event types that are sent to the sequence, which flow through all of its steps, and the
+// Backstage IDs of the subscribers of the steps.
+func processSequence(ctx context.Context, sequence *flowsv1.Sequence, converted *Sequence, tracer *consumerTracer) error {
+	key := util.GKNamespacedName(flowsv1.SchemeGroupVersion.Group, "Sequence", sequence.Namespace, sequence.Name)
+	converted.ProvidedEventTypes = appendMissing(converted.ProvidedEventTypes, tracer.receivedEventTypes[key])
+	for i := range converted.Steps {
+		converted.Steps[i].ProvidedEventTypes = append([]string{}, converted.ProvidedEventTypes...)
 	}
 
+	for i, step := range sequence.Spec.Steps {
+		backstageId, err := tracer.subscriberBackstageID(ctx, newSubscriber(step.Destination, "", sequence.Namespace))
+		if err != nil {
+			return fmt.Errorf("error getting the backstage id of the subscriber of step %d: %w", i, err)
+		}
+		converted.Steps[i].BackstageID = backstageId
+	}
 	return nil
 }
 
-// collectSubscribedEventTypes collects the event types that the trigger is subscribed to.
-// It does it by checking the trigger's filter and finding out the ET types that the filter is interested in.
-// Later on, it finds the ETs that the broker provides and returns the ones matches the type.
-// If the trigger has no filter, it returns all the ETs that the broker provides.
-func collectSubscribedEventTypes(trigger *eventingv1.Trigger, broker *Broker, etByNamespacedName map[string]*EventType, logger *zap.SugaredLogger) []*EventType {
-	logger.Debugw("Collecting subscribed event types", "namespace", trigger.Namespace, "trigger", trigger.Name, "broker", broker.Name)
-
-	// TODO: we don't handle the CESQL yet
-	if trigger.Spec.Filter != nil && len(trigger.Spec.Filter.Attributes) > 0 {
-		logger.Debugw("Trigger has filter", "namespace", trigger.Namespace, "trigger", trigger.Name, "broker", broker.Name, "filter", trigger.Spec.Filter.Attributes)
-
-		// check if "type" attribute is present
-		if subscribedEventType, ok := trigger.Spec.Filter.Attributes["type"]; ok {
-			logger.Debugw("Trigger has type filter", "namespace", trigger.Namespace, "trigger", trigger.Name, "broker", broker.Name, "type", subscribedEventType)
-
-			// it can be present but empty
-			// in that case, we assume the trigger is subscribed to all event types
-			if subscribedEventType != eventingv1.TriggerAnyFilter {
-				logger.Debugw("Trigger has non-empty type filter", "namespace", trigger.Namespace, "trigger", trigger.Name, "broker", broker.Name, "type", subscribedEventType)
-
-				// if type is present and not empty, that means the trigger is subscribed to a ETs of that type
-				// find the ETs for that type
-				subscribedEventTypes := make([]*EventType, 0)
-				for _, etNamespacedName := range broker.ProvidedEventTypes {
-					if et, ok := etByNamespacedName[etNamespacedName]; ok {
-						if et.Type == subscribedEventType {
-							subscribedEventTypes = append(subscribedEventTypes, et)
-						}
-					}
-				}
-				logger.Debugw("Found subscribed event types", "namespace", trigger.Namespace, "trigger", trigger.Name, "broker", broker.Name, "subscribedEventTypes", subscribedEventTypes)
-				return subscribedEventTypes
+// processParallel fills in the event types that are sent to the parallel and the Backstage IDs of the subscribers of
+// its branches.
+func processParallel(ctx context.Context, parallel *flowsv1.Parallel, converted *Parallel, tracer *consumerTracer) error {
+	key := util.GKNamespacedName(flowsv1.SchemeGroupVersion.Group, "Parallel", parallel.Namespace, parallel.Name)
+	converted.ProvidedEventTypes = appendMissing(converted.ProvidedEventTypes, tracer.receivedEventTypes[key])
+
+	for i, branch := range parallel.Spec.Branches {
+		backstageId, err := tracer.subscriberBackstageID(ctx, newSubscriber(branch.Subscriber, "", parallel.Namespace))
+		if err != nil {
+			return fmt.Errorf("error getting the backstage id of the subscriber of branch %d: %w", i, err)
+		}
+		converted.Branches[i].BackstageID = backstageId
+	}
+	return nil
+}
+
+// processSource links the source to the resource that its sink points to, and resolves the Backstage ID of the sink if
+// it consumes the events itself. Sinks that are only given by a URI are linked to the resource with that address.
+func processSource(ctx context.Context, entry *sourceEntry, addresses addressables, tracer *consumerTracer) error {
+	sub := &subscriber{}
+	if entry.sink != nil {
+		sub = newSubscriber(*entry.sink, entry.SinkURI, entry.Namespace)
+	} else if entry.SinkURI != "" {
+		sub.uris = []string{entry.SinkURI}
+	} else {
+		return nil
+	}
+
+	if entry.Sink == nil {
+		for _, uri := range sub.uris {
+			ref, ok := addresses.resolve(uri)
+			if !ok {
+				continue
+			}
+			entry.Sink = &ref
+			// the brokers, channels and flows are not consumers, there's no need to look them up
+			if tracer.forwards(ref) {
+				return nil
 			}
+			sub.ref = tracer.consumerReference(ref)
+			break
 		}
 	}
 
-	logger.Debugw("Trigger has no filter or type, returning all event types the broker provides", "namespace", trigger.Namespace, "trigger", trigger.Name, "broker", broker.Name)
-	// if no filter or type is specified, we assume the resource is interested in all event types that the broker provides
+	backstageId, err := tracer.subscriberBackstageID(ctx, sub)
+	if err != nil {
+		return fmt.Errorf("error getting the backstage id of the sink: %w", err)
+	}
+	entry.SinkBackstageID = backstageId
+	return nil
+}
+
+// processSink fills in the event types that are sent to the sink and the Backstage ID of the sink.
+func processSink(ctx context.Context, sink *unstructured.Unstructured, converted *Sink, tracer *consumerTracer) error {
+	key := sinkKey(sink)
+	converted.ConsumedEventTypes = appendMissing(converted.ConsumedEventTypes, tracer.receivedEventTypes[key])
+
+	sub := &subscriber{
+		ref: &duckv1.KReference{
+			APIVersion: sink.GetAPIVersion(),
+			Kind:       sink.GetKind(),
+			Namespace:  sink.GetNamespace(),
+			Name:       sink.GetName(),
+		},
+	}
+	if converted.Address != "" {
+		sub.uris = []string{converted.Address}
+	}
+	backstageId, err := tracer.backstageID(ctx, sub)
+	if err != nil {
+		return fmt.Errorf("error getting the backstage id of the sink: %w", err)
+	}
+	converted.BackstageID = backstageId
+	return nil
+}
+
+// appendMissing appends the values that are not in the slice yet.
+func appendMissing(slice []string, values []string) []string {
+	for _, v := range values {
+		if !slices.Contains(slice, v) {
+			slice = append(slice, v)
+		}
+	}
+	return slice
+}
+
+// collectSubscribedEventTypes collects the event types that the trigger is subscribed to.
+// It does it by evaluating the trigger's filters against the static attributes of the ETs that the broker provides
+// and returns the ones that can pass the filters.
+// If the trigger has no filter, it returns all the ETs that the broker provides.
+// The ETs for which the outcome depends on attributes that are only known at runtime are returned in both lists:
+// in the subscribed ones and separately as indeterminate.
+// map key of the declared attributes: "<namespace>/<name>" of the ET, see fetchEventTypeAttributes
+func collectSubscribedEventTypes(trigger *eventingv1.Trigger, broker *Broker, etByNamespacedName map[string]*EventType, declared map[string]map[string]string, logger *zap.SugaredLogger) ([]*EventType, []*EventType) {
+	logger.Debugw("Collecting subscribed event types", "namespace", trigger.Namespace, "trigger", trigger.Name, "broker", broker.Name, "filter", trigger.Spec.Filter, "filters", trigger.Spec.Filters)
+
 	subscribedEventTypes := make([]*EventType, 0, len(broker.ProvidedEventTypes))
-	for _, eventType := range broker.ProvidedEventTypes {
-		if et, ok := etByNamespacedName[eventType]; ok {
+	indeterminateEventTypes := make([]*EventType, 0)
+	for _, etNamespacedName := range broker.ProvidedEventTypes {
+		et, ok := etByNamespacedName[etNamespacedName]
+		if !ok {
+			continue
+		}
+
+		switch evaluateTriggerFilters(trigger, staticAttributes(et, declared[etNamespacedName])) {
+		case filterMatch:
 			subscribedEventTypes = append(subscribedEventTypes, et)
+		case filterIndeterminate:
+			subscribedEventTypes = append(subscribedEventTypes, et)
+			indeterminateEventTypes = append(indeterminateEventTypes, et)
+		default:
+			logger.Debugw("Event type does not pass the trigger filters", "namespace", trigger.Namespace, "trigger", trigger.Name, "broker", broker.Name, "eventType", etNamespacedName)
 		}
 	}
 
-	logger.Debugw("Found event types", "namespace", trigger.Namespace, "trigger", trigger.Name, "broker", broker.Name, "eventTypes", subscribedEventTypes)
-	return subscribedEventTypes
+	logger.Debugw("Found subscribed event types", "namespace", trigger.Namespace, "trigger", trigger.Name, "broker", broker.Name, "subscribedEventTypes", subscribedEventTypes, "indeterminateEventTypes", indeterminateEventTypes)
+	return subscribedEventTypes, indeterminateEventTypes
 }
 
-// fetchBrokers fetches the brokers and converts them to the representation that's consumed by the Backstage plugin.
-func fetchBrokers(clientset versioned.Interface, logger *zap.SugaredLogger) ([]*Broker, error) {
-	brokers, err := clientset.EventingV1().Brokers(metav1.NamespaceAll).List(context.Background(), metav1.ListOptions{})
+// listTriggers lists the triggers, sorted by their namespace and name.
+func listTriggers(ctx context.Context, lister resourceLister, warnings *warnings, logger *zap.SugaredLogger) []*eventingv1.Trigger {
+	ctx, span := startSpan(ctx, "listTriggers")
+	defer span.End()
 
+	triggers, err := lister.ListTriggers(ctx)
 	if err != nil {
-		logger.Errorw("Error listing brokers", "error", err)
-		return nil, err
+		recordSpanError(span, err)
+		logger.Errorw("Error listing triggers", "error", err)
+		warnings.addKind(eventingv1.Kind("Trigger"), fmt.Errorf("error listing triggers: %w", err))
+		return []*eventingv1.Trigger{}
 	}
+	sortByNamespacedName(triggers)
+	return triggers
+}
 
-	convertedBrokers := make([]*Broker, 0, len(brokers.Items))
-	for _, br := range brokers.Items {
-		convertedBroker := convertBroker(&br)
-		convertedBrokers = append(convertedBrokers, &convertedBroker)
+// listSubscriptions lists the subscriptions, sorted by their namespace and name.
+func listSubscriptions(ctx context.Context, lister resourceLister, warnings *warnings, logger *zap.SugaredLogger) []*v1.Subscription {
+	ctx, span := startSpan(ctx, "listSubscriptions")
+	defer span.End()
+
+	subscriptions, err := lister.ListSubscriptions(ctx)
+	if err != nil {
+		recordSpanError(span, err)
+		logger.Errorw("Error listing subscriptions", "error", err)
+		warnings.addKind(v1.Kind("Subscription"), fmt.Errorf("error listing subscriptions: %w", err))
+		return []*v1.Subscription{}
 	}
-	return convertedBrokers, err
+	sortByNamespacedName(subscriptions)
+	return subscriptions
 }
 
-func fetchSubscribables(ctx context.Context, dynamicClient dynamic.Interface, logger *zap.SugaredLogger) ([]*Subscribable, error) {
-	// first, fetch the subscribable CRDs
-	subscribableCRDs, err := dynamicClient.Resource(
-		schema.GroupVersionResource{
-			Group:    "apiextensions.k8s.io",
-			Version:  "v1",
-			Resource: "customresourcedefinitions",
-		},
-	).List(ctx, metav1.ListOptions{LabelSelector: labels.Set{"messaging.knative.dev/subscribable": "true"}.String()})
+// listSequences lists the sequences, sorted by their namespace and name.
+func listSequences(ctx context.Context, lister resourceLister, warnings *warnings, logger *zap.SugaredLogger) []*flowsv1.Sequence {
+	ctx, span := startSpan(ctx, "listSequences")
+	defer span.End()
 
-	if apierrors.IsNotFound(err) {
-		return nil, nil
+	sequences, err := lister.ListSequences(ctx)
+	if err != nil {
+		recordSpanError(span, err)
+		logger.Errorw("Error listing sequences", "error", err)
+		warnings.addKind(flowsv1.Kind("Sequence"), fmt.Errorf("error listing sequences: %w", err))
+		return []*flowsv1.Sequence{}
 	}
+	sortByNamespacedName(sequences)
+	return sequences
+}
+
+// listParallels lists the parallels, sorted by their namespace and name.
+func listParallels(ctx context.Context, lister resourceLister, warnings *warnings, logger *zap.SugaredLogger) []*flowsv1.Parallel {
+	ctx, span := startSpan(ctx, "listParallels")
+	defer span.End()
 
+	parallels, err := lister.ListParallels(ctx)
 	if err != nil {
-		logger.Errorw("Error listing subscribable CRDs", "error", err)
-		return nil, err
+		recordSpanError(span, err)
+		logger.Errorw("Error listing parallels", "error", err)
+		warnings.addKind(flowsv1.Kind("Parallel"), fmt.Errorf("error listing parallels: %w", err))
+		return []*flowsv1.Parallel{}
 	}
+	sortByNamespacedName(parallels)
+	return parallels
+}
 
-	// then, fetch the subscribables
-	subscribables := make([]*Subscribable, 0)
-	for _, crd := range subscribableCRDs.Items {
-		gvr, err := util.GVRFromUnstructured(&crd)
-		if err != nil {
-			logger.Errorw("Error getting GVR from CRD", "error", err)
-			return nil, err
+// sortByNamespacedName sorts the resources by their namespace and name.
+func sortByNamespacedName[T metav1.Object](items []T) {
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].GetNamespace() != items[j].GetNamespace() {
+			return items[i].GetNamespace() < items[j].GetNamespace()
 		}
+		return items[i].GetName() < items[j].GetName()
+	})
+}
 
-		subscribableResources, err := dynamicClient.Resource(gvr).Namespace(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+// fetchBrokers fetches the brokers and converts them to the representation that's consumed by the Backstage plugin.
+func fetchBrokers(ctx context.Context, lister resourceLister, warnings *warnings, logger *zap.SugaredLogger) []*Broker {
+	ctx, span := startSpan(ctx, "fetchBrokers")
+	defer span.End()
 
-		if apierrors.IsNotFound(err) {
-			continue
-		}
+	brokers, err := lister.ListBrokers(ctx)
+	if err != nil {
+		recordSpanError(span, err)
+		logger.Errorw("Error listing brokers", "error", err)
+		warnings.addKind(eventingv1.Kind("Broker"), fmt.Errorf("error listing brokers: %w", err))
+		return []*Broker{}
+	}
 
-		if err != nil {
-			logger.Errorw("Error listing subscribable resources", "error", err)
-			return nil, err
-		}
+	convertedBrokers := make([]*Broker, 0, len(brokers))
+	for _, br := range brokers {
+		convertedBroker := convertBroker(br)
+		convertedBrokers = append(convertedBrokers, &convertedBroker)
+	}
+	return convertedBrokers
+}
+
+func fetchSubscribables(ctx context.Context, lister resourceLister, warnings *warnings, discovered *discoveredKinds, logger *zap.SugaredLogger) []*Subscribable {
+	ctx, span := startSpan(ctx, "fetchSubscribables")
+	defer span.End()
+
+	subscribables := make([]*Subscribable, 0)
 
-		for _, resource := range subscribableResources.Items {
-			subscribable := convertSubscribable(gvr, &resource)
+	// first, fetch the subscribable CRDs
+	subscribableCRDs, err := lister.ListCRDs(ctx, subscribableCRDLabels)
+	if err != nil {
+		recordSpanError(span, err)
+		logger.Errorw("Error listing subscribable CRDs", "error", err)
+		warnings.addKind(crdGK, fmt.Errorf("error listing subscribable CRDs: %w", err))
+		return subscribables
+	}
+
+	// then, fetch the subscribables
+	for _, listed := range listCRDResources(ctx, lister, subscribableCRDs, warnings, discovered, "subscribable", logger) {
+		for _, resource := range listed.resources {
+			subscribable := convertSubscribable(listed.gvr, resource)
 			subscribables = append(subscribables, &subscribable)
 		}
 	}
 
-	return subscribables, nil
+	return subscribables
 }
 
-func fetchSources(ctx context.Context, dynamicClient dynamic.Interface, logger *zap.SugaredLogger) ([]*Source, error) {
-	// first, fetch the source CRDs
-	sourceCRDs, err := dynamicClient.Resource(
-		schema.GroupVersionResource{
-			Group:    "apiextensions.k8s.io",
-			Version:  "v1",
-			Resource: "customresourcedefinitions",
-		},
-	).List(ctx, metav1.ListOptions{LabelSelector: labels.Set{"duck.knative.dev/source": "true"}.String()})
+func fetchSources(ctx context.Context, lister resourceLister, warnings *warnings, discovered *discoveredKinds, logger *zap.SugaredLogger) []*sourceEntry {
+	ctx, span := startSpan(ctx, "fetchSources")
+	defer span.End()
 
-	if apierrors.IsNotFound(err) {
-		return nil, nil
-	}
+	sources := make([]*sourceEntry, 0)
 
+	// first, fetch the source CRDs
+	sourceCRDs, err := lister.ListCRDs(ctx, sourceCRDLabels)
 	if err != nil {
+		recordSpanError(span, err)
 		logger.Errorw("Error listing source CRDs", "error", err)
-		return nil, err
+		warnings.addKind(crdGK, fmt.Errorf("error listing source CRDs: %w", err))
+		return sources
 	}
 
 	// then, fetch the sources
-	sources := make([]*Source, 0)
-	for _, crd := range sourceCRDs.Items {
-		gvr, err := util.GVRFromUnstructured(&crd)
-		if err != nil {
-			logger.Errorw("Error getting GVR from CRD", "error", err)
-			return nil, err
+	for _, listed := range listCRDResources(ctx, lister, sourceCRDs, warnings, discovered, "source", logger) {
+		for _, resource := range listed.resources {
+			entry, err := convertSource(listed.gvr, *listed.crd, resource)
+			if err != nil {
+				logger.Errorw("Error converting source", "namespace", resource.GetNamespace(), "source", resource.GetName(), "error", err)
+				warnings.add(crdKind(listed.crd), resource.GetNamespace(), resource.GetName(), err)
+				continue
+			}
+			sources = append(sources, &entry)
 		}
+	}
 
-		sourceResources, err := dynamicClient.Resource(gvr).Namespace(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	return sources
+}
 
-		if apierrors.IsNotFound(err) {
-			continue
-		}
+// fetchSinks fetches the sinks of the kinds that are defined by the sink CRDs.
+func fetchSinks(ctx context.Context, lister resourceLister, warnings *warnings, discovered *discoveredKinds, logger *zap.SugaredLogger) []*unstructured.Unstructured {
+	ctx, span := startSpan(ctx, "fetchSinks")
+	defer span.End()
 
-		if err != nil {
-			logger.Errorw("Error listing source resources", "error", err)
-			return nil, err
-		}
+	sinks := make([]*unstructured.Unstructured, 0)
 
-		for _, resource := range sourceResources.Items {
-			sourceEntry, err := convertSource(gvr, crd, &resource)
-			if err != nil {
-				logger.Errorw("Error converting source", "error", err)
-				return nil, err
-			}
-			sources = append(sources, &sourceEntry)
-		}
+	// first, fetch the sink CRDs
+	addressableCRDs, err := lister.ListCRDs(ctx, sinkCRDLabels)
+	if err != nil {
+		recordSpanError(span, err)
+		logger.Errorw("Error listing sink CRDs", "error", err)
+		warnings.addKind(crdGK, fmt.Errorf("error listing sink CRDs: %w", err))
+		return sinks
+	}
+
+	// then, fetch the sinks
+	for _, listed := range listCRDResources(ctx, lister, filterSinkCRDs(addressableCRDs), warnings, discovered, "sink", logger) {
+		sinks = append(sinks, listed.resources...)
 	}
 
-	return sources, nil
+	// the sinks of all kinds are returned together, keep them in a stable order
+	sort.Slice(sinks, func(i, j int) bool {
+		return sinkKey(sinks[i]) < sinkKey(sinks[j])
+	})
+	return sinks
 }
 
 // fetchEventTypes fetches the event types and converts them to the representation that's consumed by the Backstage plugin.
-func fetchEventTypes(clientset versioned.Interface, logger *zap.SugaredLogger) ([]*EventType, error) {
-	eventTypeResponse, err := clientset.EventingV1beta2().EventTypes(metav1.NamespaceAll).List(context.Background(), metav1.ListOptions{})
+func fetchEventTypes(ctx context.Context, lister resourceLister, warnings *warnings, logger *zap.SugaredLogger) []*EventType {
+	ctx, span := startSpan(ctx, "fetchEventTypes")
+	defer span.End()
+
+	eventTypes, err := lister.ListEventTypes(ctx)
 	if err != nil {
+		recordSpanError(span, err)
 		logger.Errorw("Error listing eventTypes", "error", err)
-		return nil, err
+		warnings.addKind(eventingv1beta2.Kind("EventType"), fmt.Errorf("error listing event types: %w", err))
+		return []*EventType{}
 	}
-	eventTypes := eventTypeResponse.Items
 
 	sort.Slice(eventTypes, func(i, j int) bool {
 		if eventTypes[i].Namespace != eventTypes[j].Namespace {
@@ -451,30 +821,29 @@ func fetchEventTypes(clientset versioned.Interface, logger *zap.SugaredLogger) (
 
 	convertedEventTypes := make([]*EventType, 0, len(eventTypes))
 	for _, et := range eventTypes {
-		convertedEventType := convertEventType(&et)
+		convertedEventType := convertEventType(et)
 		convertedEventTypes = append(convertedEventTypes, &convertedEventType)
 	}
 
-	return convertedEventTypes, err
+	return convertedEventTypes
 }
 
-// getSubscriberBackstageId fetches the subscriber resource and returns the Backstage ID if it's present.
-func getSubscriberBackstageId(ctx context.Context, client dynamic.Interface, subRef *duckv1.KReference, logger *zap.SugaredLogger) (string, error) {
-	refGvr, _ := meta.UnsafeGuessKindToResource(schema.FromAPIVersionAndKind(subRef.APIVersion, subRef.Kind))
+// fetchEventTypeAttributes fetches the CloudEvents attributes, e.g. the extensions, that the event types declare with a
+// fixed value. The trigger filters on the attributes that can't be fetched are evaluated as indeterminate.
+// map key: "<namespace>/<name>" of the event type
+func fetchEventTypeAttributes(ctx context.Context, lister resourceLister, logger *zap.SugaredLogger) map[string]map[string]string {
+	ctx, span := startSpan(ctx, "fetchEventTypeAttributes")
+	defer span.End()
 
-	resource, err := client.Resource(refGvr).Namespace(subRef.Namespace).Get(ctx, subRef.Name, metav1.GetOptions{})
-	if apierrors.IsNotFound(err) {
-		logger.Debugw("Subscriber resource not found", "resource", subRef.Name)
-		return "", nil
-	}
+	eventTypes, err := lister.ListEventTypeAttributes(ctx)
 	if err != nil {
-		logger.Errorw("Error fetching resource", "error", err)
-		return "", err
+		logger.Debugw("Error listing the attributes of the event types, the filters on the extensions are indeterminate", "error", err)
+		return nil
 	}
 
-	// check if the resource has the Backstage label
-	if backstageId, ok := resource.GetLabels()[BackstageKubernetesIDLabel]; ok {
-		return backstageId, nil
+	attributes := make(map[string]map[string]string, len(eventTypes))
+	for _, et := range eventTypes {
+		attributes[util.NamespacedName(et.Namespace, et.Name)] = declaredAttributes(et)
 	}
-	return "", nil
+	return attributes
 }
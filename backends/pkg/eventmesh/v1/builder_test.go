@@ -2,25 +2,33 @@ package v1
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"go.uber.org/zap"
 
 	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
 	"k8s.io/utils/ptr"
 
 	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 	"knative.dev/pkg/injection/clients/dynamicclient"
 
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
 	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
 	eventingv1beta2 "knative.dev/eventing/pkg/apis/eventing/v1beta2"
+	eventingv1beta3 "knative.dev/eventing/pkg/apis/eventing/v1beta3"
+	flowsv1 "knative.dev/eventing/pkg/apis/flows/v1"
 	messagingv1 "knative.dev/eventing/pkg/apis/messaging/v1"
 	sourcesv1 "knative.dev/eventing/pkg/apis/sources/v1"
 
@@ -31,13 +39,21 @@ import (
 
 func TestBuildEventMesh(t *testing.T) {
 	tests := []struct {
-		name         string
-		brokers      []*eventingv1.Broker
-		eventTypes   []*eventingv1beta2.EventType
-		triggers     []*eventingv1.Trigger
-		extraObjects []runtime.Object
-		want         EventMesh
-		error        bool
+		name       string
+		brokers    []*eventingv1.Broker
+		eventTypes []*eventingv1beta2.EventType
+		// eventTypeAttributes are the same event types in the version that declares their attributes
+		eventTypeAttributes []*eventingv1beta3.EventType
+		triggers            []*eventingv1.Trigger
+		subscriptions       []*messagingv1.Subscription
+		sequences           []*flowsv1.Sequence
+		parallels           []*flowsv1.Parallel
+		extraObjects        []runtime.Object
+		namespaces          []string
+		// listErrors are the errors returned when listing the resources, by resource
+		listErrors map[string]error
+		want       EventMesh
+		error      bool
 	}{
 		{
 			name: "With 1 broker, 1 type, 1 trigger",
@@ -116,9 +132,25 @@ func TestBuildEventMesh(t *testing.T) {
 							Name:      "test-broker",
 						},
 						ConsumedBy: []string{"test-subscriber"},
+						Consumers:  []EventTypeConsumer{{BackstageID: "test-subscriber", Hop: &GroupKindNamespacedName{Kind: "Service", Namespace: "test-ns", Name: "test-subscriber"}}},
 					},
 				},
+				Triggers: []Trigger{
+					{
+						Name:        "test-trigger",
+						Namespace:   "test-ns",
+						Broker:      GroupKindNamespacedName{Group: "eventing.knative.dev", Kind: "Broker", Namespace: "test-ns", Name: "test-broker"},
+						Filter:      map[string]string{"type": "test-eventtype-type"},
+						Subscriber:  Destination{Ref: &GroupKindNamespacedName{Group: "", Kind: "Service", Namespace: "test-ns", Name: "test-subscriber"}},
+						BackstageID: "test-subscriber",
+						Conditions:  []Condition{},
+					},
+				},
+				Subscriptions: []Subscription{},
 				Subscribables: make([]Subscribable, 0),
+				Sequences:     []Sequence{},
+				Parallels:     []Parallel{},
+				Sinks:         []Sink{},
 				Sources:       make([]Source, 0),
 			},
 		},
@@ -154,7 +186,12 @@ func TestBuildEventMesh(t *testing.T) {
 						ConsumedBy: []string{},
 					},
 				},
+				Triggers:      []Trigger{},
+				Subscriptions: []Subscription{},
 				Subscribables: make([]Subscribable, 0),
+				Sequences:     []Sequence{},
+				Parallels:     []Parallel{},
+				Sinks:         []Sink{},
 				Sources:       make([]Source, 0),
 			},
 		},
@@ -200,7 +237,12 @@ func TestBuildEventMesh(t *testing.T) {
 						ConsumedBy: []string{},
 					},
 				},
+				Triggers:      []Trigger{},
+				Subscriptions: []Subscription{},
 				Subscribables: make([]Subscribable, 0),
+				Sequences:     []Sequence{},
+				Parallels:     []Parallel{},
+				Sinks:         []Sink{},
 				Sources:       make([]Source, 0),
 			},
 		},
@@ -259,7 +301,12 @@ func TestBuildEventMesh(t *testing.T) {
 						ConsumedBy: []string{},
 					},
 				},
+				Triggers:      []Trigger{},
+				Subscriptions: []Subscription{},
 				Subscribables: make([]Subscribable, 0),
+				Sequences:     []Sequence{},
+				Parallels:     []Parallel{},
+				Sinks:         []Sink{},
 				Sources:       make([]Source, 0),
 			},
 		},
@@ -318,7 +365,22 @@ func TestBuildEventMesh(t *testing.T) {
 						ConsumedBy: []string{},
 					},
 				},
+				Triggers: []Trigger{
+					{
+						Name:        "test-trigger",
+						Namespace:   "test-ns",
+						Broker:      GroupKindNamespacedName{Group: "eventing.knative.dev", Kind: "Broker", Namespace: "test-ns", Name: "UNKNOWN-BROKER"},
+						Filter:      map[string]string{"type": "test-eventtype-type"},
+						Subscriber:  Destination{Ref: &GroupKindNamespacedName{Group: "", Kind: "Service", Namespace: "test-ns", Name: "test-subscriber"}},
+						BackstageID: "test-subscriber",
+						Conditions:  []Condition{},
+					},
+				},
+				Subscriptions: []Subscription{},
 				Subscribables: make([]Subscribable, 0),
+				Sequences:     []Sequence{},
+				Parallels:     []Parallel{},
+				Sinks:         []Sink{},
 				Sources:       make([]Source, 0),
 			},
 		},
@@ -369,7 +431,21 @@ func TestBuildEventMesh(t *testing.T) {
 						ConsumedBy: []string{},
 					},
 				},
+				Triggers: []Trigger{
+					{
+						Name:       "test-trigger",
+						Namespace:  "test-ns",
+						Broker:     GroupKindNamespacedName{Group: "eventing.knative.dev", Kind: "Broker", Namespace: "test-ns", Name: "test-broker"},
+						Filter:     map[string]string{"type": "test-eventtype-type"},
+						Subscriber: Destination{Ref: &GroupKindNamespacedName{Group: "", Kind: "Service", Namespace: "test-ns", Name: "test-subscriber"}},
+						Conditions: []Condition{},
+					},
+				},
+				Subscriptions: []Subscription{},
 				Subscribables: make([]Subscribable, 0),
+				Sequences:     []Sequence{},
+				Parallels:     []Parallel{},
+				Sinks:         []Sink{},
 				Sources:       make([]Source, 0),
 			},
 		},
@@ -428,7 +504,21 @@ func TestBuildEventMesh(t *testing.T) {
 						ConsumedBy: []string{},
 					},
 				},
+				Triggers: []Trigger{
+					{
+						Name:       "test-trigger",
+						Namespace:  "test-ns",
+						Broker:     GroupKindNamespacedName{Group: "eventing.knative.dev", Kind: "Broker", Namespace: "test-ns", Name: "test-broker"},
+						Filter:     map[string]string{"type": "test-eventtype-type"},
+						Subscriber: Destination{Ref: &GroupKindNamespacedName{Group: "", Kind: "Service", Namespace: "test-ns", Name: "test-subscriber"}},
+						Conditions: []Condition{},
+					},
+				},
+				Subscriptions: []Subscription{},
 				Subscribables: make([]Subscribable, 0),
+				Sequences:     []Sequence{},
+				Parallels:     []Parallel{},
+				Sinks:         []Sink{},
 				Sources:       make([]Source, 0),
 			},
 		},
@@ -491,6 +581,7 @@ func TestBuildEventMesh(t *testing.T) {
 							Name:      "test-broker",
 						},
 						ConsumedBy: []string{"test-subscriber"},
+						Consumers:  []EventTypeConsumer{{BackstageID: "test-subscriber", Hop: &GroupKindNamespacedName{Kind: "Service", Namespace: "test-ns", Name: "test-subscriber"}}},
 					},
 					{
 						Name:      "test-eventtype-2",
@@ -503,9 +594,24 @@ func TestBuildEventMesh(t *testing.T) {
 							Name:      "test-broker",
 						},
 						ConsumedBy: []string{"test-subscriber"},
+						Consumers:  []EventTypeConsumer{{BackstageID: "test-subscriber", Hop: &GroupKindNamespacedName{Kind: "Service", Namespace: "test-ns", Name: "test-subscriber"}}},
 					},
 				},
+				Triggers: []Trigger{
+					{
+						Name:        "test-trigger",
+						Namespace:   "test-ns",
+						Broker:      GroupKindNamespacedName{Group: "eventing.knative.dev", Kind: "Broker", Namespace: "test-ns", Name: "test-broker"},
+						Subscriber:  Destination{Ref: &GroupKindNamespacedName{Group: "", Kind: "Service", Namespace: "test-ns", Name: "test-subscriber"}},
+						BackstageID: "test-subscriber",
+						Conditions:  []Condition{},
+					},
+				},
+				Subscriptions: []Subscription{},
 				Subscribables: make([]Subscribable, 0),
+				Sequences:     []Sequence{},
+				Parallels:     []Parallel{},
+				Sinks:         []Sink{},
 				Sources:       make([]Source, 0),
 			},
 		},
@@ -569,6 +675,7 @@ func TestBuildEventMesh(t *testing.T) {
 							Name:      "test-broker",
 						},
 						ConsumedBy: []string{"test-subscriber"},
+						Consumers:  []EventTypeConsumer{{BackstageID: "test-subscriber", Hop: &GroupKindNamespacedName{Kind: "Service", Namespace: "test-ns", Name: "test-subscriber"}}},
 					},
 					{
 						Name:      "test-eventtype-2",
@@ -581,176 +688,1735 @@ func TestBuildEventMesh(t *testing.T) {
 							Name:      "test-broker",
 						},
 						ConsumedBy: []string{"test-subscriber"},
+						Consumers:  []EventTypeConsumer{{BackstageID: "test-subscriber", Hop: &GroupKindNamespacedName{Kind: "Service", Namespace: "test-ns", Name: "test-subscriber"}}},
+					},
+				},
+				Triggers: []Trigger{
+					{
+						Name:        "test-trigger",
+						Namespace:   "test-ns",
+						Broker:      GroupKindNamespacedName{Group: "eventing.knative.dev", Kind: "Broker", Namespace: "test-ns", Name: "test-broker"},
+						Filter:      map[string]string{"type": ""},
+						Subscriber:  Destination{Ref: &GroupKindNamespacedName{Group: "", Kind: "Service", Namespace: "test-ns", Name: "test-subscriber"}},
+						BackstageID: "test-subscriber",
+						Conditions:  []Condition{},
 					},
 				},
+				Subscriptions: []Subscription{},
 				Subscribables: make([]Subscribable, 0),
+				Sequences:     []Sequence{},
+				Parallels:     []Parallel{},
+				Sinks:         []Sink{},
 				Sources:       make([]Source, 0),
 			},
 		},
 		{
-			name: "With 1 channel, 1 type",
+			name: "Triggers with source filters subscribe to the event types of that source",
+			brokers: []*eventingv1.Broker{
+				testingv1.NewBroker("test-broker", "test-ns"),
+			},
 			eventTypes: []*eventingv1beta2.EventType{
-				testingv1beta2.NewEventType("test-eventtype", "test-ns",
+				testingv1beta2.NewEventType("test-eventtype-1", "test-ns",
 					testingv1beta2.WithEventTypeType("test-eventtype-type"),
-					testingv1beta2.WithEventTypeReference(reference("messaging.knative.dev/v1", "InMemoryChannel", "test-ns", "test-imc")),
+					testingv1beta2.WithEventTypeSource(&apis.URL{Scheme: "https", Host: "test-source-1"}),
+					testingv1beta2.WithEventTypeReference(brokerReference("test-broker", "test-ns")),
+				),
+				testingv1beta2.NewEventType("test-eventtype-2", "test-ns",
+					testingv1beta2.WithEventTypeType("test-eventtype-type"),
+					testingv1beta2.WithEventTypeSource(&apis.URL{Scheme: "https", Host: "test-source-2"}),
+					testingv1beta2.WithEventTypeReference(brokerReference("test-broker", "test-ns")),
 				),
 			},
-			triggers: []*eventingv1.Trigger{},
-			extraObjects: []runtime.Object{
-				&apiextensionsv1.CustomResourceDefinition{
-					ObjectMeta: metav1.ObjectMeta{
-						Name: "inmemorychannels.messaging.knative.dev",
-						Labels: map[string]string{
-							"messaging.knative.dev/subscribable": "true",
-						},
-					},
-					Spec: apiextensionsv1.CustomResourceDefinitionSpec{
-						Group: "messaging.knative.dev",
-						Names: apiextensionsv1.CustomResourceDefinitionNames{
-							Kind:     "InMemoryChannel",
-							ListKind: "InMemoryChannelList",
-							Plural:   "inmemorychannels",
+			triggers: []*eventingv1.Trigger{
+				testingv1.NewTrigger("test-trigger-1", "test-ns", "test-broker",
+					testingv1.WithTriggerSubscriberRef(
+						metav1.GroupVersionKind{
+							Group:   "",
+							Version: "v1",
+							Kind:    "Service",
 						},
-						Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
-							{
-								Name:    "v1",
-								Served:  true,
-								Storage: true,
-							},
+						"test-subscriber-1",
+						"test-ns",
+					),
+					WithEventTypeFilter("test-eventtype-type"),
+					WithAttributeFilter("source", "https://test-source-1"),
+				),
+				testingv1.NewTrigger("test-trigger-2", "test-ns", "test-broker",
+					testingv1.WithTriggerSubscriberRef(
+						metav1.GroupVersionKind{
+							Group:   "",
+							Version: "v1",
+							Kind:    "Service",
 						},
+						"test-subscriber-2",
+						"test-ns",
+					),
+					WithAttributeFilter("source", "https://test-source-2"),
+					WithAttributeFilter("myextension", "foo"),
+				),
+			},
+			extraObjects: []runtime.Object{
+				&corev1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-subscriber-1",
+						Namespace: "test-ns",
+						Labels:    map[string]string{"backstage.io/kubernetes-id": "test-subscriber-1"},
 					},
 				},
-				&messagingv1.InMemoryChannel{
+				&corev1.Service{
 					ObjectMeta: metav1.ObjectMeta{
-						Name:      "test-imc",
+						Name:      "test-subscriber-2",
 						Namespace: "test-ns",
+						Labels:    map[string]string{"backstage.io/kubernetes-id": "test-subscriber-2"},
 					},
 				},
 			},
 			want: EventMesh{
-				Brokers: []Broker{},
+				Brokers: []Broker{
+					{
+						Name:      "test-broker",
+						Namespace: "test-ns",
+						ProvidedEventTypes: []string{
+							"test-ns/test-eventtype-1",
+							"test-ns/test-eventtype-2",
+						}},
+				},
 				EventTypes: []EventType{
 					{
-						Name:      "test-eventtype",
+						Name:      "test-eventtype-1",
 						Namespace: "test-ns",
 						Type:      "test-eventtype-type",
+						Source:    ptr.To("https://test-source-1"),
 						Reference: &GroupKindNamespacedName{
-							Group:     "messaging.knative.dev",
-							Kind:      "InMemoryChannel",
+							Group:     "eventing.knative.dev",
+							Kind:      "Broker",
 							Namespace: "test-ns",
-							Name:      "test-imc",
+							Name:      "test-broker",
 						},
-						ConsumedBy: []string{},
+						ConsumedBy: []string{"test-subscriber-1"},
+						Consumers:  []EventTypeConsumer{{BackstageID: "test-subscriber-1", Hop: &GroupKindNamespacedName{Kind: "Service", Namespace: "test-ns", Name: "test-subscriber-1"}}},
+					},
+					{
+						Name:      "test-eventtype-2",
+						Namespace: "test-ns",
+						Type:      "test-eventtype-type",
+						Source:    ptr.To("https://test-source-2"),
+						Reference: &GroupKindNamespacedName{
+							Group:     "eventing.knative.dev",
+							Kind:      "Broker",
+							Namespace: "test-ns",
+							Name:      "test-broker",
+						},
+						ConsumedBy:              []string{"test-subscriber-2"},
+						IndeterminateConsumedBy: []string{"test-subscriber-2"},
+						Consumers:               []EventTypeConsumer{{BackstageID: "test-subscriber-2", Hop: &GroupKindNamespacedName{Kind: "Service", Namespace: "test-ns", Name: "test-subscriber-2"}, Indeterminate: true}},
 					},
 				},
-				Subscribables: []Subscribable{
+				Triggers: []Trigger{
 					{
-						Group:              "messaging.knative.dev",
-						Kind:               "InMemoryChannel",
-						Name:               "test-imc",
-						Namespace:          "test-ns",
-						ProvidedEventTypes: []string{"test-ns/test-eventtype"},
+						Name:        "test-trigger-1",
+						Namespace:   "test-ns",
+						Broker:      GroupKindNamespacedName{Group: "eventing.knative.dev", Kind: "Broker", Namespace: "test-ns", Name: "test-broker"},
+						Filter:      map[string]string{"type": "test-eventtype-type", "source": "https://test-source-1"},
+						Subscriber:  Destination{Ref: &GroupKindNamespacedName{Group: "", Kind: "Service", Namespace: "test-ns", Name: "test-subscriber-1"}},
+						BackstageID: "test-subscriber-1",
+						Conditions:  []Condition{},
+					},
+					{
+						Name:        "test-trigger-2",
+						Namespace:   "test-ns",
+						Broker:      GroupKindNamespacedName{Group: "eventing.knative.dev", Kind: "Broker", Namespace: "test-ns", Name: "test-broker"},
+						Filter:      map[string]string{"source": "https://test-source-2", "myextension": "foo"},
+						Subscriber:  Destination{Ref: &GroupKindNamespacedName{Group: "", Kind: "Service", Namespace: "test-ns", Name: "test-subscriber-2"}},
+						BackstageID: "test-subscriber-2",
+						Conditions:  []Condition{},
 					},
 				},
-				Sources: make([]Source, 0),
+				Subscriptions: []Subscription{},
+				Subscribables: make([]Subscribable, 0),
+				Sequences:     []Sequence{},
+				Parallels:     []Parallel{},
+				Sinks:         []Sink{},
+				Sources:       make([]Source, 0),
 			},
 		},
 		{
-			name: "With 1 channel, 1 type, 1 subscriber",
+			name: "Trigger with filters subscribes to the event types the filters can match",
+			brokers: []*eventingv1.Broker{
+				testingv1.NewBroker("test-broker", "test-ns"),
+			},
 			eventTypes: []*eventingv1beta2.EventType{
-				testingv1beta2.NewEventType("test-eventtype", "test-ns",
-					testingv1beta2.WithEventTypeType("test-eventtype-type"),
-					testingv1beta2.WithEventTypeReference(reference("messaging.knative.dev/v1", "InMemoryChannel", "test-ns", "test-imc")),
+				testingv1beta2.NewEventType("test-eventtype-1", "test-ns",
+					testingv1beta2.WithEventTypeType("test-eventtype-type-1"),
+					testingv1beta2.WithEventTypeReference(brokerReference("test-broker", "test-ns")),
+				),
+				testingv1beta2.NewEventType("test-eventtype-2", "test-ns",
+					testingv1beta2.WithEventTypeType("test-eventtype-type-2"),
+					testingv1beta2.WithEventTypeSource(&apis.URL{Scheme: "https", Host: "test-source"}),
+					testingv1beta2.WithEventTypeReference(brokerReference("test-broker", "test-ns")),
+				),
+				testingv1beta2.NewEventType("test-eventtype-3", "test-ns",
+					testingv1beta2.WithEventTypeType("test-eventtype-type-3"),
+					testingv1beta2.WithEventTypeSource(&apis.URL{Scheme: "https", Host: "other-source"}),
+					testingv1beta2.WithEventTypeReference(brokerReference("test-broker", "test-ns")),
 				),
 			},
-			triggers: []*eventingv1.Trigger{},
-			extraObjects: []runtime.Object{
-				&apiextensionsv1.CustomResourceDefinition{
-					ObjectMeta: metav1.ObjectMeta{
-						Name: "inmemorychannels.messaging.knative.dev",
-						Labels: map[string]string{
-							"messaging.knative.dev/subscribable": "true",
-						},
-					},
-					Spec: apiextensionsv1.CustomResourceDefinitionSpec{
-						Group: "messaging.knative.dev",
-						Names: apiextensionsv1.CustomResourceDefinitionNames{
-							Kind:     "InMemoryChannel",
-							ListKind: "InMemoryChannelList",
-							Plural:   "inmemorychannels",
-						},
-						Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
-							{
-								Name:    "v1",
-								Served:  true,
-								Storage: true,
-							},
+			triggers: []*eventingv1.Trigger{
+				testingv1.NewTrigger("test-trigger", "test-ns", "test-broker",
+					testingv1.WithTriggerSubscriberRef(
+						metav1.GroupVersionKind{
+							Group:   "",
+							Version: "v1",
+							Kind:    "Service",
 						},
-					},
-				},
-				&messagingv1.InMemoryChannel{
+						"test-subscriber",
+						"test-ns",
+					),
+					// filters take precedence over the filter
+					WithEventTypeFilter("test-eventtype-type-3"),
+					WithTriggerFilters(eventingv1.SubscriptionsAPIFilter{
+						CESQL: "type = 'test-eventtype-type-1' OR (source = 'https://test-source' AND subject = 'foo')",
+					}),
+				),
+			},
+			extraObjects: []runtime.Object{
+				&corev1.Service{
 					ObjectMeta: metav1.ObjectMeta{
-						Name:      "test-imc",
+						Name:      "test-subscriber",
 						Namespace: "test-ns",
+						Labels:    map[string]string{"backstage.io/kubernetes-id": "test-subscriber"},
 					},
 				},
 			},
 			want: EventMesh{
-				Brokers: []Broker{},
+				Brokers: []Broker{
+					{
+						Name:      "test-broker",
+						Namespace: "test-ns",
+						ProvidedEventTypes: []string{
+							"test-ns/test-eventtype-1",
+							"test-ns/test-eventtype-2",
+							"test-ns/test-eventtype-3",
+						}},
+				},
 				EventTypes: []EventType{
 					{
-						Name:      "test-eventtype",
+						Name:      "test-eventtype-1",
 						Namespace: "test-ns",
-						Type:      "test-eventtype-type",
+						Type:      "test-eventtype-type-1",
 						Reference: &GroupKindNamespacedName{
-							Group:     "messaging.knative.dev",
-							Kind:      "InMemoryChannel",
+							Group:     "eventing.knative.dev",
+							Kind:      "Broker",
 							Namespace: "test-ns",
-							Name:      "test-imc",
+							Name:      "test-broker",
+						},
+						ConsumedBy: []string{"test-subscriber"},
+						Consumers:  []EventTypeConsumer{{BackstageID: "test-subscriber", Hop: &GroupKindNamespacedName{Kind: "Service", Namespace: "test-ns", Name: "test-subscriber"}}},
+					},
+					{
+						Name:      "test-eventtype-2",
+						Namespace: "test-ns",
+						Type:      "test-eventtype-type-2",
+						Source:    ptr.To("https://test-source"),
+						Reference: &GroupKindNamespacedName{
+							Group:     "eventing.knative.dev",
+							Kind:      "Broker",
+							Namespace: "test-ns",
+							Name:      "test-broker",
+						},
+						ConsumedBy:              []string{"test-subscriber"},
+						IndeterminateConsumedBy: []string{"test-subscriber"},
+						Consumers:               []EventTypeConsumer{{BackstageID: "test-subscriber", Hop: &GroupKindNamespacedName{Kind: "Service", Namespace: "test-ns", Name: "test-subscriber"}, Indeterminate: true}},
+					},
+					{
+						Name:      "test-eventtype-3",
+						Namespace: "test-ns",
+						Type:      "test-eventtype-type-3",
+						Source:    ptr.To("https://other-source"),
+						Reference: &GroupKindNamespacedName{
+							Group:     "eventing.knative.dev",
+							Kind:      "Broker",
+							Namespace: "test-ns",
+							Name:      "test-broker",
 						},
 						ConsumedBy: []string{},
 					},
 				},
-				Subscribables: []Subscribable{
+				Triggers: []Trigger{
 					{
-						Group:              "messaging.knative.dev",
-						Kind:               "InMemoryChannel",
-						Name:               "test-imc",
-						Namespace:          "test-ns",
-						ProvidedEventTypes: []string{"test-ns/test-eventtype"},
+						Name:      "test-trigger",
+						Namespace: "test-ns",
+						Broker:    GroupKindNamespacedName{Group: "eventing.knative.dev", Kind: "Broker", Namespace: "test-ns", Name: "test-broker"},
+						Filter:    map[string]string{"type": "test-eventtype-type-3"},
+						Filters: []SubscriptionsAPIFilter{
+							{CESQL: "type = 'test-eventtype-type-1' OR (source = 'https://test-source' AND subject = 'foo')"},
+						},
+						Subscriber:  Destination{Ref: &GroupKindNamespacedName{Group: "", Kind: "Service", Namespace: "test-ns", Name: "test-subscriber"}},
+						BackstageID: "test-subscriber",
+						Conditions:  []Condition{},
 					},
 				},
-				Sources: make([]Source, 0),
+				Subscriptions: []Subscription{},
+				Subscribables: make([]Subscribable, 0),
+				Sequences:     []Sequence{},
+				Parallels:     []Parallel{},
+				Sinks:         []Sink{},
+				Sources:       make([]Source, 0),
 			},
 		},
 		{
-			name: "With 1 source, 2 types",
+			name: "Trigger filters on extensions are evaluated against the attributes the event types declare",
+			brokers: []*eventingv1.Broker{
+				testingv1.NewBroker("test-broker", "test-ns"),
+			},
 			eventTypes: []*eventingv1beta2.EventType{
-				testingv1beta2.NewEventType("test-eventtype", "test-ns",
+				testingv1beta2.NewEventType("test-eventtype-1", "test-ns",
 					testingv1beta2.WithEventTypeType("test-eventtype-type"),
+					testingv1beta2.WithEventTypeReference(brokerReference("test-broker", "test-ns")),
 				),
-				testingv1beta2.NewEventType("test-eventtype-used-in-source", "test-ns",
-					testingv1beta2.WithEventTypeType("dev.knative.apiserver.resource.add"),
+				testingv1beta2.NewEventType("test-eventtype-2", "test-ns",
+					testingv1beta2.WithEventTypeType("test-eventtype-type"),
+					testingv1beta2.WithEventTypeReference(brokerReference("test-broker", "test-ns")),
+				),
+				testingv1beta2.NewEventType("test-eventtype-3", "test-ns",
+					testingv1beta2.WithEventTypeType("test-eventtype-type"),
+					testingv1beta2.WithEventTypeReference(brokerReference("test-broker", "test-ns")),
+				),
+				testingv1beta2.NewEventType("test-eventtype-4", "test-ns",
+					testingv1beta2.WithEventTypeType("test-eventtype-type"),
+					testingv1beta2.WithEventTypeReference(brokerReference("test-broker", "test-ns")),
+				),
+			},
+			eventTypeAttributes: []*eventingv1beta3.EventType{
+				eventTypeWithAttributes("test-eventtype-1", "test-ns", eventingv1beta3.EventAttributeDefinition{Name: "region", Required: true, Value: "eu-west"}),
+				eventTypeWithAttributes("test-eventtype-2", "test-ns", eventingv1beta3.EventAttributeDefinition{Name: "region", Required: true, Value: "us-east"}),
+				// an optional attribute may be missing, a template may have any value
+				eventTypeWithAttributes("test-eventtype-3", "test-ns", eventingv1beta3.EventAttributeDefinition{Name: "region", Value: "eu-west"}),
+				eventTypeWithAttributes("test-eventtype-4", "test-ns", eventingv1beta3.EventAttributeDefinition{Name: "region", Required: true, Value: "{region}"}),
+			},
+			triggers: []*eventingv1.Trigger{
+				testingv1.NewTrigger("test-trigger", "test-ns", "test-broker",
+					testingv1.WithTriggerSubscriberRef(
+						metav1.GroupVersionKind{
+							Group:   "",
+							Version: "v1",
+							Kind:    "Service",
+						},
+						"test-subscriber",
+						"test-ns",
+					),
+					WithTriggerFilters(eventingv1.SubscriptionsAPIFilter{
+						Prefix: map[string]string{"region": "eu-"},
+					}),
+				),
+			},
+			extraObjects: []runtime.Object{
+				&corev1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-subscriber",
+						Namespace: "test-ns",
+						Labels:    map[string]string{"backstage.io/kubernetes-id": "test-subscriber"},
+					},
+				},
+			},
+			want: EventMesh{
+				Brokers: []Broker{
+					{
+						Name:      "test-broker",
+						Namespace: "test-ns",
+						ProvidedEventTypes: []string{
+							"test-ns/test-eventtype-1",
+							"test-ns/test-eventtype-2",
+							"test-ns/test-eventtype-3",
+							"test-ns/test-eventtype-4",
+						}},
+				},
+				EventTypes: []EventType{
+					{
+						Name:       "test-eventtype-1",
+						Namespace:  "test-ns",
+						Type:       "test-eventtype-type",
+						Reference:  &GroupKindNamespacedName{Group: "eventing.knative.dev", Kind: "Broker", Namespace: "test-ns", Name: "test-broker"},
+						ConsumedBy: []string{"test-subscriber"},
+						Consumers:  []EventTypeConsumer{{BackstageID: "test-subscriber", Hop: &GroupKindNamespacedName{Kind: "Service", Namespace: "test-ns", Name: "test-subscriber"}}},
+					},
+					{
+						Name:       "test-eventtype-2",
+						Namespace:  "test-ns",
+						Type:       "test-eventtype-type",
+						Reference:  &GroupKindNamespacedName{Group: "eventing.knative.dev", Kind: "Broker", Namespace: "test-ns", Name: "test-broker"},
+						ConsumedBy: []string{},
+					},
+					{
+						Name:                    "test-eventtype-3",
+						Namespace:               "test-ns",
+						Type:                    "test-eventtype-type",
+						Reference:               &GroupKindNamespacedName{Group: "eventing.knative.dev", Kind: "Broker", Namespace: "test-ns", Name: "test-broker"},
+						ConsumedBy:              []string{"test-subscriber"},
+						IndeterminateConsumedBy: []string{"test-subscriber"},
+						Consumers:               []EventTypeConsumer{{BackstageID: "test-subscriber", Hop: &GroupKindNamespacedName{Kind: "Service", Namespace: "test-ns", Name: "test-subscriber"}, Indeterminate: true}},
+					},
+					{
+						Name:                    "test-eventtype-4",
+						Namespace:               "test-ns",
+						Type:                    "test-eventtype-type",
+						Reference:               &GroupKindNamespacedName{Group: "eventing.knative.dev", Kind: "Broker", Namespace: "test-ns", Name: "test-broker"},
+						ConsumedBy:              []string{"test-subscriber"},
+						IndeterminateConsumedBy: []string{"test-subscriber"},
+						Consumers:               []EventTypeConsumer{{BackstageID: "test-subscriber", Hop: &GroupKindNamespacedName{Kind: "Service", Namespace: "test-ns", Name: "test-subscriber"}, Indeterminate: true}},
+					},
+				},
+				Triggers: []Trigger{
+					{
+						Name:      "test-trigger",
+						Namespace: "test-ns",
+						Broker:    GroupKindNamespacedName{Group: "eventing.knative.dev", Kind: "Broker", Namespace: "test-ns", Name: "test-broker"},
+						Filters: []SubscriptionsAPIFilter{
+							{Prefix: map[string]string{"region": "eu-"}},
+						},
+						Subscriber:  Destination{Ref: &GroupKindNamespacedName{Group: "", Kind: "Service", Namespace: "test-ns", Name: "test-subscriber"}},
+						BackstageID: "test-subscriber",
+						Conditions:  []Condition{},
+					},
+				},
+				Subscriptions: []Subscription{},
+				Subscribables: make([]Subscribable, 0),
+				Sequences:     []Sequence{},
+				Parallels:     []Parallel{},
+				Sinks:         []Sink{},
+				Sources:       make([]Source, 0),
+			},
+		},
+		{
+			name: "With 1 channel, 1 type",
+			eventTypes: []*eventingv1beta2.EventType{
+				testingv1beta2.NewEventType("test-eventtype", "test-ns",
+					testingv1beta2.WithEventTypeType("test-eventtype-type"),
+					testingv1beta2.WithEventTypeReference(reference("messaging.knative.dev/v1", "InMemoryChannel", "test-ns", "test-imc")),
+				),
+			},
+			triggers: []*eventingv1.Trigger{},
+			extraObjects: []runtime.Object{
+				&apiextensionsv1.CustomResourceDefinition{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "inmemorychannels.messaging.knative.dev",
+						Labels: map[string]string{
+							"messaging.knative.dev/subscribable": "true",
+						},
+					},
+					Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+						Group: "messaging.knative.dev",
+						Names: apiextensionsv1.CustomResourceDefinitionNames{
+							Kind:     "InMemoryChannel",
+							ListKind: "InMemoryChannelList",
+							Plural:   "inmemorychannels",
+						},
+						Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+							{
+								Name:    "v1",
+								Served:  true,
+								Storage: true,
+							},
+						},
+					},
+				},
+				&messagingv1.InMemoryChannel{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-imc",
+						Namespace: "test-ns",
+					},
+				},
+			},
+			want: EventMesh{
+				DiscoveredKinds: []DiscoveredKind{
+					{Group: "messaging.knative.dev", Kind: "InMemoryChannel", Resource: "inmemorychannels", Version: "v1"},
+				},
+				Brokers: []Broker{},
+				EventTypes: []EventType{
+					{
+						Name:      "test-eventtype",
+						Namespace: "test-ns",
+						Type:      "test-eventtype-type",
+						Reference: &GroupKindNamespacedName{
+							Group:     "messaging.knative.dev",
+							Kind:      "InMemoryChannel",
+							Namespace: "test-ns",
+							Name:      "test-imc",
+						},
+						ConsumedBy: []string{},
+					},
+				},
+				Triggers:      []Trigger{},
+				Subscriptions: []Subscription{},
+				Subscribables: []Subscribable{
+					{
+						Group:              "messaging.knative.dev",
+						Kind:               "InMemoryChannel",
+						Name:               "test-imc",
+						Namespace:          "test-ns",
+						ProvidedEventTypes: []string{"test-ns/test-eventtype"},
+					},
+				},
+				Sequences: []Sequence{},
+				Parallels: []Parallel{},
+				Sinks:     []Sink{},
+				Sources:   make([]Source, 0),
+			},
+		},
+		{
+			name: "With 1 channel, 1 type, 1 subscriber",
+			eventTypes: []*eventingv1beta2.EventType{
+				testingv1beta2.NewEventType("test-eventtype", "test-ns",
+					testingv1beta2.WithEventTypeType("test-eventtype-type"),
+					testingv1beta2.WithEventTypeReference(reference("messaging.knative.dev/v1", "InMemoryChannel", "test-ns", "test-imc")),
+				),
+			},
+			triggers: []*eventingv1.Trigger{},
+			extraObjects: []runtime.Object{
+				&apiextensionsv1.CustomResourceDefinition{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "inmemorychannels.messaging.knative.dev",
+						Labels: map[string]string{
+							"messaging.knative.dev/subscribable": "true",
+						},
+					},
+					Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+						Group: "messaging.knative.dev",
+						Names: apiextensionsv1.CustomResourceDefinitionNames{
+							Kind:     "InMemoryChannel",
+							ListKind: "InMemoryChannelList",
+							Plural:   "inmemorychannels",
+						},
+						Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+							{
+								Name:    "v1",
+								Served:  true,
+								Storage: true,
+							},
+						},
+					},
+				},
+				&messagingv1.InMemoryChannel{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-imc",
+						Namespace: "test-ns",
+					},
+				},
+			},
+			want: EventMesh{
+				DiscoveredKinds: []DiscoveredKind{
+					{Group: "messaging.knative.dev", Kind: "InMemoryChannel", Resource: "inmemorychannels", Version: "v1"},
+				},
+				Brokers: []Broker{},
+				EventTypes: []EventType{
+					{
+						Name:      "test-eventtype",
+						Namespace: "test-ns",
+						Type:      "test-eventtype-type",
+						Reference: &GroupKindNamespacedName{
+							Group:     "messaging.knative.dev",
+							Kind:      "InMemoryChannel",
+							Namespace: "test-ns",
+							Name:      "test-imc",
+						},
+						ConsumedBy: []string{},
+					},
+				},
+				Triggers:      []Trigger{},
+				Subscriptions: []Subscription{},
+				Subscribables: []Subscribable{
+					{
+						Group:              "messaging.knative.dev",
+						Kind:               "InMemoryChannel",
+						Name:               "test-imc",
+						Namespace:          "test-ns",
+						ProvidedEventTypes: []string{"test-ns/test-eventtype"},
+					},
+				},
+				Sequences: []Sequence{},
+				Parallels: []Parallel{},
+				Sinks:     []Sink{},
+				Sources:   make([]Source, 0),
+			},
+		},
+		{
+			name: "Subscriptions connect the event types of the channel to the subscriber",
+			eventTypes: []*eventingv1beta2.EventType{
+				testingv1beta2.NewEventType("test-eventtype", "test-ns",
+					testingv1beta2.WithEventTypeType("test-eventtype-type"),
+					testingv1beta2.WithEventTypeReference(reference("messaging.knative.dev/v1", "InMemoryChannel", "test-ns", "test-imc")),
+				),
+			},
+			subscriptions: []*messagingv1.Subscription{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-subscription",
+						Namespace: "test-ns",
+						UID:       "test-subscription-uid",
+					},
+					Spec: messagingv1.SubscriptionSpec{
+						Channel: duckv1.KReference{APIVersion: "messaging.knative.dev/v1", Kind: "InMemoryChannel", Name: "test-imc"},
+						Subscriber: &duckv1.Destination{
+							Ref: &duckv1.KReference{APIVersion: "v1", Kind: "Service", Namespace: "test-ns", Name: "test-subscriber"},
+						},
+						Reply: &duckv1.Destination{
+							Ref: &duckv1.KReference{APIVersion: "messaging.knative.dev/v1", Kind: "InMemoryChannel", Namespace: "test-ns", Name: "test-reply"},
+						},
+						Delivery: &eventingduckv1.DeliverySpec{
+							DeadLetterSink: &duckv1.Destination{URI: &apis.URL{Scheme: "http", Host: "test-dls.example.com"}},
+						},
+					},
+					Status: messagingv1.SubscriptionStatus{
+						Status: duckv1.Status{
+							Conditions: duckv1.Conditions{
+								{Type: apis.ConditionReady, Status: corev1.ConditionFalse, Reason: "NotAddedToChannel", Message: "Not added to the channel yet"},
+							},
+						},
+						PhysicalSubscription: messagingv1.SubscriptionStatusPhysicalSubscription{
+							SubscriberURI: &apis.URL{Scheme: "http", Host: "test-subscriber.test-ns.svc.cluster.local"},
+							ReplyURI:      &apis.URL{Scheme: "http", Host: "test-reply-kn-channel.test-ns.svc.cluster.local"},
+							DeliveryStatus: eventingduckv1.DeliveryStatus{
+								DeadLetterSinkURI: &apis.URL{Scheme: "http", Host: "test-dls.example.com"},
+							},
+						},
+					},
+				},
+				{
+					// subscriptions without a subscriber are returned too
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-subscription-without-subscriber",
+						Namespace: "test-ns",
+					},
+					Spec: messagingv1.SubscriptionSpec{
+						Channel: duckv1.KReference{APIVersion: "messaging.knative.dev/v1", Kind: "InMemoryChannel", Name: "test-imc"},
+					},
+				},
+			},
+			extraObjects: []runtime.Object{
+				&apiextensionsv1.CustomResourceDefinition{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "inmemorychannels.messaging.knative.dev",
+						Labels: map[string]string{
+							"messaging.knative.dev/subscribable": "true",
+						},
+					},
+					Spec: inMemoryChannelCRDSpec,
+				},
+				&messagingv1.InMemoryChannel{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-imc",
+						Namespace: "test-ns",
+					},
+				},
+				&corev1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-subscriber",
+						Namespace: "test-ns",
+						Labels:    map[string]string{"backstage.io/kubernetes-id": "test-subscriber"},
+					},
+				},
+			},
+			want: EventMesh{
+				DiscoveredKinds: []DiscoveredKind{
+					{Group: "messaging.knative.dev", Kind: "InMemoryChannel", Resource: "inmemorychannels", Version: "v1"},
+				},
+				Brokers: []Broker{},
+				EventTypes: []EventType{
+					{
+						Name:      "test-eventtype",
+						Namespace: "test-ns",
+						Type:      "test-eventtype-type",
+						Reference: &GroupKindNamespacedName{
+							Group:     "messaging.knative.dev",
+							Kind:      "InMemoryChannel",
+							Namespace: "test-ns",
+							Name:      "test-imc",
+						},
+						ConsumedBy: []string{"test-subscriber"},
+						Consumers:  []EventTypeConsumer{{BackstageID: "test-subscriber", Hop: &GroupKindNamespacedName{Kind: "Service", Namespace: "test-ns", Name: "test-subscriber"}}},
+					},
+				},
+				Triggers: []Trigger{},
+				Subscriptions: []Subscription{
+					{
+						Name:          "test-subscription",
+						Namespace:     "test-ns",
+						UID:           "test-subscription-uid",
+						Channel:       GroupKindNamespacedName{Group: "messaging.knative.dev", Kind: "InMemoryChannel", Namespace: "test-ns", Name: "test-imc"},
+						Subscriber:    &Destination{Ref: &GroupKindNamespacedName{Group: "", Kind: "Service", Namespace: "test-ns", Name: "test-subscriber"}},
+						SubscriberURI: "http://test-subscriber.test-ns.svc.cluster.local",
+						Reply:         &Destination{Ref: &GroupKindNamespacedName{Group: "messaging.knative.dev", Kind: "InMemoryChannel", Namespace: "test-ns", Name: "test-reply"}},
+						ReplyURI:      "http://test-reply-kn-channel.test-ns.svc.cluster.local",
+						BackstageID:   "test-subscriber",
+						Delivery: &Delivery{
+							DeadLetterSink:    &Destination{URI: "http://test-dls.example.com"},
+							DeadLetterSinkURI: "http://test-dls.example.com",
+						},
+						Conditions: []Condition{
+							{Type: "Ready", Status: "False", Reason: "NotAddedToChannel", Message: "Not added to the channel yet"},
+						},
+					},
+					{
+						Name:       "test-subscription-without-subscriber",
+						Namespace:  "test-ns",
+						Channel:    GroupKindNamespacedName{Group: "messaging.knative.dev", Kind: "InMemoryChannel", Namespace: "test-ns", Name: "test-imc"},
+						Conditions: []Condition{},
+					},
+				},
+				Subscribables: []Subscribable{
+					{
+						Group:              "messaging.knative.dev",
+						Kind:               "InMemoryChannel",
+						Name:               "test-imc",
+						Namespace:          "test-ns",
+						ProvidedEventTypes: []string{"test-ns/test-eventtype"},
+					},
+				},
+				Sequences: []Sequence{},
+				Parallels: []Parallel{},
+				Sinks:     []Sink{},
+				Sources:   make([]Source, 0),
+			},
+		},
+		{
+			name: "With 1 source, 2 types",
+			eventTypes: []*eventingv1beta2.EventType{
+				testingv1beta2.NewEventType("test-eventtype", "test-ns",
+					testingv1beta2.WithEventTypeType("test-eventtype-type"),
+				),
+				testingv1beta2.NewEventType("test-eventtype-used-in-source", "test-ns",
+					testingv1beta2.WithEventTypeType("dev.knative.apiserver.resource.add"),
+				),
+			},
+			triggers: []*eventingv1.Trigger{},
+			extraObjects: []runtime.Object{
+				&apiextensionsv1.CustomResourceDefinition{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "sources.knative.dev",
+						Labels: map[string]string{
+							"duck.knative.dev/source": "true",
+						},
+						Annotations: map[string]string{
+							"registry.knative.dev/eventTypes": `
+							[
+							  {
+							    "type": "dev.knative.apiserver.resource.add",
+							    "description": "CloudEvent type used for add operations when in Resource mode"
+							  }
+							]
+							`,
+						},
+					},
+					Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+						Group: "sources.knative.dev",
+						Names: apiextensionsv1.CustomResourceDefinitionNames{
+							Kind:     "ApiServerSource",
+							ListKind: "ApiServerSourceList",
+							Plural:   "apiserversources",
+						},
+						// the first served version is not the one that's used
+						Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+							{
+								Name:   "v1alpha1",
+								Served: true,
+							},
+							{
+								Name:    "v1",
+								Served:  true,
+								Storage: true,
+							},
+						},
+					},
+				},
+				&sourcesv1.ApiServerSource{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-src",
+						Namespace: "test-ns",
+					},
+				},
+			},
+			want: EventMesh{
+				DiscoveredKinds: []DiscoveredKind{
+					{Group: "sources.knative.dev", Kind: "ApiServerSource", Resource: "apiserversources", Version: "v1"},
+				},
+				Brokers: []Broker{},
+				EventTypes: []EventType{
+					{
+						Name:       "test-eventtype",
+						Namespace:  "test-ns",
+						Type:       "test-eventtype-type",
+						ConsumedBy: []string{},
+					},
+					{
+						Name:       "test-eventtype-used-in-source",
+						Namespace:  "test-ns",
+						Type:       "dev.knative.apiserver.resource.add",
+						ConsumedBy: []string{},
+					},
+				},
+				Triggers:      []Trigger{},
+				Subscriptions: []Subscription{},
+				Subscribables: make([]Subscribable, 0),
+				Sequences:     []Sequence{},
+				Parallels:     []Parallel{},
+				Sinks:         []Sink{},
+				Sources: []Source{
+					{
+						Group:                  "sources.knative.dev",
+						Kind:                   "ApiServerSource",
+						Name:                   "test-src",
+						Namespace:              "test-ns",
+						ProvidedEventTypeTypes: []string{"dev.knative.apiserver.resource.add"},
+						ProvidedEventTypes:     []string{"test-ns/test-eventtype-used-in-source"},
+					},
+				},
+			},
+		},
+		{
+			name: "Sources of a CRD with malformed event types are reported as warnings",
+			extraObjects: []runtime.Object{
+				&apiextensionsv1.CustomResourceDefinition{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "apiserversources.sources.knative.dev",
+						Labels: map[string]string{
+							"duck.knative.dev/source": "true",
+						},
+						Annotations: map[string]string{
+							"registry.knative.dev/eventTypes": `[{"type": `,
+						},
+					},
+					Spec: apiServerSourceCRDSpec,
+				},
+				&sourcesv1.ApiServerSource{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-src",
+						Namespace: "test-ns",
+					},
+				},
+			},
+			want: EventMesh{
+				DiscoveredKinds: []DiscoveredKind{
+					{Group: "sources.knative.dev", Kind: "ApiServerSource", Resource: "apiserversources", Version: "v1"},
+				},
+				Brokers:       []Broker{},
+				EventTypes:    []EventType{},
+				Triggers:      []Trigger{},
+				Subscriptions: []Subscription{},
+				Subscribables: make([]Subscribable, 0),
+				Sequences:     []Sequence{},
+				Parallels:     []Parallel{},
+				Sinks:         []Sink{},
+				Sources:       make([]Source, 0),
+				Warnings: []Warning{
+					{
+						Group:     "sources.knative.dev",
+						Kind:      "ApiServerSource",
+						Namespace: "test-ns",
+						Name:      "test-src",
+						Reason:    "failed to unmarshal the event types of CRD apiserversources.sources.knative.dev: unexpected end of JSON input",
+					},
+				},
+			},
+		},
+		{
+			name: "Resources that can't be listed are reported as warnings",
+			brokers: []*eventingv1.Broker{
+				testingv1.NewBroker("test-broker", "test-ns"),
+			},
+			eventTypes: []*eventingv1beta2.EventType{
+				testingv1beta2.NewEventType("test-eventtype", "test-ns",
+					testingv1beta2.WithEventTypeType("test-eventtype-type"),
+					testingv1beta2.WithEventTypeReference(brokerReference("test-broker", "test-ns")),
+				),
+			},
+			extraObjects: []runtime.Object{
+				&apiextensionsv1.CustomResourceDefinition{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "apiserversources.sources.knative.dev",
+						Labels: map[string]string{
+							"duck.knative.dev/source": "true",
+						},
+					},
+					Spec: apiServerSourceCRDSpec,
+				},
+			},
+			listErrors: map[string]error{
+				"triggers":         errTriggersForbidden,
+				"apiserversources": errSourcesInternal,
+			},
+			want: EventMesh{
+				DiscoveredKinds: []DiscoveredKind{
+					{Group: "sources.knative.dev", Kind: "ApiServerSource", Resource: "apiserversources", Version: "v1"},
+				},
+				Brokers: []Broker{
+					{
+						Name:               "test-broker",
+						Namespace:          "test-ns",
+						ProvidedEventTypes: []string{"test-ns/test-eventtype"},
+					},
+				},
+				EventTypes: []EventType{
+					{
+						Name:      "test-eventtype",
+						Namespace: "test-ns",
+						Type:      "test-eventtype-type",
+						Reference: &GroupKindNamespacedName{
+							Group:     "eventing.knative.dev",
+							Kind:      "Broker",
+							Namespace: "test-ns",
+							Name:      "test-broker",
+						},
+						ConsumedBy: []string{},
+					},
+				},
+				Triggers:      []Trigger{},
+				Subscriptions: []Subscription{},
+				Subscribables: make([]Subscribable, 0),
+				Sequences:     []Sequence{},
+				Parallels:     []Parallel{},
+				Sinks:         []Sink{},
+				Sources:       make([]Source, 0),
+				Warnings: []Warning{
+					{
+						Group:       "eventing.knative.dev",
+						Kind:        "Trigger",
+						Reason:      "error listing triggers: " + errTriggersForbidden.Error(),
+						StatusClass: "4xx",
+					},
+					{
+						Group:       "sources.knative.dev",
+						Kind:        "ApiServerSource",
+						Reason:      "error listing apiserversources.sources.knative.dev: " + errSourcesInternal.Error(),
+						StatusClass: "5xx",
+					},
+				},
+			},
+		},
+		{
+			name: "Triggers are returned with their delivery and status",
+			brokers: []*eventingv1.Broker{
+				testingv1.NewBroker("test-broker", "test-ns"),
+			},
+			triggers: []*eventingv1.Trigger{
+				testingv1.NewTrigger("test-trigger", "test-ns", "test-broker",
+					WithTriggerUID("test-trigger-uid"),
+					WithTriggerSubscriberURI(&apis.URL{Scheme: "http", Host: "test-subscriber.example.com"}),
+					WithTriggerDelivery(&eventingduckv1.DeliverySpec{
+						DeadLetterSink: &duckv1.Destination{
+							Ref: &duckv1.KReference{APIVersion: "v1", Kind: "Service", Name: "test-dls"},
+						},
+						Retry:         ptr.To[int32](3),
+						BackoffPolicy: ptr.To(eventingduckv1.BackoffPolicyExponential),
+						BackoffDelay:  ptr.To("PT0.2S"),
+					}),
+					WithTriggerStatus(eventingv1.TriggerStatus{
+						Status: duckv1.Status{
+							Conditions: duckv1.Conditions{
+								{
+									Type:               apis.ConditionReady,
+									Status:             corev1.ConditionTrue,
+									LastTransitionTime: apis.VolatileTime{Inner: metav1.NewTime(testTransitionTime)},
+								},
+							},
+						},
+						SubscriberURI: &apis.URL{Scheme: "http", Host: "test-subscriber.example.com"},
+						DeliveryStatus: eventingduckv1.DeliveryStatus{
+							DeadLetterSinkURI: &apis.URL{Scheme: "http", Host: "test-dls.test-ns.svc.cluster.local"},
+						},
+					}),
+				),
+			},
+			want: EventMesh{
+				Brokers: []Broker{
+					{
+						Name:               "test-broker",
+						Namespace:          "test-ns",
+						ProvidedEventTypes: []string{},
+					},
+				},
+				EventTypes: []EventType{},
+				Triggers: []Trigger{
+					{
+						Name:          "test-trigger",
+						Namespace:     "test-ns",
+						UID:           "test-trigger-uid",
+						Broker:        GroupKindNamespacedName{Group: "eventing.knative.dev", Kind: "Broker", Namespace: "test-ns", Name: "test-broker"},
+						Subscriber:    Destination{URI: "http://test-subscriber.example.com"},
+						SubscriberURI: "http://test-subscriber.example.com",
+						Delivery: &Delivery{
+							DeadLetterSink:    &Destination{Ref: &GroupKindNamespacedName{Group: "", Kind: "Service", Namespace: "test-ns", Name: "test-dls"}},
+							DeadLetterSinkURI: "http://test-dls.test-ns.svc.cluster.local",
+							Retry:             ptr.To[int32](3),
+							BackoffPolicy:     "exponential",
+							BackoffDelay:      "PT0.2S",
+						},
+						Conditions: []Condition{
+							{Type: "Ready", Status: "True", LastTransitionTime: &testTransitionTime},
+						},
+					},
+				},
+				Subscriptions: []Subscription{},
+				Subscribables: make([]Subscribable, 0),
+				Sequences:     []Sequence{},
+				Parallels:     []Parallel{},
+				Sinks:         []Sink{},
+				Sources:       make([]Source, 0),
+			},
+		},
+		{
+			name: "Resources are returned with their status",
+			brokers: []*eventingv1.Broker{
+				testingv1.NewBroker("test-broker", "test-ns",
+					WithBrokerStatus(eventingv1.BrokerStatus{
+						Status: duckv1.Status{
+							Conditions: duckv1.Conditions{
+								{Type: eventingv1.BrokerConditionAddressable, Status: corev1.ConditionTrue},
+								{Type: apis.ConditionReady, Status: corev1.ConditionTrue, LastTransitionTime: apis.VolatileTime{Inner: metav1.NewTime(testTransitionTime)}},
+							},
+						},
+						AddressStatus: duckv1.AddressStatus{
+							Address: &duckv1.Addressable{URL: &apis.URL{Scheme: "http", Host: "broker-ingress.knative-eventing.svc.cluster.local", Path: "/test-ns/test-broker"}},
+						},
+					}),
+				),
+			},
+			eventTypes: []*eventingv1beta2.EventType{
+				testingv1beta2.NewEventType("test-eventtype", "test-ns",
+					testingv1beta2.WithEventTypeType("test-eventtype-type"),
+					WithEventTypeStatus(eventingv1beta2.EventTypeStatus{
+						Status: duckv1.Status{
+							Conditions: duckv1.Conditions{
+								{Type: "ReferenceExists", Status: corev1.ConditionUnknown},
+							},
+						},
+					}),
+				),
+			},
+			extraObjects: []runtime.Object{
+				&apiextensionsv1.CustomResourceDefinition{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "apiserversources.sources.knative.dev",
+						Labels: map[string]string{
+							"duck.knative.dev/source": "true",
+						},
+					},
+					Spec: apiServerSourceCRDSpec,
+				},
+				&sourcesv1.ApiServerSource{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-src",
+						Namespace: "test-ns",
+					},
+					Status: sourcesv1.ApiServerSourceStatus{
+						SourceStatus: duckv1.SourceStatus{
+							Status: duckv1.Status{
+								Conditions: duckv1.Conditions{
+									{Type: apis.ConditionReady, Status: corev1.ConditionFalse, Reason: "NotFound", Message: "Sink not found"},
+								},
+							},
+						},
+					},
+				},
+				&apiextensionsv1.CustomResourceDefinition{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "inmemorychannels.messaging.knative.dev",
+						Labels: map[string]string{
+							"messaging.knative.dev/subscribable": "true",
+						},
+					},
+					Spec: inMemoryChannelCRDSpec,
+				},
+				&messagingv1.InMemoryChannel{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-imc",
+						Namespace: "test-ns",
+					},
+					Status: messagingv1.InMemoryChannelStatus{
+						ChannelableStatus: eventingduckv1.ChannelableStatus{
+							AddressStatus: duckv1.AddressStatus{
+								Addresses: []duckv1.Addressable{
+									{URL: &apis.URL{Scheme: "http", Host: "test-imc-kn-channel.test-ns.svc.cluster.local"}},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: EventMesh{
+				DiscoveredKinds: []DiscoveredKind{
+					{Group: "messaging.knative.dev", Kind: "InMemoryChannel", Resource: "inmemorychannels", Version: "v1"},
+					{Group: "sources.knative.dev", Kind: "ApiServerSource", Resource: "apiserversources", Version: "v1"},
+				},
+				Brokers: []Broker{
+					{
+						Name:               "test-broker",
+						Namespace:          "test-ns",
+						ProvidedEventTypes: []string{},
+						Status: &ResourceStatus{
+							Ready:              "True",
+							LastTransitionTime: &testTransitionTime,
+							Address:            "http://broker-ingress.knative-eventing.svc.cluster.local/test-ns/test-broker",
+						},
+					},
+				},
+				EventTypes: []EventType{
+					{
+						Name:       "test-eventtype",
+						Namespace:  "test-ns",
+						Type:       "test-eventtype-type",
+						ConsumedBy: []string{},
+						// there's no ready condition
+						Status: &ResourceStatus{Ready: "Unknown"},
+					},
+				},
+				Triggers:      []Trigger{},
+				Subscriptions: []Subscription{},
+				Subscribables: []Subscribable{
+					{
+						Group:              "messaging.knative.dev",
+						Kind:               "InMemoryChannel",
+						Name:               "test-imc",
+						Namespace:          "test-ns",
+						ProvidedEventTypes: []string{},
+						Status: &ResourceStatus{
+							Ready:   "Unknown",
+							Address: "http://test-imc-kn-channel.test-ns.svc.cluster.local",
+						},
+					},
+				},
+				Sequences: []Sequence{},
+				Parallels: []Parallel{},
+				Sinks:     []Sink{},
+				Sources: []Source{
+					{
+						Group:                  "sources.knative.dev",
+						Kind:                   "ApiServerSource",
+						Name:                   "test-src",
+						Namespace:              "test-ns",
+						ProvidedEventTypeTypes: []string{},
+						ProvidedEventTypes:     []string{},
+						Status: &ResourceStatus{
+							Ready:   "False",
+							Reason:  "NotFound",
+							Message: "Sink not found",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Events are followed through brokers and channels to their consumers",
+			brokers: []*eventingv1.Broker{
+				testingv1.NewBroker("test-broker", "test-ns"),
+				testingv1.NewBroker("test-broker-2", "test-ns"),
+			},
+			eventTypes: []*eventingv1beta2.EventType{
+				testingv1beta2.NewEventType("test-eventtype", "test-ns",
+					testingv1beta2.WithEventTypeType("test-eventtype-type"),
+					testingv1beta2.WithEventTypeReference(brokerReference("test-broker", "test-ns")),
+				),
+			},
+			triggers: []*eventingv1.Trigger{
+				testingv1.NewTrigger("test-trigger-1", "test-ns", "test-broker",
+					WithTriggerSubscriber(brokerReference("test-broker-2", "test-ns")),
+				),
+				testingv1.NewTrigger("test-trigger-2", "test-ns", "test-broker-2",
+					WithTriggerSubscriber(reference("messaging.knative.dev/v1", "InMemoryChannel", "test-ns", "test-imc")),
+					WithEventTypeFilter("test-eventtype-type"),
+				),
+				// the events of the event type don't pass the filter
+				testingv1.NewTrigger("test-trigger-3", "test-ns", "test-broker-2",
+					WithTriggerSubscriber(reference("v1", "Service", "test-ns", "test-other")),
+					WithEventTypeFilter("test-other-type"),
+				),
+				// the events are sent back to the first broker, which is a cycle
+				testingv1.NewTrigger("test-trigger-4", "test-ns", "test-broker-2",
+					WithTriggerSubscriber(brokerReference("test-broker", "test-ns")),
+				),
+			},
+			subscriptions: []*messagingv1.Subscription{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-subscription",
+						Namespace: "test-ns",
+					},
+					Spec: messagingv1.SubscriptionSpec{
+						Channel: duckv1.KReference{APIVersion: "messaging.knative.dev/v1", Kind: "InMemoryChannel", Name: "test-imc"},
+						Subscriber: &duckv1.Destination{
+							Ref: reference("v1", "Service", "test-ns", "test-subscriber"),
+						},
+					},
+				},
+			},
+			extraObjects: []runtime.Object{
+				&apiextensionsv1.CustomResourceDefinition{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "inmemorychannels.messaging.knative.dev",
+						Labels: map[string]string{
+							"messaging.knative.dev/subscribable": "true",
+						},
+					},
+					Spec: inMemoryChannelCRDSpec,
+				},
+				&messagingv1.InMemoryChannel{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-imc",
+						Namespace: "test-ns",
+					},
+				},
+				backstageService("test-subscriber"),
+				backstageService("test-other"),
+			},
+			want: EventMesh{
+				DiscoveredKinds: []DiscoveredKind{
+					{Group: "messaging.knative.dev", Kind: "InMemoryChannel", Resource: "inmemorychannels", Version: "v1"},
+				},
+				Brokers: []Broker{
+					{
+						Name:               "test-broker",
+						Namespace:          "test-ns",
+						ProvidedEventTypes: []string{"test-ns/test-eventtype"},
+					},
+					{
+						Name:               "test-broker-2",
+						Namespace:          "test-ns",
+						ProvidedEventTypes: []string{},
+					},
+				},
+				EventTypes: []EventType{
+					{
+						Name:      "test-eventtype",
+						Namespace: "test-ns",
+						Type:      "test-eventtype-type",
+						Reference: &GroupKindNamespacedName{
+							Group:     "eventing.knative.dev",
+							Kind:      "Broker",
+							Namespace: "test-ns",
+							Name:      "test-broker",
+						},
+						ConsumedBy: []string{"test-subscriber"},
+						Consumers:  []EventTypeConsumer{{BackstageID: "test-subscriber", Hop: &GroupKindNamespacedName{Group: "eventing.knative.dev", Kind: "Broker", Namespace: "test-ns", Name: "test-broker-2"}}},
+					},
+				},
+				Triggers: []Trigger{
+					{
+						Name:       "test-trigger-1",
+						Namespace:  "test-ns",
+						Broker:     GroupKindNamespacedName{Group: "eventing.knative.dev", Kind: "Broker", Namespace: "test-ns", Name: "test-broker"},
+						Subscriber: Destination{Ref: &GroupKindNamespacedName{Group: "eventing.knative.dev", Kind: "Broker", Namespace: "test-ns", Name: "test-broker-2"}},
+						Conditions: []Condition{},
+					},
+					{
+						Name:       "test-trigger-2",
+						Namespace:  "test-ns",
+						Broker:     GroupKindNamespacedName{Group: "eventing.knative.dev", Kind: "Broker", Namespace: "test-ns", Name: "test-broker-2"},
+						Filter:     map[string]string{"type": "test-eventtype-type"},
+						Subscriber: Destination{Ref: &GroupKindNamespacedName{Group: "messaging.knative.dev", Kind: "InMemoryChannel", Namespace: "test-ns", Name: "test-imc"}},
+						Conditions: []Condition{},
+					},
+					{
+						Name:        "test-trigger-3",
+						Namespace:   "test-ns",
+						Broker:      GroupKindNamespacedName{Group: "eventing.knative.dev", Kind: "Broker", Namespace: "test-ns", Name: "test-broker-2"},
+						Filter:      map[string]string{"type": "test-other-type"},
+						Subscriber:  Destination{Ref: &GroupKindNamespacedName{Group: "", Kind: "Service", Namespace: "test-ns", Name: "test-other"}},
+						BackstageID: "test-other",
+						Conditions:  []Condition{},
+					},
+					{
+						Name:       "test-trigger-4",
+						Namespace:  "test-ns",
+						Broker:     GroupKindNamespacedName{Group: "eventing.knative.dev", Kind: "Broker", Namespace: "test-ns", Name: "test-broker-2"},
+						Subscriber: Destination{Ref: &GroupKindNamespacedName{Group: "eventing.knative.dev", Kind: "Broker", Namespace: "test-ns", Name: "test-broker"}},
+						Conditions: []Condition{},
+					},
+				},
+				Subscriptions: []Subscription{
+					{
+						Name:        "test-subscription",
+						Namespace:   "test-ns",
+						Channel:     GroupKindNamespacedName{Group: "messaging.knative.dev", Kind: "InMemoryChannel", Namespace: "test-ns", Name: "test-imc"},
+						Subscriber:  &Destination{Ref: &GroupKindNamespacedName{Group: "", Kind: "Service", Namespace: "test-ns", Name: "test-subscriber"}},
+						BackstageID: "test-subscriber",
+						Conditions:  []Condition{},
+					},
+				},
+				Subscribables: []Subscribable{
+					{
+						Group:              "messaging.knative.dev",
+						Kind:               "InMemoryChannel",
+						Name:               "test-imc",
+						Namespace:          "test-ns",
+						ProvidedEventTypes: []string{},
+					},
+				},
+				Sequences: []Sequence{},
+				Parallels: []Parallel{},
+				Sinks:     []Sink{},
+				Sources:   make([]Source, 0),
+			},
+		},
+		{
+			name: "Events are followed through sequences and parallels to their consumers",
+			brokers: []*eventingv1.Broker{
+				testingv1.NewBroker("test-broker", "test-ns"),
+			},
+			eventTypes: []*eventingv1beta2.EventType{
+				testingv1beta2.NewEventType("test-eventtype", "test-ns",
+					testingv1beta2.WithEventTypeType("test-eventtype-type"),
+					testingv1beta2.WithEventTypeReference(brokerReference("test-broker", "test-ns")),
+				),
+			},
+			triggers: []*eventingv1.Trigger{
+				testingv1.NewTrigger("test-trigger-1", "test-ns", "test-broker",
+					WithTriggerSubscriber(reference("flows.knative.dev/v1", "Sequence", "test-ns", "test-sequence")),
+				),
+				testingv1.NewTrigger("test-trigger-2", "test-ns", "test-broker",
+					WithTriggerSubscriber(reference("flows.knative.dev/v1", "Parallel", "test-ns", "test-parallel")),
+				),
+			},
+			sequences: []*flowsv1.Sequence{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-sequence",
+						Namespace: "test-ns",
+					},
+					Spec: flowsv1.SequenceSpec{
+						// the second step receives the replies of the first one, which are assumed to be of the
+						// same event type, so both steps consume it
+						Steps: []flowsv1.SequenceStep{
+							{Destination: duckv1.Destination{Ref: reference("v1", "Service", "test-ns", "test-step-1")}},
+							{Destination: duckv1.Destination{Ref: reference("v1", "Service", "test-ns", "test-step-2")}},
+						},
+					},
+					Status: flowsv1.SequenceStatus{
+						ChannelStatuses: []flowsv1.SequenceChannelStatus{
+							{Channel: corev1.ObjectReference{APIVersion: "messaging.knative.dev/v1", Kind: "InMemoryChannel", Namespace: "test-ns", Name: "test-sequence-kn-sequence-0"}},
+						},
+						SubscriptionStatuses: []flowsv1.SequenceSubscriptionStatus{
+							{Subscription: corev1.ObjectReference{APIVersion: "messaging.knative.dev/v1", Kind: "Subscription", Namespace: "test-ns", Name: "test-sequence-kn-sequence-0"}},
+						},
+					},
+				},
+			},
+			parallels: []*flowsv1.Parallel{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-parallel",
+						Namespace: "test-ns",
+					},
+					Spec: flowsv1.ParallelSpec{
+						Branches: []flowsv1.ParallelBranch{
+							{
+								Filter:     &duckv1.Destination{Ref: reference("v1", "Service", "test-ns", "test-filter")},
+								Subscriber: duckv1.Destination{Ref: reference("v1", "Service", "test-ns", "test-branch-1")},
+							},
+							{
+								Subscriber: duckv1.Destination{Ref: reference("v1", "Service", "test-ns", "test-branch-2")},
+							},
+						},
+					},
+				},
+			},
+			extraObjects: []runtime.Object{
+				backstageService("test-step-1"),
+				backstageService("test-step-2"),
+				backstageService("test-filter"),
+				backstageService("test-branch-1"),
+				backstageService("test-branch-2"),
+			},
+			want: EventMesh{
+				Brokers: []Broker{
+					{
+						Name:               "test-broker",
+						Namespace:          "test-ns",
+						ProvidedEventTypes: []string{"test-ns/test-eventtype"},
+					},
+				},
+				EventTypes: []EventType{
+					{
+						Name:      "test-eventtype",
+						Namespace: "test-ns",
+						Type:      "test-eventtype-type",
+						Reference: &GroupKindNamespacedName{
+							Group:     "eventing.knative.dev",
+							Kind:      "Broker",
+							Namespace: "test-ns",
+							Name:      "test-broker",
+						},
+						ConsumedBy:              []string{"test-branch-1", "test-branch-2", "test-filter", "test-step-1", "test-step-2"},
+						IndeterminateConsumedBy: []string{"test-branch-1"},
+						Consumers: []EventTypeConsumer{
+							{BackstageID: "test-branch-1", Hop: &GroupKindNamespacedName{Group: "flows.knative.dev", Kind: "Parallel", Namespace: "test-ns", Name: "test-parallel"}, Indeterminate: true},
+							{BackstageID: "test-branch-2", Hop: &GroupKindNamespacedName{Group: "flows.knative.dev", Kind: "Parallel", Namespace: "test-ns", Name: "test-parallel"}},
+							{BackstageID: "test-filter", Hop: &GroupKindNamespacedName{Group: "flows.knative.dev", Kind: "Parallel", Namespace: "test-ns", Name: "test-parallel"}},
+							{BackstageID: "test-step-1", Hop: &GroupKindNamespacedName{Group: "flows.knative.dev", Kind: "Sequence", Namespace: "test-ns", Name: "test-sequence"}},
+							{BackstageID: "test-step-2", Hop: &GroupKindNamespacedName{Group: "flows.knative.dev", Kind: "Sequence", Namespace: "test-ns", Name: "test-sequence"}},
+						},
+					},
+				},
+				Triggers: []Trigger{
+					{
+						Name:       "test-trigger-1",
+						Namespace:  "test-ns",
+						Broker:     GroupKindNamespacedName{Group: "eventing.knative.dev", Kind: "Broker", Namespace: "test-ns", Name: "test-broker"},
+						Subscriber: Destination{Ref: &GroupKindNamespacedName{Group: "flows.knative.dev", Kind: "Sequence", Namespace: "test-ns", Name: "test-sequence"}},
+						Conditions: []Condition{},
+					},
+					{
+						Name:       "test-trigger-2",
+						Namespace:  "test-ns",
+						Broker:     GroupKindNamespacedName{Group: "eventing.knative.dev", Kind: "Broker", Namespace: "test-ns", Name: "test-broker"},
+						Subscriber: Destination{Ref: &GroupKindNamespacedName{Group: "flows.knative.dev", Kind: "Parallel", Namespace: "test-ns", Name: "test-parallel"}},
+						Conditions: []Condition{},
+					},
+				},
+				Subscriptions: []Subscription{},
+				Subscribables: make([]Subscribable, 0),
+				Sequences: []Sequence{
+					{
+						Name:               "test-sequence",
+						Namespace:          "test-ns",
+						ProvidedEventTypes: []string{"test-ns/test-eventtype"},
+						Steps: []SequenceStep{
+							{
+								Subscriber:         Destination{Ref: &GroupKindNamespacedName{Kind: "Service", Namespace: "test-ns", Name: "test-step-1"}},
+								BackstageID:        "test-step-1",
+								Channel:            &GroupKindNamespacedName{Group: "messaging.knative.dev", Kind: "InMemoryChannel", Namespace: "test-ns", Name: "test-sequence-kn-sequence-0"},
+								Subscription:       &GroupKindNamespacedName{Group: "messaging.knative.dev", Kind: "Subscription", Namespace: "test-ns", Name: "test-sequence-kn-sequence-0"},
+								ProvidedEventTypes: []string{"test-ns/test-eventtype"},
+							},
+							{
+								Subscriber:         Destination{Ref: &GroupKindNamespacedName{Kind: "Service", Namespace: "test-ns", Name: "test-step-2"}},
+								BackstageID:        "test-step-2",
+								ProvidedEventTypes: []string{"test-ns/test-eventtype"},
+							},
+						},
+					},
+				},
+				Parallels: []Parallel{
+					{
+						Name:               "test-parallel",
+						Namespace:          "test-ns",
+						ProvidedEventTypes: []string{"test-ns/test-eventtype"},
+						Branches: []ParallelBranch{
+							{
+								Filter:      &Destination{Ref: &GroupKindNamespacedName{Kind: "Service", Namespace: "test-ns", Name: "test-filter"}},
+								Subscriber:  Destination{Ref: &GroupKindNamespacedName{Kind: "Service", Namespace: "test-ns", Name: "test-branch-1"}},
+								BackstageID: "test-branch-1",
+							},
+							{
+								Subscriber:  Destination{Ref: &GroupKindNamespacedName{Kind: "Service", Namespace: "test-ns", Name: "test-branch-2"}},
+								BackstageID: "test-branch-2",
+							},
+						},
+					},
+				},
+				Sinks:   []Sink{},
+				Sources: make([]Source, 0),
+			},
+		},
+		{
+			name: "Events are followed to the replies of sequences and parallels",
+			brokers: []*eventingv1.Broker{
+				testingv1.NewBroker("test-broker", "test-ns"),
+			},
+			eventTypes: []*eventingv1beta2.EventType{
+				testingv1beta2.NewEventType("test-eventtype", "test-ns",
+					testingv1beta2.WithEventTypeType("test-eventtype-type"),
+					testingv1beta2.WithEventTypeReference(brokerReference("test-broker", "test-ns")),
+				),
+			},
+			triggers: []*eventingv1.Trigger{
+				testingv1.NewTrigger("test-trigger-1", "test-ns", "test-broker",
+					WithTriggerSubscriber(reference("flows.knative.dev/v1", "Sequence", "test-ns", "test-sequence")),
+				),
+				testingv1.NewTrigger("test-trigger-2", "test-ns", "test-broker",
+					WithTriggerSubscriber(reference("flows.knative.dev/v1", "Parallel", "test-ns", "test-parallel")),
+				),
+				testingv1.NewTrigger("test-trigger-3", "test-ns", "test-broker",
+					WithTriggerSubscriber(reference("flows.knative.dev/v1", "Sequence", "test-ns", "test-cycle")),
+				),
+			},
+			sequences: []*flowsv1.Sequence{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-sequence",
+						Namespace: "test-ns",
+					},
+					Spec: flowsv1.SequenceSpec{
+						Steps: []flowsv1.SequenceStep{
+							{Destination: duckv1.Destination{Ref: reference("v1", "Service", "test-ns", "test-step")}},
+						},
+						Reply: &duckv1.Destination{Ref: reference("v1", "Service", "test-ns", "test-sequence-reply")},
+					},
+				},
+				{
+					// the replies go back to the broker that the events come from, they're not followed again
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-cycle",
+						Namespace: "test-ns",
+					},
+					Spec: flowsv1.SequenceSpec{
+						Reply: &duckv1.Destination{Ref: brokerReference("test-broker", "test-ns")},
+					},
+				},
+			},
+			parallels: []*flowsv1.Parallel{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-parallel",
+						Namespace: "test-ns",
+					},
+					Spec: flowsv1.ParallelSpec{
+						Branches: []flowsv1.ParallelBranch{
+							{
+								Filter:     &duckv1.Destination{Ref: reference("v1", "Service", "test-ns", "test-filter")},
+								Subscriber: duckv1.Destination{Ref: reference("v1", "Service", "test-ns", "test-branch-1")},
+								Reply:      &duckv1.Destination{Ref: reference("v1", "Service", "test-ns", "test-branch-reply")},
+							},
+							{
+								Subscriber: duckv1.Destination{Ref: reference("v1", "Service", "test-ns", "test-branch-2")},
+							},
+						},
+						Reply: &duckv1.Destination{Ref: reference("v1", "Service", "test-ns", "test-parallel-reply")},
+					},
+				},
+			},
+			extraObjects: []runtime.Object{
+				backstageService("test-step"),
+				backstageService("test-sequence-reply"),
+				backstageService("test-filter"),
+				backstageService("test-branch-1"),
+				backstageService("test-branch-reply"),
+				backstageService("test-branch-2"),
+				backstageService("test-parallel-reply"),
+			},
+			want: EventMesh{
+				Brokers: []Broker{
+					{
+						Name:               "test-broker",
+						Namespace:          "test-ns",
+						ProvidedEventTypes: []string{"test-ns/test-eventtype"},
+					},
+				},
+				EventTypes: []EventType{
+					{
+						Name:      "test-eventtype",
+						Namespace: "test-ns",
+						Type:      "test-eventtype-type",
+						Reference: &GroupKindNamespacedName{
+							Group:     "eventing.knative.dev",
+							Kind:      "Broker",
+							Namespace: "test-ns",
+							Name:      "test-broker",
+						},
+						ConsumedBy:              []string{"test-branch-1", "test-branch-2", "test-branch-reply", "test-filter", "test-parallel-reply", "test-sequence-reply", "test-step"},
+						IndeterminateConsumedBy: []string{"test-branch-1", "test-branch-reply"},
+						Consumers: []EventTypeConsumer{
+							{BackstageID: "test-branch-1", Hop: &GroupKindNamespacedName{Group: "flows.knative.dev", Kind: "Parallel", Namespace: "test-ns", Name: "test-parallel"}, Indeterminate: true},
+							{BackstageID: "test-branch-2", Hop: &GroupKindNamespacedName{Group: "flows.knative.dev", Kind: "Parallel", Namespace: "test-ns", Name: "test-parallel"}},
+							{BackstageID: "test-branch-reply", Hop: &GroupKindNamespacedName{Group: "flows.knative.dev", Kind: "Parallel", Namespace: "test-ns", Name: "test-parallel"}, Indeterminate: true},
+							{BackstageID: "test-filter", Hop: &GroupKindNamespacedName{Group: "flows.knative.dev", Kind: "Parallel", Namespace: "test-ns", Name: "test-parallel"}},
+							{BackstageID: "test-parallel-reply", Hop: &GroupKindNamespacedName{Group: "flows.knative.dev", Kind: "Parallel", Namespace: "test-ns", Name: "test-parallel"}},
+							{BackstageID: "test-sequence-reply", Hop: &GroupKindNamespacedName{Group: "flows.knative.dev", Kind: "Sequence", Namespace: "test-ns", Name: "test-sequence"}},
+							{BackstageID: "test-step", Hop: &GroupKindNamespacedName{Group: "flows.knative.dev", Kind: "Sequence", Namespace: "test-ns", Name: "test-sequence"}},
+						},
+					},
+				},
+				Triggers: []Trigger{
+					{
+						Name:       "test-trigger-1",
+						Namespace:  "test-ns",
+						Broker:     GroupKindNamespacedName{Group: "eventing.knative.dev", Kind: "Broker", Namespace: "test-ns", Name: "test-broker"},
+						Subscriber: Destination{Ref: &GroupKindNamespacedName{Group: "flows.knative.dev", Kind: "Sequence", Namespace: "test-ns", Name: "test-sequence"}},
+						Conditions: []Condition{},
+					},
+					{
+						Name:       "test-trigger-2",
+						Namespace:  "test-ns",
+						Broker:     GroupKindNamespacedName{Group: "eventing.knative.dev", Kind: "Broker", Namespace: "test-ns", Name: "test-broker"},
+						Subscriber: Destination{Ref: &GroupKindNamespacedName{Group: "flows.knative.dev", Kind: "Parallel", Namespace: "test-ns", Name: "test-parallel"}},
+						Conditions: []Condition{},
+					},
+					{
+						Name:       "test-trigger-3",
+						Namespace:  "test-ns",
+						Broker:     GroupKindNamespacedName{Group: "eventing.knative.dev", Kind: "Broker", Namespace: "test-ns", Name: "test-broker"},
+						Subscriber: Destination{Ref: &GroupKindNamespacedName{Group: "flows.knative.dev", Kind: "Sequence", Namespace: "test-ns", Name: "test-cycle"}},
+						Conditions: []Condition{},
+					},
+				},
+				Subscriptions: []Subscription{},
+				Subscribables: make([]Subscribable, 0),
+				Sequences: []Sequence{
+					{
+						Name:               "test-cycle",
+						Namespace:          "test-ns",
+						ProvidedEventTypes: []string{"test-ns/test-eventtype"},
+						Steps:              []SequenceStep{},
+						Reply:              &Destination{Ref: &GroupKindNamespacedName{Group: "eventing.knative.dev", Kind: "Broker", Namespace: "test-ns", Name: "test-broker"}},
+					},
+					{
+						Name:               "test-sequence",
+						Namespace:          "test-ns",
+						ProvidedEventTypes: []string{"test-ns/test-eventtype"},
+						Steps: []SequenceStep{
+							{
+								Subscriber:         Destination{Ref: &GroupKindNamespacedName{Kind: "Service", Namespace: "test-ns", Name: "test-step"}},
+								BackstageID:        "test-step",
+								ProvidedEventTypes: []string{"test-ns/test-eventtype"},
+							},
+						},
+						Reply: &Destination{Ref: &GroupKindNamespacedName{Kind: "Service", Namespace: "test-ns", Name: "test-sequence-reply"}},
+					},
+				},
+				Parallels: []Parallel{
+					{
+						Name:               "test-parallel",
+						Namespace:          "test-ns",
+						ProvidedEventTypes: []string{"test-ns/test-eventtype"},
+						Branches: []ParallelBranch{
+							{
+								Filter:      &Destination{Ref: &GroupKindNamespacedName{Kind: "Service", Namespace: "test-ns", Name: "test-filter"}},
+								Subscriber:  Destination{Ref: &GroupKindNamespacedName{Kind: "Service", Namespace: "test-ns", Name: "test-branch-1"}},
+								Reply:       &Destination{Ref: &GroupKindNamespacedName{Kind: "Service", Namespace: "test-ns", Name: "test-branch-reply"}},
+								BackstageID: "test-branch-1",
+							},
+							{
+								Subscriber:  Destination{Ref: &GroupKindNamespacedName{Kind: "Service", Namespace: "test-ns", Name: "test-branch-2"}},
+								BackstageID: "test-branch-2",
+							},
+						},
+						Reply: &Destination{Ref: &GroupKindNamespacedName{Kind: "Service", Namespace: "test-ns", Name: "test-parallel-reply"}},
+					},
+				},
+				Sinks:   []Sink{},
+				Sources: make([]Source, 0),
+			},
+		},
+		{
+			name: "Sinks consume the event types that are sent to them",
+			brokers: []*eventingv1.Broker{
+				testingv1.NewBroker("test-broker", "test-ns"),
+			},
+			eventTypes: []*eventingv1beta2.EventType{
+				testingv1beta2.NewEventType("test-eventtype", "test-ns",
+					testingv1beta2.WithEventTypeType("test-eventtype-type"),
+					testingv1beta2.WithEventTypeReference(brokerReference("test-broker", "test-ns")),
+				),
+			},
+			triggers: []*eventingv1.Trigger{
+				testingv1.NewTrigger("test-trigger-1", "test-ns", "test-broker",
+					WithTriggerSubscriber(reference("sinks.knative.dev/v1alpha1", "JobSink", "test-ns", "test-jobsink")),
+				),
+				testingv1.NewTrigger("test-trigger-2", "test-ns", "test-broker",
+					WithTriggerSubscriber(reference("eventing.knative.dev/v1alpha1", "KafkaSink", "test-ns", "test-kafkasink")),
+				),
+			},
+			extraObjects: []runtime.Object{
+				sinkCRD("sinks.knative.dev", "JobSink", "jobsinks"),
+				sinkCRD("eventing.knative.dev", "KafkaSink", "kafkasinks"),
+				// addressables that aren't sinks are ignored
+				sinkCRD("example.com", "Addressable", "addressables"),
+				newSink("sinks.knative.dev/v1alpha1", "JobSink", "test-jobsink",
+					map[string]string{BackstageKubernetesIDLabel: "test-jobsink"},
+					"http://job-sink.knative-eventing.svc.cluster.local/test-ns/test-jobsink",
+				),
+				newSink("eventing.knative.dev/v1alpha1", "KafkaSink", "test-kafkasink", nil, ""),
+			},
+			want: EventMesh{
+				DiscoveredKinds: []DiscoveredKind{
+					{Group: "eventing.knative.dev", Kind: "KafkaSink", Resource: "kafkasinks", Version: "v1alpha1"},
+					{Group: "sinks.knative.dev", Kind: "JobSink", Resource: "jobsinks", Version: "v1alpha1"},
+				},
+				Brokers: []Broker{
+					{
+						Name:               "test-broker",
+						Namespace:          "test-ns",
+						ProvidedEventTypes: []string{"test-ns/test-eventtype"},
+					},
+				},
+				EventTypes: []EventType{
+					{
+						Name:      "test-eventtype",
+						Namespace: "test-ns",
+						Type:      "test-eventtype-type",
+						Reference: &GroupKindNamespacedName{
+							Group:     "eventing.knative.dev",
+							Kind:      "Broker",
+							Namespace: "test-ns",
+							Name:      "test-broker",
+						},
+						ConsumedBy: []string{"test-jobsink"},
+						Consumers: []EventTypeConsumer{
+							{BackstageID: "test-jobsink", Hop: &GroupKindNamespacedName{Group: "sinks.knative.dev", Kind: "JobSink", Namespace: "test-ns", Name: "test-jobsink"}},
+						},
+					},
+				},
+				Triggers: []Trigger{
+					{
+						Name:        "test-trigger-1",
+						Namespace:   "test-ns",
+						Broker:      GroupKindNamespacedName{Group: "eventing.knative.dev", Kind: "Broker", Namespace: "test-ns", Name: "test-broker"},
+						Subscriber:  Destination{Ref: &GroupKindNamespacedName{Group: "sinks.knative.dev", Kind: "JobSink", Namespace: "test-ns", Name: "test-jobsink"}},
+						BackstageID: "test-jobsink",
+						Conditions:  []Condition{},
+					},
+					{
+						Name:       "test-trigger-2",
+						Namespace:  "test-ns",
+						Broker:     GroupKindNamespacedName{Group: "eventing.knative.dev", Kind: "Broker", Namespace: "test-ns", Name: "test-broker"},
+						Subscriber: Destination{Ref: &GroupKindNamespacedName{Group: "eventing.knative.dev", Kind: "KafkaSink", Namespace: "test-ns", Name: "test-kafkasink"}},
+						Conditions: []Condition{},
+					},
+				},
+				Subscriptions: []Subscription{},
+				Subscribables: make([]Subscribable, 0),
+				Sequences:     []Sequence{},
+				Parallels:     []Parallel{},
+				Sinks: []Sink{
+					{
+						Name:               "test-kafkasink",
+						Namespace:          "test-ns",
+						Group:              "eventing.knative.dev",
+						Kind:               "KafkaSink",
+						ConsumedEventTypes: []string{"test-ns/test-eventtype"},
+					},
+					{
+						Name:               "test-jobsink",
+						Namespace:          "test-ns",
+						Labels:             map[string]string{BackstageKubernetesIDLabel: "test-jobsink"},
+						Group:              "sinks.knative.dev",
+						Kind:               "JobSink",
+						Address:            "http://job-sink.knative-eventing.svc.cluster.local/test-ns/test-jobsink",
+						BackstageID:        "test-jobsink",
+						ConsumedEventTypes: []string{"test-ns/test-eventtype"},
+						Status: &ResourceStatus{
+							Ready:   "Unknown",
+							Address: "http://job-sink.knative-eventing.svc.cluster.local/test-ns/test-jobsink",
+						},
+					},
+				},
+				Sources: make([]Source, 0),
+			},
+		},
+		{
+			name: "Source sinks are resolved to the resources they point to",
+			brokers: []*eventingv1.Broker{
+				testingv1.NewBroker("test-broker", "test-ns",
+					WithBrokerStatus(eventingv1.BrokerStatus{
+						AddressStatus: duckv1.AddressStatus{
+							Address: &duckv1.Addressable{URL: &apis.URL{Scheme: "http", Host: "broker-ingress.knative-eventing.svc.cluster.local", Path: "/test-ns/test-broker"}},
+						},
+					}),
 				),
 			},
-			triggers: []*eventingv1.Trigger{},
 			extraObjects: []runtime.Object{
 				&apiextensionsv1.CustomResourceDefinition{
 					ObjectMeta: metav1.ObjectMeta{
-						Name: "sources.knative.dev",
-						Labels: map[string]string{
-							"duck.knative.dev/source": "true",
-						},
-						Annotations: map[string]string{
-							"registry.knative.dev/eventTypes": `
-							[
-							  {
-							    "type": "dev.knative.apiserver.resource.add",
-							    "description": "CloudEvent type used for add operations when in Resource mode"
-							  }
-							]
-							`,
-						},
+						Name:   "apiserversources.sources.knative.dev",
+						Labels: map[string]string{"duck.knative.dev/source": "true"},
 					},
 					Spec: apiextensionsv1.CustomResourceDefinitionSpec{
 						Group: "sources.knative.dev",
@@ -760,50 +2426,215 @@ func TestBuildEventMesh(t *testing.T) {
 							Plural:   "apiserversources",
 						},
 						Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
-							{
-								Name:    "v1",
-								Served:  true,
-								Storage: true,
-							},
+							{Name: "v1", Served: true, Storage: true},
+						},
+					},
+				},
+				// sends the events to the URL of the broker
+				&sourcesv1.ApiServerSource{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-src-broker-uri", Namespace: "test-ns"},
+					Spec: sourcesv1.ApiServerSourceSpec{
+						SourceSpec: duckv1.SourceSpec{
+							Sink: duckv1.Destination{URI: &apis.URL{Scheme: "http", Host: "broker-ingress.knative-eventing.svc.cluster.local", Path: "/test-ns/test-broker"}},
+						},
+					},
+				},
+				// sends the events to a service in another namespace
+				&sourcesv1.ApiServerSource{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-src-other-ns", Namespace: "test-ns"},
+					Spec: sourcesv1.ApiServerSourceSpec{
+						SourceSpec: duckv1.SourceSpec{
+							Sink: duckv1.Destination{Ref: reference("v1", "Service", "other-ns", "test-subscriber")},
+						},
+					},
+					Status: sourcesv1.ApiServerSourceStatus{
+						SourceStatus: duckv1.SourceStatus{
+							SinkURI: &apis.URL{Scheme: "http", Host: "test-subscriber.other-ns.svc.cluster.local"},
+						},
+					},
+				},
+				// sends the events to the cluster-local URL of a service
+				&sourcesv1.ApiServerSource{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-src-service-uri", Namespace: "test-ns"},
+					Spec: sourcesv1.ApiServerSourceSpec{
+						SourceSpec: duckv1.SourceSpec{
+							Sink: duckv1.Destination{URI: &apis.URL{Scheme: "http", Host: "test-service.test-ns.svc.cluster.local"}},
+						},
+					},
+				},
+				// sends the events to the URL of a broker that isn't known, not to the broker ingress service
+				&sourcesv1.ApiServerSource{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-src-unknown-broker-uri", Namespace: "test-ns"},
+					Spec: sourcesv1.ApiServerSourceSpec{
+						SourceSpec: duckv1.SourceSpec{
+							Sink: duckv1.Destination{URI: &apis.URL{Scheme: "http", Host: "broker-ingress.knative-eventing.svc.cluster.local", Path: "/other-ns/test-broker"}},
 						},
 					},
 				},
+				// sends the events outside the cluster
 				&sourcesv1.ApiServerSource{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-src-unknown-uri", Namespace: "test-ns"},
+					Spec: sourcesv1.ApiServerSourceSpec{
+						SourceSpec: duckv1.SourceSpec{
+							Sink: duckv1.Destination{URI: &apis.URL{Scheme: "https", Host: "example.com", Path: "/events"}},
+						},
+					},
+				},
+				&corev1.Service{
 					ObjectMeta: metav1.ObjectMeta{
-						Name:      "test-src",
-						Namespace: "test-ns",
+						Name:      "test-subscriber",
+						Namespace: "other-ns",
+						Labels:    map[string]string{BackstageKubernetesIDLabel: "test-subscriber"},
 					},
 				},
+				backstageService("test-service"),
 			},
 			want: EventMesh{
-				Brokers: []Broker{},
-				EventTypes: []EventType{
-					{
-						Name:       "test-eventtype",
-						Namespace:  "test-ns",
-						Type:       "test-eventtype-type",
-						ConsumedBy: []string{},
-					},
+				DiscoveredKinds: []DiscoveredKind{
+					{Group: "sources.knative.dev", Kind: "ApiServerSource", Resource: "apiserversources", Version: "v1"},
+				},
+				Brokers: []Broker{
 					{
-						Name:       "test-eventtype-used-in-source",
-						Namespace:  "test-ns",
-						Type:       "dev.knative.apiserver.resource.add",
-						ConsumedBy: []string{},
+						Name:               "test-broker",
+						Namespace:          "test-ns",
+						ProvidedEventTypes: []string{},
+						Status: &ResourceStatus{
+							Ready:   "Unknown",
+							Address: "http://broker-ingress.knative-eventing.svc.cluster.local/test-ns/test-broker",
+						},
 					},
 				},
+				EventTypes:    []EventType{},
+				Triggers:      []Trigger{},
+				Subscriptions: []Subscription{},
 				Subscribables: make([]Subscribable, 0),
+				Sequences:     []Sequence{},
+				Parallels:     []Parallel{},
+				Sinks:         []Sink{},
 				Sources: []Source{
 					{
 						Group:                  "sources.knative.dev",
 						Kind:                   "ApiServerSource",
-						Name:                   "test-src",
+						Name:                   "test-src-broker-uri",
 						Namespace:              "test-ns",
-						ProvidedEventTypeTypes: []string{"dev.knative.apiserver.resource.add"},
-						ProvidedEventTypes:     []string{"test-ns/test-eventtype-used-in-source"},
+						ProvidedEventTypeTypes: []string{},
+						ProvidedEventTypes:     []string{},
+						Sink:                   &GroupKindNamespacedName{Group: "eventing.knative.dev", Kind: "Broker", Namespace: "test-ns", Name: "test-broker"},
+						SinkURI:                "http://broker-ingress.knative-eventing.svc.cluster.local/test-ns/test-broker",
+					},
+					{
+						Group:                  "sources.knative.dev",
+						Kind:                   "ApiServerSource",
+						Name:                   "test-src-other-ns",
+						Namespace:              "test-ns",
+						ProvidedEventTypeTypes: []string{},
+						ProvidedEventTypes:     []string{},
+						Sink:                   &GroupKindNamespacedName{Kind: "Service", Namespace: "other-ns", Name: "test-subscriber"},
+						SinkURI:                "http://test-subscriber.other-ns.svc.cluster.local",
+						SinkBackstageID:        "test-subscriber",
+					},
+					{
+						Group:                  "sources.knative.dev",
+						Kind:                   "ApiServerSource",
+						Name:                   "test-src-service-uri",
+						Namespace:              "test-ns",
+						ProvidedEventTypeTypes: []string{},
+						ProvidedEventTypes:     []string{},
+						Sink:                   &GroupKindNamespacedName{Kind: "Service", Namespace: "test-ns", Name: "test-service"},
+						SinkURI:                "http://test-service.test-ns.svc.cluster.local",
+						SinkBackstageID:        "test-service",
+					},
+					{
+						Group:                  "sources.knative.dev",
+						Kind:                   "ApiServerSource",
+						Name:                   "test-src-unknown-broker-uri",
+						Namespace:              "test-ns",
+						ProvidedEventTypeTypes: []string{},
+						ProvidedEventTypes:     []string{},
+						SinkURI:                "http://broker-ingress.knative-eventing.svc.cluster.local/other-ns/test-broker",
+					},
+					{
+						Group:                  "sources.knative.dev",
+						Kind:                   "ApiServerSource",
+						Name:                   "test-src-unknown-uri",
+						Namespace:              "test-ns",
+						ProvidedEventTypeTypes: []string{},
+						ProvidedEventTypes:     []string{},
+						SinkURI:                "https://example.com/events",
 					},
 				},
 			},
 		},
+		{
+			name: "Restricted to a namespace",
+			brokers: []*eventingv1.Broker{
+				testingv1.NewBroker("test-broker", "test-ns"),
+				testingv1.NewBroker("test-broker", "other-ns"),
+			},
+			eventTypes: []*eventingv1beta2.EventType{
+				testingv1beta2.NewEventType("test-eventtype", "test-ns",
+					testingv1beta2.WithEventTypeType("test-eventtype-type"),
+					testingv1beta2.WithEventTypeReference(brokerReference("test-broker", "test-ns")),
+				),
+				testingv1beta2.NewEventType("test-eventtype", "other-ns",
+					testingv1beta2.WithEventTypeType("test-eventtype-type"),
+					testingv1beta2.WithEventTypeReference(brokerReference("test-broker", "other-ns")),
+				),
+			},
+			triggers: []*eventingv1.Trigger{
+				testingv1.NewTrigger("test-trigger", "other-ns", "test-broker",
+					testingv1.WithTriggerSubscriberRef(
+						metav1.GroupVersionKind{
+							Group:   "",
+							Version: "v1",
+							Kind:    "Service",
+						},
+						"test-subscriber",
+						"other-ns",
+					),
+				),
+			},
+			extraObjects: []runtime.Object{
+				&corev1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-subscriber",
+						Namespace: "other-ns",
+						Labels:    map[string]string{"backstage.io/kubernetes-id": "test-subscriber"},
+					},
+				},
+			},
+			namespaces: []string{"test-ns"},
+			want: EventMesh{
+				Brokers: []Broker{
+					{
+						Name:               "test-broker",
+						Namespace:          "test-ns",
+						ProvidedEventTypes: []string{"test-ns/test-eventtype"},
+					},
+				},
+				EventTypes: []EventType{
+					{
+						Name:      "test-eventtype",
+						Namespace: "test-ns",
+						Type:      "test-eventtype-type",
+						Reference: &GroupKindNamespacedName{
+							Group:     "eventing.knative.dev",
+							Kind:      "Broker",
+							Namespace: "test-ns",
+							Name:      "test-broker",
+						},
+						ConsumedBy: []string{},
+					},
+				},
+				Triggers:      []Trigger{},
+				Subscriptions: []Subscription{},
+				Subscribables: make([]Subscribable, 0),
+				Sequences:     []Sequence{},
+				Parallels:     []Parallel{},
+				Sinks:         []Sink{},
+				Sources:       make([]Source, 0),
+			},
+		},
 	}
 	for _, tt := range tests {
 		logger := zap.NewNop().Sugar()
@@ -812,6 +2643,10 @@ func TestBuildEventMesh(t *testing.T) {
 			v1beta2objects = append(v1beta2objects, et)
 		}
 
+		for _, et := range tt.eventTypeAttributes {
+			v1beta2objects = append(v1beta2objects, et)
+		}
+
 		for _, b := range tt.brokers {
 			v1beta2objects = append(v1beta2objects, b)
 		}
@@ -819,11 +2654,24 @@ func TestBuildEventMesh(t *testing.T) {
 		for _, t := range tt.triggers {
 			v1beta2objects = append(v1beta2objects, t)
 		}
+
+		for _, sub := range tt.subscriptions {
+			v1beta2objects = append(v1beta2objects, sub)
+		}
+
+		for _, sq := range tt.sequences {
+			v1beta2objects = append(v1beta2objects, sq)
+		}
+
+		for _, p := range tt.parallels {
+			v1beta2objects = append(v1beta2objects, p)
+		}
 		sc := runtime.NewScheme()
 		_ = corev1.AddToScheme(sc)
 		_ = eventingv1.AddToScheme(sc)
 		_ = messagingv1.AddToScheme(sc)
 		_ = sourcesv1.AddToScheme(sc)
+		_ = flowsv1.AddToScheme(sc)
 		_ = apiextensionsv1.AddToScheme(sc)
 
 		fakeDynamicClient := dynamicfake.NewSimpleDynamicClient(sc, tt.extraObjects...)
@@ -833,8 +2681,16 @@ func TestBuildEventMesh(t *testing.T) {
 
 		fakeClient := fakeclientset.NewSimpleClientset(v1beta2objects...)
 
+		for resource, err := range tt.listErrors {
+			reactor := func(k8stesting.Action) (bool, runtime.Object, error) {
+				return true, nil, err
+			}
+			fakeClient.PrependReactor("list", resource, reactor)
+			fakeDynamicClient.PrependReactor("list", resource, reactor)
+		}
+
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := BuildEventMesh(ctx, fakeClient, fakeDynamicClient, logger)
+			got, err := BuildEventMesh(ctx, fakeClient, fakeDynamicClient, tt.namespaces, nil, logger)
 			if (err != nil) != tt.error {
 				t.Errorf("BuildEventMesh() error = %v, error %v", err, tt.error)
 				return
@@ -847,7 +2703,80 @@ func TestBuildEventMesh(t *testing.T) {
 	}
 }
 
+var (
+	apiServerSourceCRDSpec = apiextensionsv1.CustomResourceDefinitionSpec{
+		Group: "sources.knative.dev",
+		Names: apiextensionsv1.CustomResourceDefinitionNames{
+			Kind:     "ApiServerSource",
+			ListKind: "ApiServerSourceList",
+			Plural:   "apiserversources",
+		},
+		Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+			{
+				Name:    "v1",
+				Served:  true,
+				Storage: true,
+			},
+		},
+	}
+
+	inMemoryChannelCRDSpec = apiextensionsv1.CustomResourceDefinitionSpec{
+		Group: "messaging.knative.dev",
+		Names: apiextensionsv1.CustomResourceDefinitionNames{
+			Kind:     "InMemoryChannel",
+			ListKind: "InMemoryChannelList",
+			Plural:   "inmemorychannels",
+		},
+		Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+			{
+				Name:    "v1",
+				Served:  true,
+				Storage: true,
+			},
+		},
+	}
+
+	testTransitionTime = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	errTriggersForbidden = apierrors.NewForbidden(eventingv1.Resource("triggers"), "", errors.New("not allowed"))
+	errSourcesInternal   = apierrors.NewInternalError(errors.New("boom"))
+)
+
+func WithTriggerUID(uid string) testingv1.TriggerOption {
+	return func(t *eventingv1.Trigger) {
+		t.UID = types.UID(uid)
+	}
+}
+
+func WithTriggerSubscriber(ref *duckv1.KReference) testingv1.TriggerOption {
+	return func(t *eventingv1.Trigger) {
+		t.Spec.Subscriber = duckv1.Destination{Ref: ref}
+	}
+}
+
+func WithTriggerSubscriberURI(uri *apis.URL) testingv1.TriggerOption {
+	return func(t *eventingv1.Trigger) {
+		t.Spec.Subscriber = duckv1.Destination{URI: uri}
+	}
+}
+
+func WithTriggerDelivery(delivery *eventingduckv1.DeliverySpec) testingv1.TriggerOption {
+	return func(t *eventingv1.Trigger) {
+		t.Spec.Delivery = delivery
+	}
+}
+
+func WithTriggerStatus(status eventingv1.TriggerStatus) testingv1.TriggerOption {
+	return func(t *eventingv1.Trigger) {
+		t.Status = status
+	}
+}
+
 func WithEventTypeFilter(et string) testingv1.TriggerOption {
+	return WithAttributeFilter("type", et)
+}
+
+func WithAttributeFilter(name, value string) testingv1.TriggerOption {
 	return func(a *eventingv1.Trigger) {
 		if a.Spec.Filter == nil {
 			a.Spec.Filter = &eventingv1.TriggerFilter{}
@@ -855,7 +2784,13 @@ func WithEventTypeFilter(et string) testingv1.TriggerOption {
 		if a.Spec.Filter.Attributes == nil {
 			a.Spec.Filter.Attributes = make(map[string]string)
 		}
-		a.Spec.Filter.Attributes["type"] = et
+		a.Spec.Filter.Attributes[name] = value
+	}
+}
+
+func WithTriggerFilters(filters ...eventingv1.SubscriptionsAPIFilter) testingv1.TriggerOption {
+	return func(a *eventingv1.Trigger) {
+		a.Spec.Filters = filters
 	}
 }
 
@@ -878,6 +2813,14 @@ func WithEventTypeUID(uid string) testingv1beta2.EventTypeOption {
 	}
 }
 
+// eventTypeWithAttributes returns the event type in the version that declares its attributes.
+func eventTypeWithAttributes(name, namespace string, attributes ...eventingv1beta3.EventAttributeDefinition) *eventingv1beta3.EventType {
+	return &eventingv1beta3.EventType{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec:       eventingv1beta3.EventTypeSpec{Attributes: attributes},
+	}
+}
+
 func WithEventTypeSchema(url *apis.URL) testingv1beta2.EventTypeOption {
 	return func(a *eventingv1beta2.EventType) {
 		a.Spec.Schema = url
@@ -908,8 +2851,64 @@ func WithBrokerAnnotations(annotations map[string]string) testingv1.BrokerOption
 	}
 }
 
+func WithBrokerStatus(status eventingv1.BrokerStatus) testingv1.BrokerOption {
+	return func(a *eventingv1.Broker) {
+		a.Status = status
+	}
+}
+
+func WithEventTypeStatus(status eventingv1beta2.EventTypeStatus) testingv1beta2.EventTypeOption {
+	return func(a *eventingv1beta2.EventType) {
+		a.Status = status
+	}
+}
+
 func WithBrokerLabels(labels map[string]string) testingv1.BrokerOption {
 	return func(a *eventingv1.Broker) {
 		a.ObjectMeta.Labels = labels
 	}
 }
+
+// backstageService returns a Service that's registered on Backstage with its name.
+func sinkCRD(group, kind, plural string) *apiextensionsv1.CustomResourceDefinition {
+	return &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   plural + "." + group,
+			Labels: map[string]string{"duck.knative.dev/addressable": "true"},
+		},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: group,
+			Names: apiextensionsv1.CustomResourceDefinitionNames{
+				Kind:     kind,
+				ListKind: kind + "List",
+				Plural:   plural,
+			},
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{Name: "v1alpha1", Served: true, Storage: true},
+			},
+		},
+	}
+}
+
+func newSink(apiVersion, kind, name string, labels map[string]string, address string) *unstructured.Unstructured {
+	sink := &unstructured.Unstructured{}
+	sink.SetAPIVersion(apiVersion)
+	sink.SetKind(kind)
+	sink.SetNamespace("test-ns")
+	sink.SetName(name)
+	sink.SetLabels(labels)
+	if address != "" {
+		_ = unstructured.SetNestedField(sink.Object, address, "status", "address", "url")
+	}
+	return sink
+}
+
+func backstageService(name string) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "test-ns",
+			Labels:    map[string]string{BackstageKubernetesIDLabel: name},
+		},
+	}
+}
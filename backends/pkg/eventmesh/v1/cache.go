@@ -0,0 +1,594 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	eventingv1beta2 "knative.dev/eventing/pkg/apis/eventing/v1beta2"
+	eventingv1beta3 "knative.dev/eventing/pkg/apis/eventing/v1beta3"
+	flowsv1 "knative.dev/eventing/pkg/apis/flows/v1"
+	messagingv1 "knative.dev/eventing/pkg/apis/messaging/v1"
+
+	"knative.dev/backstage-plugins/backends/pkg/util"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	// defaultResyncPeriod is how often the informers replay their whole state, which makes the cache rebuild the
+	// event mesh even if nothing changed. This picks up changes that we don't watch, e.g. the labels of the subscribers.
+	defaultResyncPeriod = 10 * time.Minute
+	// defaultDebounce is how long the cache waits after a change before rebuilding the event mesh, so that a burst
+	// of changes (e.g. applying a bunch of manifests) results in a single rebuild.
+	defaultDebounce = time.Second
+	// defaultSyncTimeout is how long the cache waits for the informers to sync before building the event mesh.
+	defaultSyncTimeout = 30 * time.Second
+	// defaultWatchHistory is how many of the last built event meshes are kept, so that the watchers can resume from
+	// them with the changes since then instead of a new snapshot.
+	defaultWatchHistory = 16
+)
+
+var (
+	brokersGVR       = eventingv1.SchemeGroupVersion.WithResource("brokers")
+	triggersGVR      = eventingv1.SchemeGroupVersion.WithResource("triggers")
+	eventTypesGVR    = eventingv1beta2.SchemeGroupVersion.WithResource("eventtypes")
+	subscriptionsGVR = messagingv1.SchemeGroupVersion.WithResource("subscriptions")
+	sequencesGVR     = flowsv1.SchemeGroupVersion.WithResource("sequences")
+	parallelsGVR     = flowsv1.SchemeGroupVersion.WithResource("parallels")
+
+	// eventTypeAttributesGVR serves the event types along with the CloudEvents attributes that they declare
+	eventTypeAttributesGVR = eventingv1beta3.SchemeGroupVersion.WithResource("eventtypes")
+
+	// staticGVRs are the resources that are always watched by the cache.
+	// Sources, subscribables and sinks are watched based on the CRDs that exist in the cluster, which are watched as
+	// well unless the kinds are discovered with the discovery API.
+	staticGVRs = []schema.GroupVersionResource{brokersGVR, triggersGVR, eventTypesGVR, eventTypeAttributesGVR, subscriptionsGVR, sequencesGVR, parallelsGVR}
+)
+
+// EventMeshCache keeps an EventMesh that's built from informers instead of listing the resources on every request.
+// The event mesh is rebuilt from the informer caches whenever a watched resource changes.
+// The cache uses the credentials of the backend itself, so it's up to the callers to check if the requester is
+// allowed to see the cached data.
+type EventMeshCache struct {
+	dynamicClient dynamic.Interface
+	// discoveryClient is optional. With it, the kinds of the sources, subscribables and sinks are discovered with the
+	// discovery API on every rebuild instead of from the watched CRDs, see APIDiscoveryMode.
+	discoveryClient discovery.DiscoveryInterface
+	// backstageIDConfig is optional. Without it, the default Backstage ID resolution is used.
+	backstageIDConfig *BackstageIDConfigStore
+	// backstageIDs is optional. Without it, the Backstage IDs of the subscribers are resolved on every rebuild.
+	backstageIDs *BackstageIDCache
+	// mapper is optional. Without it, the resources of the subscribers are guessed from their kinds.
+	mapper *ResourceMapper
+	logger *zap.SugaredLogger
+
+	resyncPeriod time.Duration
+	debounce     time.Duration
+	syncTimeout  time.Duration
+
+	// changed is signaled when a watched resource changes
+	changed chan struct{}
+
+	informersLock sync.RWMutex
+	// informers are the running informers, by the resource they watch
+	informers map[schema.GroupVersionResource]*resourceInformer
+
+	// epoch tells the versions of the snapshots apart from the ones of an earlier run of the backend
+	epoch        int64
+	watchHistory int
+
+	snapshotLock sync.RWMutex
+	// snapshot is nil until the event mesh is built for the first time
+	snapshot *meshSnapshot
+	// version is the version of the last snapshot, it's increased on every rebuild
+	version uint64
+	// history holds the last snapshots, the oldest first
+	history []*meshSnapshot
+	// updated is closed when a new snapshot is stored, and replaced with a new channel
+	updated chan struct{}
+}
+
+// meshSnapshot is an event mesh built by the cache, along with what's needed to authorize the access to it.
+type meshSnapshot struct {
+	// resourceVersion identifies the snapshot for the watchers
+	resourceVersion string
+	eventMesh       EventMesh
+	// kindResources maps the kinds in the event mesh and on the way to the consumers to their resources
+	kindResources map[schema.GroupKind]schema.GroupResource
+	// consumers are the ways the events reach the consumers of the event types
+	consumers *consumerPaths
+}
+
+type resourceInformer struct {
+	informer cache.SharedIndexInformer
+	stop     context.CancelFunc
+}
+
+func NewEventMeshCache(dynamicClient dynamic.Interface, discoveryClient discovery.DiscoveryInterface, backstageIDConfig *BackstageIDConfigStore, backstageIDs *BackstageIDCache, mapper *ResourceMapper, logger *zap.SugaredLogger) *EventMeshCache {
+	c := &EventMeshCache{
+		dynamicClient:     dynamicClient,
+		discoveryClient:   discoveryClient,
+		backstageIDConfig: backstageIDConfig,
+		backstageIDs:      backstageIDs,
+		mapper:            mapper,
+		logger:            logger,
+		resyncPeriod:      defaultResyncPeriod,
+		debounce:          defaultDebounce,
+		syncTimeout:       defaultSyncTimeout,
+		changed:           make(chan struct{}, 1),
+		informers:         make(map[schema.GroupVersionResource]*resourceInformer),
+		epoch:             time.Now().UnixNano(),
+		watchHistory:      defaultWatchHistory,
+		updated:           make(chan struct{}),
+	}
+	// the Backstage IDs change with the configuration, so the event mesh is rebuilt
+	if backstageIDConfig != nil {
+		backstageIDConfig.notify(c.enqueue)
+	}
+	return c
+}
+
+// Run starts the informers and keeps the event mesh up to date until the context is done.
+func (c *EventMeshCache) Run(ctx context.Context) {
+	logger := c.logger
+
+	gvrs := staticGVRs
+	if c.discoveryClient == nil {
+		gvrs = append(slices.Clone(staticGVRs), crdGVR)
+	}
+	for _, gvr := range gvrs {
+		c.ensureInformer(ctx, gvr)
+	}
+
+	// a kind may never sync, e.g. when it's not installed or the backend can't list it. the event mesh is built
+	// anyway, the kinds that haven't synced are reported as warnings until they do.
+	logger.Infow("Waiting for the event mesh informers to sync")
+	syncCtx, cancel := context.WithTimeout(ctx, c.syncTimeout)
+	synced := cache.WaitForCacheSync(syncCtx.Done(), c.hasSynced(gvrs)...)
+	cancel()
+	if ctx.Err() != nil {
+		return
+	}
+	if !synced {
+		logger.Warnw("Some event mesh informers didn't sync in time", "timeout", c.syncTimeout)
+	}
+
+	for {
+		if err := c.rebuild(ctx); err != nil {
+			logger.Errorw("Error rebuilding event mesh", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-c.changed:
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(c.debounce):
+		}
+
+		// drop the changes that happened while we were waiting, the rebuild below covers them
+		select {
+		case <-c.changed:
+		default:
+		}
+	}
+}
+
+// EventMesh returns the last built event mesh and whether the event mesh has been built yet.
+// The returned event mesh is shared between the callers and must not be modified.
+func (c *EventMeshCache) EventMesh() (EventMesh, bool) {
+	snapshot := c.currentSnapshot()
+	if snapshot == nil {
+		return EventMesh{}, false
+	}
+	return snapshot.eventMesh, true
+}
+
+func (c *EventMeshCache) currentSnapshot() *meshSnapshot {
+	c.snapshotLock.RLock()
+	defer c.snapshotLock.RUnlock()
+
+	return c.snapshot
+}
+
+// watch returns the last snapshot, which is nil if the event mesh hasn't been built yet, and a channel that's closed
+// when a newer snapshot is stored.
+func (c *EventMeshCache) watch() (*meshSnapshot, <-chan struct{}) {
+	c.snapshotLock.RLock()
+	defer c.snapshotLock.RUnlock()
+
+	return c.snapshot, c.updated
+}
+
+// snapshotAt returns the snapshot with the given resourceVersion, or nil if it's not kept anymore.
+func (c *EventMeshCache) snapshotAt(resourceVersion string) *meshSnapshot {
+	c.snapshotLock.RLock()
+	defer c.snapshotLock.RUnlock()
+
+	for _, snapshot := range c.history {
+		if snapshot.resourceVersion == resourceVersion {
+			return snapshot
+		}
+	}
+	return nil
+}
+
+// store makes the event mesh the current snapshot and wakes up the watchers.
+func (c *EventMeshCache) store(eventMesh EventMesh, kindResources map[schema.GroupKind]schema.GroupResource, consumers *consumerPaths) {
+	c.snapshotLock.Lock()
+	defer c.snapshotLock.Unlock()
+
+	c.version++
+	c.snapshot = &meshSnapshot{
+		resourceVersion: fmt.Sprintf("%d-%d", c.epoch, c.version),
+		eventMesh:       eventMesh,
+		kindResources:   kindResources,
+		consumers:       consumers,
+	}
+
+	c.history = append(c.history, c.snapshot)
+	if len(c.history) > c.watchHistory {
+		c.history = c.history[len(c.history)-c.watchHistory:]
+	}
+
+	close(c.updated)
+	c.updated = make(chan struct{})
+}
+
+// Resources returns the resources that the cached event mesh is built from.
+func (c *EventMeshCache) Resources() []schema.GroupVersionResource {
+	c.informersLock.RLock()
+	defer c.informersLock.RUnlock()
+
+	resources := make([]schema.GroupVersionResource, 0, len(c.informers))
+	for gvr := range c.informers {
+		resources = append(resources, gvr)
+	}
+	return resources
+}
+
+// rebuild builds the event mesh from the informer caches and stores it.
+func (c *EventMeshCache) rebuild(ctx context.Context) error {
+	logger := c.logger
+
+	// the kinds are discovered once per rebuild, they're picked from the same resources by the informers and the build
+	var discovered *discoveryLister
+	if c.discoveryClient != nil {
+		discovered = &discoveryLister{clientLister: &clientLister{}, discoveryClient: c.discoveryClient}
+	}
+
+	kindResources, err := c.syncResourceInformers(ctx, discovered)
+	if err != nil {
+		return fmt.Errorf("error syncing informers for sources, subscribables and sinks: %w", err)
+	}
+
+	lister := &informerLister{
+		informers:     c.snapshotInformers(),
+		discovered:    discovered,
+		dynamicClient: c.dynamicClient,
+		mapper:        c.mapper,
+		logger:        logger,
+	}
+
+	consumers := newConsumerPaths()
+	eventMesh, err := buildEventMesh(ctx, lister, c.backstageIDConfig.Load(), c.backstageIDs.scoped(""), consumers, logger)
+	if err != nil {
+		return err
+	}
+
+	// the users are authorized for the resources on the way to the consumers as well
+	for gk, gr := range consumers.kindResources {
+		if _, ok := kindResources[gk]; !ok {
+			kindResources[gk] = gr
+		}
+	}
+	// the triggers and subscriptions whose subscribers can't be resolved are reported as warnings in the event mesh
+	if forbidden := lister.forbiddenResources(); len(forbidden) > 0 {
+		logger.Warnw("Not allowed to get some subscribers or their owners, their consumers are missing from the event mesh", "resources", forbidden)
+	}
+	c.store(eventMesh, kindResources, consumers)
+	recordEventMeshSize(ctx, eventMesh)
+
+	logger.Debugw("Rebuilt event mesh", "brokers", len(eventMesh.Brokers), "eventTypes", len(eventMesh.EventTypes), "subscribables", len(eventMesh.Subscribables), "sources", len(eventMesh.Sources), "sinks", len(eventMesh.Sinks))
+	return nil
+}
+
+// syncResourceInformers starts the informers for the sources, subscribables and sinks of the CRDs in the cluster and
+// stops the ones whose CRDs are gone. The CRDs are the discovered ones if the kinds are discovered with the
+// discovery API. It returns the kinds of the sources, subscribables and sinks mapped to their resources.
+func (c *EventMeshCache) syncResourceInformers(ctx context.Context, discovered *discoveryLister) (map[schema.GroupKind]schema.GroupResource, error) {
+	logger := c.logger
+
+	crdLister := &informerLister{informers: c.snapshotInformers(), discovered: discovered}
+	if discovered == nil && crdLister.informers[crdGVR] == nil {
+		return nil, fmt.Errorf("CRD informer is not running")
+	}
+
+	wanted := make(map[schema.GroupVersionResource]bool)
+	kindResources := make(map[schema.GroupKind]schema.GroupResource)
+	for _, selector := range []labels.Set{sourceCRDLabels, subscribableCRDLabels, sinkCRDLabels} {
+		crds, err := crdLister.ListCRDs(ctx, selector)
+		if err != nil {
+			return nil, err
+		}
+		if labels.Equals(selector, sinkCRDLabels) {
+			crds = filterSinkCRDs(crds)
+		}
+		for _, crd := range crds {
+			gvr, err := util.GVRFromUnstructured(crd)
+			if err != nil {
+				logger.Errorw("Error getting GVR from CRD", "crd", crd.GetName(), "error", err)
+				continue
+			}
+			wanted[gvr] = true
+
+			kindResources[crdKind(crd)] = gvr.GroupResource()
+		}
+	}
+
+	isStatic := make(map[schema.GroupVersionResource]bool, len(staticGVRs)+1)
+	for _, gvr := range staticGVRs {
+		isStatic[gvr] = true
+	}
+	isStatic[crdGVR] = true
+
+	c.informersLock.Lock()
+	for gvr, ri := range c.informers {
+		if !isStatic[gvr] && !wanted[gvr] {
+			logger.Infow("Stopping informer, its CRD is gone", "gvr", gvr)
+			ri.stop()
+			delete(c.informers, gvr)
+		}
+	}
+	c.informersLock.Unlock()
+
+	gvrs := make([]schema.GroupVersionResource, 0, len(wanted))
+	for gvr := range wanted {
+		c.ensureInformer(ctx, gvr)
+		gvrs = append(gvrs, gvr)
+	}
+
+	// an informer may never sync, e.g. when the CRD doesn't serve the version we picked.
+	// don't block the whole event mesh because of that, the resources of the informers that haven't synced are
+	// reported as warnings.
+	syncCtx, cancel := context.WithTimeout(ctx, c.syncTimeout)
+	defer cancel()
+	if !cache.WaitForCacheSync(syncCtx.Done(), c.hasSynced(gvrs)...) {
+		logger.Errorw("Some source, subscribable or sink informers didn't sync in time", "timeout", c.syncTimeout)
+	}
+	return kindResources, nil
+}
+
+// ensureInformer starts an informer for the given resource, unless there's one running already.
+func (c *EventMeshCache) ensureInformer(ctx context.Context, gvr schema.GroupVersionResource) {
+	c.informersLock.Lock()
+	defer c.informersLock.Unlock()
+
+	if _, ok := c.informers[gvr]; ok {
+		return
+	}
+
+	c.logger.Infow("Starting informer", "gvr", gvr)
+
+	informer := dynamicinformer.NewFilteredDynamicInformer(c.dynamicClient, gvr, metav1.NamespaceAll, c.resyncPeriod, cache.Indexers{}, nil).Informer()
+	_, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(interface{}) { c.enqueue() },
+		UpdateFunc: func(interface{}, interface{}) { c.enqueue() },
+		DeleteFunc: func(interface{}) { c.enqueue() },
+	})
+	if err != nil {
+		c.logger.Errorw("Error adding event handler to informer", "gvr", gvr, "error", err)
+	}
+
+	informerCtx, stop := context.WithCancel(ctx)
+	go informer.Run(informerCtx.Done())
+
+	c.informers[gvr] = &resourceInformer{
+		informer: informer,
+		stop:     stop,
+	}
+}
+
+// enqueue signals that a watched resource has changed, without blocking.
+func (c *EventMeshCache) enqueue() {
+	select {
+	case c.changed <- struct{}{}:
+	default:
+	}
+}
+
+func (c *EventMeshCache) hasSynced(gvrs []schema.GroupVersionResource) []cache.InformerSynced {
+	informers := c.snapshotInformers()
+
+	synced := make([]cache.InformerSynced, 0, len(gvrs))
+	for _, gvr := range gvrs {
+		if ri, ok := informers[gvr]; ok {
+			synced = append(synced, ri.informer.HasSynced)
+		}
+	}
+	return synced
+}
+
+func (c *EventMeshCache) snapshotInformers() map[schema.GroupVersionResource]*resourceInformer {
+	c.informersLock.RLock()
+	defer c.informersLock.RUnlock()
+
+	informers := make(map[schema.GroupVersionResource]*resourceInformer, len(c.informers))
+	for gvr, ri := range c.informers {
+		informers[gvr] = ri
+	}
+	return informers
+}
+
+// informerLister is a resourceLister that serves the resources from the informer caches.
+// Single resources that aren't watched (e.g. the subscribers) are fetched from the API server.
+type informerLister struct {
+	informers map[schema.GroupVersionResource]*resourceInformer
+	// discovered is optional. With it, the CRDs are made up from the discovered resources instead of being served
+	// from the informer cache.
+	discovered    *discoveryLister
+	dynamicClient dynamic.Interface
+	// mapper is optional. Without it, the resources are guessed from the kinds.
+	mapper *ResourceMapper
+	logger *zap.SugaredLogger
+
+	forbiddenLock sync.Mutex
+	// forbidden are the resources of the single resources that the backend wasn't allowed to get
+	forbidden map[schema.GroupResource]bool
+}
+
+var _ resourceLister = &informerLister{}
+
+func (l *informerLister) ListBrokers(ctx context.Context) ([]*eventingv1.Broker, error) {
+	return listTyped[eventingv1.Broker](ctx, l, brokersGVR)
+}
+
+func (l *informerLister) ListEventTypes(ctx context.Context) ([]*eventingv1beta2.EventType, error) {
+	return listTyped[eventingv1beta2.EventType](ctx, l, eventTypesGVR)
+}
+
+func (l *informerLister) ListEventTypeAttributes(ctx context.Context) ([]*eventingv1beta3.EventType, error) {
+	return listTyped[eventingv1beta3.EventType](ctx, l, eventTypeAttributesGVR)
+}
+
+func (l *informerLister) ListTriggers(ctx context.Context) ([]*eventingv1.Trigger, error) {
+	return listTyped[eventingv1.Trigger](ctx, l, triggersGVR)
+}
+
+func (l *informerLister) ListSubscriptions(ctx context.Context) ([]*messagingv1.Subscription, error) {
+	return listTyped[messagingv1.Subscription](ctx, l, subscriptionsGVR)
+}
+
+func (l *informerLister) ListSequences(ctx context.Context) ([]*flowsv1.Sequence, error) {
+	return listTyped[flowsv1.Sequence](ctx, l, sequencesGVR)
+}
+
+func (l *informerLister) ListParallels(ctx context.Context) ([]*flowsv1.Parallel, error) {
+	return listTyped[flowsv1.Parallel](ctx, l, parallelsGVR)
+}
+
+func (l *informerLister) ListCRDs(ctx context.Context, selector labels.Set) ([]*unstructured.Unstructured, error) {
+	if l.discovered != nil {
+		return l.discovered.ListCRDs(ctx, selector)
+	}
+	ri, ok := l.informers[crdGVR]
+	if !ok {
+		return nil, nil
+	}
+	return listFromIndexer(ri.informer.GetIndexer(), labels.SelectorFromSet(selector))
+}
+
+func (l *informerLister) ListResources(_ context.Context, gvr schema.GroupVersionResource) ([]*unstructured.Unstructured, error) {
+	ri, ok := l.informers[gvr]
+	if !ok || !ri.informer.HasSynced() {
+		l.logger.Infow("Informer is not running or not synced, skipping resources", "gvr", gvr)
+		return nil, fmt.Errorf("informer for %s is not synced", gvr.GroupResource())
+	}
+	return listFromIndexer(ri.informer.GetIndexer(), labels.Everything())
+}
+
+// ListNamespacedResources serves the resources from the informer cache if they're watched, otherwise they're listed
+// from the API server.
+func (l *informerLister) ListNamespacedResources(ctx context.Context, gvr schema.GroupVersionResource, namespace string) ([]*unstructured.Unstructured, error) {
+	ri, ok := l.informers[gvr]
+	if !ok || !ri.informer.HasSynced() {
+		return listPages[unstructured.Unstructured](ctx, metav1.ListOptions{}, l.dynamicClient.Resource(gvr).Namespace(namespace).List)
+	}
+
+	all, err := listFromIndexer(ri.informer.GetIndexer(), labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*unstructured.Unstructured, 0)
+	for _, u := range all {
+		if u.GetNamespace() == namespace {
+			result = append(result, u)
+		}
+	}
+	return result, nil
+}
+
+// GetResource fetches the resource from the API server. The resources that the backend isn't allowed to get are
+// recorded, see forbiddenResources.
+func (l *informerLister) GetResource(ctx context.Context, gvr schema.GroupVersionResource, namespace, name string) (*unstructured.Unstructured, error) {
+	resource, err := l.dynamicClient.Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsForbidden(err) {
+		l.forbiddenLock.Lock()
+		defer l.forbiddenLock.Unlock()
+		if l.forbidden == nil {
+			l.forbidden = make(map[schema.GroupResource]bool)
+		}
+		l.forbidden[gvr.GroupResource()] = true
+	}
+	return resource, err
+}
+
+// forbiddenResources returns the resources that the backend wasn't allowed to get, sorted.
+func (l *informerLister) forbiddenResources() []schema.GroupResource {
+	l.forbiddenLock.Lock()
+	defer l.forbiddenLock.Unlock()
+
+	if len(l.forbidden) == 0 {
+		return nil
+	}
+	resources := make([]schema.GroupResource, 0, len(l.forbidden))
+	for gr := range l.forbidden {
+		resources = append(resources, gr)
+	}
+	sort.Slice(resources, func(i, j int) bool {
+		return resources[i].String() < resources[j].String()
+	})
+	return resources
+}
+
+func (l *informerLister) ResourceFor(gvk schema.GroupVersionKind) (schema.GroupVersionResource, error) {
+	return l.mapper.resourceFor(gvk)
+}
+
+// listTyped lists the resources of the given GVR from the informer cache and converts them to the typed objects.
+func listTyped[T any](ctx context.Context, l *informerLister, gvr schema.GroupVersionResource) ([]*T, error) {
+	items, err := l.ListResources(ctx, gvr)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*T, 0, len(items))
+	for _, item := range items {
+		obj := new(T)
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.UnstructuredContent(), obj); err != nil {
+			return nil, fmt.Errorf("error converting %s %s/%s: %w", gvr.Resource, item.GetNamespace(), item.GetName(), err)
+		}
+		result = append(result, obj)
+	}
+	return result, nil
+}
+
+func listFromIndexer(indexer cache.Indexer, selector labels.Selector) ([]*unstructured.Unstructured, error) {
+	result := make([]*unstructured.Unstructured, 0)
+	err := cache.ListAll(indexer, selector, func(obj interface{}) {
+		if u, ok := obj.(*unstructured.Unstructured); ok {
+			result = append(result, u)
+		}
+	})
+	return result, err
+}
@@ -0,0 +1,371 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	discoveryfake "k8s.io/client-go/discovery/fake"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	eventingv1beta2 "knative.dev/eventing/pkg/apis/eventing/v1beta2"
+	eventingv1beta3 "knative.dev/eventing/pkg/apis/eventing/v1beta3"
+	flowsv1 "knative.dev/eventing/pkg/apis/flows/v1"
+	messagingv1 "knative.dev/eventing/pkg/apis/messaging/v1"
+	sourcesv1 "knative.dev/eventing/pkg/apis/sources/v1"
+
+	testingv1 "knative.dev/eventing/pkg/reconciler/testing/v1"
+	testingv1beta2 "knative.dev/eventing/pkg/reconciler/testing/v1beta2"
+
+	"knative.dev/backstage-plugins/backends/pkg/util"
+)
+
+func TestEventMeshCache(t *testing.T) {
+	sc := runtime.NewScheme()
+	_ = corev1.AddToScheme(sc)
+	_ = eventingv1.AddToScheme(sc)
+	_ = eventingv1beta2.AddToScheme(sc)
+	_ = eventingv1beta3.AddToScheme(sc)
+	_ = messagingv1.AddToScheme(sc)
+	_ = flowsv1.AddToScheme(sc)
+	_ = sourcesv1.AddToScheme(sc)
+	_ = apiextensionsv1.AddToScheme(sc)
+
+	sourceCRD := &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "apiserversources.sources.knative.dev",
+			Labels: map[string]string{
+				"duck.knative.dev/source": "true",
+			},
+		},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: "sources.knative.dev",
+			Names: apiextensionsv1.CustomResourceDefinitionNames{
+				Kind:     "ApiServerSource",
+				ListKind: "ApiServerSourceList",
+				Plural:   "apiserversources",
+			},
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{
+					Name:    "v1",
+					Served:  true,
+					Storage: true,
+				},
+			},
+		},
+	}
+
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(sc,
+		testingv1.NewBroker("test-broker", "test-ns"),
+		testingv1beta2.NewEventType("test-eventtype", "test-ns",
+			testingv1beta2.WithEventTypeType("test-eventtype-type"),
+			testingv1beta2.WithEventTypeReference(brokerReference("test-broker", "test-ns")),
+		),
+		testingv1.NewTrigger("test-trigger", "test-ns", "test-broker",
+			testingv1.WithTriggerSubscriberRef(
+				metav1.GroupVersionKind{
+					Group:   "",
+					Version: "v1",
+					Kind:    "Service",
+				},
+				"test-subscriber",
+				"test-ns",
+			),
+		),
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-subscriber",
+				Namespace: "test-ns",
+				Labels:    map[string]string{"backstage.io/kubernetes-id": "test-subscriber"},
+			},
+		},
+		sourceCRD,
+		&sourcesv1.ApiServerSource{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-src",
+				Namespace: "test-ns",
+			},
+		},
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	c := NewEventMeshCache(dynamicClient, nil, nil, nil, nil, zap.NewNop().Sugar())
+	c.debounce = 10 * time.Millisecond
+	go c.Run(ctx)
+
+	got := waitForEventMesh(t, c, func(em EventMesh) bool {
+		return len(em.Sources) == 1
+	})
+
+	want := EventMesh{
+		Brokers: []Broker{
+			{
+				Name:               "test-broker",
+				Namespace:          "test-ns",
+				ProvidedEventTypes: []string{"test-ns/test-eventtype"},
+			},
+		},
+		EventTypes: []EventType{
+			{
+				Name:      "test-eventtype",
+				Namespace: "test-ns",
+				Type:      "test-eventtype-type",
+				Reference: &GroupKindNamespacedName{
+					Group:     "eventing.knative.dev",
+					Kind:      "Broker",
+					Namespace: "test-ns",
+					Name:      "test-broker",
+				},
+				ConsumedBy: []string{"test-subscriber"},
+				Consumers:  []EventTypeConsumer{{BackstageID: "test-subscriber", Hop: &GroupKindNamespacedName{Kind: "Service", Namespace: "test-ns", Name: "test-subscriber"}}},
+			},
+		},
+		Subscribables: make([]Subscribable, 0),
+		Subscriptions: []Subscription{},
+		Sequences:     []Sequence{},
+		Parallels:     []Parallel{},
+		Sinks:         []Sink{},
+		DiscoveredKinds: []DiscoveredKind{
+			{Group: "sources.knative.dev", Kind: "ApiServerSource", Resource: "apiserversources", Version: "v1"},
+		},
+		Sources: []Source{
+			{
+				Group:                  "sources.knative.dev",
+				Kind:                   "ApiServerSource",
+				Name:                   "test-src",
+				Namespace:              "test-ns",
+				ProvidedEventTypeTypes: []string{},
+				ProvidedEventTypes:     []string{},
+			},
+		},
+		Triggers: []Trigger{
+			{
+				Name:        "test-trigger",
+				Namespace:   "test-ns",
+				Broker:      GroupKindNamespacedName{Group: "eventing.knative.dev", Kind: "Broker", Namespace: "test-ns", Name: "test-broker"},
+				Subscriber:  Destination{Ref: &GroupKindNamespacedName{Group: "", Kind: "Service", Namespace: "test-ns", Name: "test-subscriber"}},
+				BackstageID: "test-subscriber",
+				Conditions:  []Condition{},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Error("EventMesh() (-want, +got):", diff)
+	}
+
+	// a new broker is picked up without asking for it
+	broker := &unstructured.Unstructured{}
+	broker.SetAPIVersion("eventing.knative.dev/v1")
+	broker.SetKind("Broker")
+	broker.SetNamespace("test-ns")
+	broker.SetName("test-broker-2")
+	if _, err := dynamicClient.Resource(brokersGVR).Namespace("test-ns").Create(ctx, broker, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("Error creating broker: %v", err)
+	}
+
+	waitForEventMesh(t, c, func(em EventMesh) bool {
+		return len(em.Brokers) == 2
+	})
+
+	// the sources are gone with their CRD
+	if err := dynamicClient.Resource(crdGVR).Delete(ctx, sourceCRD.Name, metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("Error deleting CRD: %v", err)
+	}
+
+	waitForEventMesh(t, c, func(em EventMesh) bool {
+		return len(em.Sources) == 0
+	})
+
+	for _, gvr := range c.Resources() {
+		if gvr.Resource == "apiserversources" {
+			t.Errorf("Resources() = %v, want no apiserversources", c.Resources())
+		}
+	}
+}
+
+func TestEventMeshCacheWithAPIDiscovery(t *testing.T) {
+	sc := runtime.NewScheme()
+	_ = corev1.AddToScheme(sc)
+	_ = eventingv1.AddToScheme(sc)
+	_ = eventingv1beta2.AddToScheme(sc)
+	_ = eventingv1beta3.AddToScheme(sc)
+	_ = messagingv1.AddToScheme(sc)
+	_ = flowsv1.AddToScheme(sc)
+	_ = sourcesv1.AddToScheme(sc)
+
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(sc,
+		&sourcesv1.ApiServerSource{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-src",
+				Namespace: "test-ns",
+			},
+		},
+	)
+	// the backend isn't allowed to list the CRDs
+	dynamicClient.PrependReactor("*", "customresourcedefinitions", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(crdGVR.GroupResource(), "", errors.New("not allowed"))
+	})
+
+	discoveryClient := &discoveryfake.FakeDiscovery{Fake: &k8stesting.Fake{Resources: []*metav1.APIResourceList{
+		{
+			GroupVersion: "sources.knative.dev/v1",
+			APIResources: []metav1.APIResource{
+				{Name: "apiserversources", Kind: "ApiServerSource", Namespaced: true, Verbs: []string{"get", "list", "watch"}, Categories: []string{"all", "knative", "sources"}},
+			},
+		},
+	}}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	c := NewEventMeshCache(dynamicClient, discoveryClient, nil, nil, nil, zap.NewNop().Sugar())
+	c.debounce = 10 * time.Millisecond
+	go c.Run(ctx)
+
+	got := waitForEventMesh(t, c, func(em EventMesh) bool {
+		return len(em.Sources) == 1
+	})
+
+	wantKinds := []DiscoveredKind{
+		{Group: "sources.knative.dev", Kind: "ApiServerSource", Resource: "apiserversources", Version: "v1"},
+	}
+	if diff := cmp.Diff(wantKinds, got.DiscoveredKinds); diff != "" {
+		t.Error("DiscoveredKinds (-want, +got):", diff)
+	}
+	if len(got.Warnings) != 0 {
+		t.Errorf("Warnings = %v, want none", got.Warnings)
+	}
+
+	for _, gvr := range c.Resources() {
+		if gvr == crdGVR {
+			t.Errorf("Resources() = %v, want the CRDs not to be watched", c.Resources())
+		}
+	}
+}
+
+func TestEventMeshCacheForbiddenSubscribers(t *testing.T) {
+	sc := runtime.NewScheme()
+	_ = corev1.AddToScheme(sc)
+	_ = eventingv1.AddToScheme(sc)
+	_ = eventingv1beta2.AddToScheme(sc)
+	_ = eventingv1beta3.AddToScheme(sc)
+	_ = messagingv1.AddToScheme(sc)
+	_ = flowsv1.AddToScheme(sc)
+	_ = apiextensionsv1.AddToScheme(sc)
+
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(sc,
+		testingv1.NewBroker("test-broker", "test-ns"),
+		testingv1beta2.NewEventType("test-eventtype", "test-ns",
+			testingv1beta2.WithEventTypeType("test-eventtype-type"),
+			testingv1beta2.WithEventTypeReference(brokerReference("test-broker", "test-ns")),
+		),
+		testingv1.NewTrigger("test-trigger", "test-ns", "test-broker",
+			testingv1.WithTriggerSubscriberRef(
+				metav1.GroupVersionKind{
+					Group:   "",
+					Version: "v1",
+					Kind:    "Service",
+				},
+				"test-subscriber",
+				"test-ns",
+			),
+		),
+	)
+	// the backend isn't allowed to get the subscriber
+	dynamicClient.PrependReactor("get", "services", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(corev1.Resource("services"), "test-subscriber", errors.New("not allowed"))
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	c := NewEventMeshCache(dynamicClient, nil, nil, nil, nil, zap.NewNop().Sugar())
+	c.debounce = 10 * time.Millisecond
+	go c.Run(ctx)
+
+	got := waitForEventMesh(t, c, func(em EventMesh) bool {
+		return len(em.Triggers) == 1
+	})
+
+	// the cached event mesh is still served, the trigger whose subscriber can't be resolved is reported
+	var warned []string
+	for _, w := range got.Warnings {
+		warned = append(warned, util.GKNamespacedName(w.Group, w.Kind, w.Namespace, w.Name))
+	}
+	if diff := cmp.Diff([]string{"eventing.knative.dev/Trigger/test-ns/test-trigger"}, warned); diff != "" {
+		t.Error("Warnings (-want, +got):", diff)
+	}
+	if got.EventTypes[0].Consumers != nil {
+		t.Errorf("Consumers = %v, want none", got.EventTypes[0].Consumers)
+	}
+}
+
+func TestEventMeshCacheUnsyncedInformers(t *testing.T) {
+	sc := runtime.NewScheme()
+	_ = corev1.AddToScheme(sc)
+	_ = eventingv1.AddToScheme(sc)
+	_ = eventingv1beta2.AddToScheme(sc)
+	_ = eventingv1beta3.AddToScheme(sc)
+	_ = messagingv1.AddToScheme(sc)
+	_ = flowsv1.AddToScheme(sc)
+	_ = apiextensionsv1.AddToScheme(sc)
+
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(sc,
+		testingv1.NewBroker("test-broker", "test-ns"),
+	)
+	// the backend isn't allowed to list the sequences, so their informer never syncs
+	dynamicClient.PrependReactor("list", "sequences", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(sequencesGVR.GroupResource(), "", errors.New("not allowed"))
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	c := NewEventMeshCache(dynamicClient, nil, nil, nil, nil, zap.NewNop().Sugar())
+	c.debounce = 10 * time.Millisecond
+	c.syncTimeout = 100 * time.Millisecond
+	go c.Run(ctx)
+
+	got := waitForEventMesh(t, c, func(em EventMesh) bool {
+		return len(em.Brokers) == 1
+	})
+
+	var kinds []string
+	for _, w := range got.Warnings {
+		kinds = append(kinds, w.Kind)
+	}
+	if diff := cmp.Diff([]string{"Sequence"}, kinds); diff != "" {
+		t.Error("Kinds of the warnings (-want, +got):", diff)
+	}
+}
+
+// waitForEventMesh waits until the cached event mesh satisfies the condition and returns it.
+func waitForEventMesh(t *testing.T, c *EventMeshCache, condition func(EventMesh) bool) EventMesh {
+	t.Helper()
+
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		if em, ok := c.EventMesh(); ok && condition(em) {
+			return em
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	em, _ := c.EventMesh()
+	t.Fatalf("Timed out waiting for the event mesh, last seen: %+v", em)
+	return em
+}
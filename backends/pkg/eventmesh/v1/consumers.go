@@ -0,0 +1,637 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	flowsv1 "knative.dev/eventing/pkg/apis/flows/v1"
+	v1 "knative.dev/eventing/pkg/apis/messaging/v1"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	"knative.dev/backstage-plugins/backends/pkg/util"
+)
+
+// batchMinSubscribers is how many subscribers of the same kind in a namespace it takes to list them all at once
+// instead of fetching them one by one.
+const batchMinSubscribers = 2
+
+// consumer is a Backstage entity that eventually receives the events of an event type.
+type consumer struct {
+	backstageId string
+	// indeterminate is true if a filter on the way to the consumer depends on attributes that are only known at runtime
+	indeterminate bool
+	// via are the resources that the events go through on the way to the consumer, and the consumer itself
+	via []meshResource
+}
+
+// consumerTracer follows the subscribers of the triggers and the subscriptions to the resources that eventually
+// consume the events. Brokers, channels, sequences and parallels only forward the events they receive, so the
+// consumers are the subscribers and the sinks further down the chain, not those resources themselves.
+type consumerTracer struct {
+	lister            resourceLister
+	backstageIDConfig *BackstageIDConfig
+	logger            *zap.SugaredLogger
+
+	// channelKinds are the kinds of the subscribables, which forward the events to their subscriptions
+	channelKinds map[schema.GroupKind]bool
+	// triggersByBroker are the triggers of the brokers.
+	// map key: "<group>/<kind>/<namespace>/<name>" of the broker
+	triggersByBroker map[string][]*eventingv1.Trigger
+	// subscriptionsByChannel are the subscriptions of the channels.
+	// map key: "<group>/<kind>/<namespace>/<name>" of the channel
+	subscriptionsByChannel map[string][]*v1.Subscription
+	// sequences and parallels are the flows, which forward the events to their steps and branches.
+	// map key: "<group>/<kind>/<namespace>/<name>" of the flow
+	sequences map[string]*flowsv1.Sequence
+	parallels map[string]*flowsv1.Parallel
+	// sinks are the sinks, which consume the events themselves.
+	// map key: "<group>/<kind>/<namespace>/<name>" of the sink
+	sinks map[string]*unstructured.Unstructured
+	// receivedEventTypes are the event types that are sent to the flows and the sinks, in the order they're found.
+	// map key: "<group>/<kind>/<namespace>/<name>" of the flow or the sink
+	receivedEventTypes map[string][]string
+	received           map[string]map[string]bool
+	// backstageIds are the resolved Backstage IDs of the subscribers, so that each subscriber is only resolved once
+	// map key: "<group>/<kind>/<namespace>/<name>" of the subscriber, or its URIs
+	backstageIds map[string]backstageIDResult
+	// sharedIds are the Backstage IDs that are shared with the other builds. It's optional.
+	sharedIds *scopedBackstageIDs
+	// warnings collect the kinds of the subscribers that the cluster doesn't serve, each kind once
+	warnings     *warnings
+	kindsLock    sync.Mutex
+	unknownKinds map[schema.GroupKind]bool
+	// paths is optional, it records the ways the events reach the consumers
+	paths *consumerPaths
+	// eventTypeAttributes are the attributes that the event types declare, see fetchEventTypeAttributes.
+	// map key: "<namespace>/<name>" of the event type
+	eventTypeAttributes map[string]map[string]string
+}
+
+type backstageIDResult struct {
+	backstageId string
+	err         error
+}
+
+func newConsumerTracer(lister resourceLister, backstageIDConfig *BackstageIDConfig, sharedIds *scopedBackstageIDs, paths *consumerPaths, warnings *warnings, eventTypeAttributes map[string]map[string]string, subscribables []*Subscribable, triggers []*eventingv1.Trigger, subscriptions []*v1.Subscription, sequences []*flowsv1.Sequence, parallels []*flowsv1.Parallel, sinks []*unstructured.Unstructured, logger *zap.SugaredLogger) *consumerTracer {
+	t := &consumerTracer{
+		lister:            lister,
+		backstageIDConfig: backstageIDConfig,
+		logger:            logger,
+		channelKinds: map[schema.GroupKind]bool{
+			v1.Kind("Channel"): true,
+		},
+		triggersByBroker:       make(map[string][]*eventingv1.Trigger),
+		subscriptionsByChannel: make(map[string][]*v1.Subscription),
+		sequences:              make(map[string]*flowsv1.Sequence),
+		parallels:              make(map[string]*flowsv1.Parallel),
+		sinks:                  make(map[string]*unstructured.Unstructured),
+		receivedEventTypes:     make(map[string][]string),
+		received:               make(map[string]map[string]bool),
+		backstageIds:           make(map[string]backstageIDResult),
+		sharedIds:              sharedIds,
+		warnings:               warnings,
+		unknownKinds:           make(map[schema.GroupKind]bool),
+		paths:                  paths,
+		eventTypeAttributes:    eventTypeAttributes,
+	}
+	for _, s := range subscribables {
+		t.channelKinds[schema.GroupKind{Group: s.Group, Kind: s.Kind}] = true
+	}
+	for _, trigger := range triggers {
+		key := triggerBrokerReference(trigger).String()
+		t.triggersByBroker[key] = append(t.triggersByBroker[key], trigger)
+	}
+	for _, subscription := range subscriptions {
+		key := convertReference(subscription.Spec.Channel, subscription.Namespace).String()
+		t.subscriptionsByChannel[key] = append(t.subscriptionsByChannel[key], subscription)
+	}
+	for _, sequence := range sequences {
+		t.sequences[util.GKNamespacedName(flowsv1.SchemeGroupVersion.Group, "Sequence", sequence.Namespace, sequence.Name)] = sequence
+	}
+	for _, parallel := range parallels {
+		t.parallels[util.GKNamespacedName(flowsv1.SchemeGroupVersion.Group, "Parallel", parallel.Namespace, parallel.Name)] = parallel
+	}
+	for _, sink := range sinks {
+		t.sinks[sinkKey(sink)] = sink
+	}
+	return t
+}
+
+// forwards returns true if the referenced resource forwards the events it receives to other subscribers.
+func (t *consumerTracer) forwards(ref GroupKindNamespacedName) bool {
+	gk := schema.GroupKind{Group: ref.Group, Kind: ref.Kind}
+	return gk == eventingv1.Kind("Broker") || gk == flowsv1.Kind("Sequence") || gk == flowsv1.Kind("Parallel") || t.channelKinds[gk]
+}
+
+// backstageID returns the Backstage ID of a subscriber that consumes the events itself.
+func (t *consumerTracer) backstageID(ctx context.Context, sub *subscriber) (string, error) {
+	key := t.subscriberKey(sub)
+	result, ok := t.backstageIds[key]
+	recordBackstageIDLookup(ctx, backstageIDCacheBuild, ok)
+	if ok {
+		return result.backstageId, result.err
+	}
+
+	if backstageId, ok := t.sharedBackstageID(ctx, key); ok {
+		t.backstageIds[key] = backstageIDResult{backstageId: backstageId}
+		return backstageId, nil
+	}
+
+	result = t.resolve(ctx, key, sub)
+	t.backstageIds[key] = result
+	return result.backstageId, result.err
+}
+
+// sharedBackstageID returns the Backstage ID of the subscriber from the cache that's shared by the builds, if any.
+func (t *consumerTracer) sharedBackstageID(ctx context.Context, key string) (string, bool) {
+	if t.sharedIds == nil {
+		return "", false
+	}
+	backstageId, ok := t.sharedIds.get(key, t.backstageIDConfig)
+	recordBackstageIDLookup(ctx, backstageIDCacheShared, ok)
+	return backstageId, ok
+}
+
+// resolve resolves the Backstage ID of the subscriber and shares it with the next builds.
+// The errors aren't shared, the next builds try again.
+func (t *consumerTracer) resolve(ctx context.Context, key string, sub *subscriber) backstageIDResult {
+	t.mapResource(sub)
+	backstageId, err := t.backstageIDConfig.resolveBackstageID(ctx, t.lister, sub, t.logger)
+	if err == nil {
+		t.sharedIds.put(key, t.backstageIDConfig, backstageId)
+	}
+	return backstageIDResult{backstageId: backstageId, err: err}
+}
+
+// mapResource maps the kind of the referenced subscriber to its resource, unless the resource is known already.
+// The kinds that the cluster doesn't serve are reported in the warnings.
+func (t *consumerTracer) mapResource(sub *subscriber) {
+	if sub.ref == nil || sub.object != nil || sub.fetched || !sub.gvr.Empty() {
+		return
+	}
+
+	gvk := sub.gvk()
+	gvr, err := t.lister.ResourceFor(gvk)
+	switch {
+	case meta.IsNoMatchError(err):
+		sub.fetched = true
+		t.logger.Debugw("Kind of the subscriber is not served by the cluster", "kind", gvk, "error", err)
+
+		t.kindsLock.Lock()
+		defer t.kindsLock.Unlock()
+		if !t.unknownKinds[gvk.GroupKind()] {
+			t.unknownKinds[gvk.GroupKind()] = true
+			t.warnings.addKind(gvk.GroupKind(), fmt.Errorf("unknown kind of subscribers: %w", err))
+		}
+	case err != nil:
+		t.logger.Errorw("Error mapping the kind of the subscriber to its resource", "kind", gvk, "error", err)
+	default:
+		sub.gvr = gvr
+	}
+}
+
+// subscriberKey returns the key that the Backstage ID of the subscriber is resolved once for.
+// The sinks are listed already, so they're attached to the subscribers that refer to them to not fetch them again.
+func (t *consumerTracer) subscriberKey(sub *subscriber) string {
+	if sub.ref == nil {
+		return strings.Join(sub.uris, ",")
+	}
+	key := convertReference(*sub.ref, sub.ref.Namespace).String()
+	if sink, ok := t.sinks[key]; ok && sub.object == nil {
+		sub.object = sink
+	}
+	return key
+}
+
+// prefetchBackstageIDs resolves the Backstage IDs of the distinct subscribers in parallel, before the tracing.
+// The ones in the shared cache aren't resolved again, and the rest are fetched in batches where possible.
+func (t *consumerTracer) prefetchBackstageIDs(ctx context.Context, subs []*subscriber) {
+	ctx, span := startSpan(ctx, "prefetchBackstageIDs")
+	defer span.End()
+
+	pending := make(map[string]*subscriber)
+	keys := make([]string, 0)
+	for _, sub := range subs {
+		if sub.ref == nil && len(sub.uris) == 0 {
+			continue
+		}
+		if sub.ref != nil && t.forwards(convertReference(*sub.ref, sub.ref.Namespace)) {
+			continue
+		}
+		key := t.subscriberKey(sub)
+		if _, ok := t.backstageIds[key]; ok {
+			continue
+		}
+		if _, ok := pending[key]; ok {
+			continue
+		}
+		recordBackstageIDLookup(ctx, backstageIDCacheBuild, false)
+		if backstageId, ok := t.sharedBackstageID(ctx, key); ok {
+			t.backstageIds[key] = backstageIDResult{backstageId: backstageId}
+			continue
+		}
+		pending[key] = sub
+		keys = append(keys, key)
+	}
+
+	// the subscribers that are resolved, rather than found in the caches
+	span.SetAttributes(subscribersAttr.With(len(keys)))
+
+	batch := make([]*subscriber, 0, len(keys))
+	for _, key := range keys {
+		t.mapResource(pending[key])
+		batch = append(batch, pending[key])
+	}
+	t.fetchSubscribers(ctx, batch)
+
+	results := make([]backstageIDResult, len(keys))
+	g := &errgroup.Group{}
+	g.SetLimit(fetchConcurrency)
+	for i, key := range keys {
+		g.Go(func() error {
+			results[i] = t.resolve(ctx, key, pending[key])
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	for i, key := range keys {
+		t.backstageIds[key] = results[i]
+	}
+}
+
+// subscriberGroup is the resource and the namespace of the subscribers that are listed at once.
+type subscriberGroup struct {
+	gvr       schema.GroupVersionResource
+	namespace string
+}
+
+// fetchSubscribers lists the subscriber resources of the same kind in the same namespace with a single call, and
+// attaches them to the subscribers. The lone ones and the ones that can't be listed are fetched one by one.
+func (t *consumerTracer) fetchSubscribers(ctx context.Context, subs []*subscriber) {
+	ctx, span := startSpan(ctx, "fetchSubscribers", subscribersAttr.With(len(subs)))
+	defer span.End()
+
+	groups := make(map[subscriberGroup][]*subscriber)
+	order := make([]subscriberGroup, 0)
+	for _, sub := range subs {
+		if sub.ref == nil || sub.object != nil || sub.fetched || sub.gvr.Empty() {
+			continue
+		}
+		group := subscriberGroup{gvr: sub.gvr, namespace: sub.ref.Namespace}
+		if _, ok := groups[group]; !ok {
+			order = append(order, group)
+		}
+		groups[group] = append(groups[group], sub)
+	}
+
+	g := &errgroup.Group{}
+	g.SetLimit(fetchConcurrency)
+	for _, group := range order {
+		members := groups[group]
+		if len(members) < batchMinSubscribers {
+			continue
+		}
+		g.Go(func() error {
+			resources, err := t.lister.ListNamespacedResources(ctx, group.gvr, group.namespace)
+			if err != nil {
+				t.logger.Debugw("Error listing the subscribers, fetching them one by one", "gvr", group.gvr, "namespace", group.namespace, "error", err)
+				return nil
+			}
+
+			byName := make(map[string]*unstructured.Unstructured, len(resources))
+			for _, resource := range resources {
+				byName[resource.GetName()] = resource
+			}
+			for _, sub := range members {
+				sub.object = byName[sub.ref.Name]
+				sub.fetched = true
+			}
+			return nil
+		})
+	}
+	_ = g.Wait()
+}
+
+// subscribersOf returns the subscribers of the triggers, the subscriptions, and the steps, branches and replies of the
+// flows.
+func subscribersOf(triggers []*eventingv1.Trigger, subscriptions []*v1.Subscription, sequences []*flowsv1.Sequence, parallels []*flowsv1.Parallel) []*subscriber {
+	subs := make([]*subscriber, 0, len(triggers)+len(subscriptions))
+	for _, trigger := range triggers {
+		subs = append(subs, newSubscriber(trigger.Spec.Subscriber, urlString(trigger.Status.SubscriberURI), trigger.Namespace))
+	}
+	for _, subscription := range subscriptions {
+		if subscription.Spec.Subscriber != nil {
+			subs = append(subs, newSubscriber(*subscription.Spec.Subscriber, urlString(subscription.Status.PhysicalSubscription.SubscriberURI), subscription.Namespace))
+		}
+	}
+	for _, sequence := range sequences {
+		for _, step := range sequence.Spec.Steps {
+			subs = append(subs, newSubscriber(step.Destination, "", sequence.Namespace))
+		}
+		if sequence.Spec.Reply != nil {
+			subs = append(subs, newSubscriber(*sequence.Spec.Reply, "", sequence.Namespace))
+		}
+	}
+	for _, parallel := range parallels {
+		for _, branch := range parallel.Spec.Branches {
+			if branch.Filter != nil {
+				subs = append(subs, newSubscriber(*branch.Filter, "", parallel.Namespace))
+			}
+			subs = append(subs, newSubscriber(branch.Subscriber, "", parallel.Namespace))
+			if branch.Reply != nil {
+				subs = append(subs, newSubscriber(*branch.Reply, "", parallel.Namespace))
+			}
+		}
+		if parallel.Spec.Reply != nil {
+			subs = append(subs, newSubscriber(*parallel.Spec.Reply, "", parallel.Namespace))
+		}
+	}
+	return subs
+}
+
+// subscriberBackstageID returns the Backstage ID of the subscriber itself, or an empty string if it only forwards
+// the events.
+func (t *consumerTracer) subscriberBackstageID(ctx context.Context, sub *subscriber) (string, error) {
+	if sub.ref == nil {
+		return t.backstageID(ctx, sub)
+	}
+	t.mapKind(sub.gvk())
+	if t.forwards(convertReference(*sub.ref, sub.ref.Namespace)) {
+		return "", nil
+	}
+	return t.backstageID(ctx, sub)
+}
+
+// mapKind records the resource of the kind in the paths. The kinds that can't be mapped aren't visible to anyone.
+func (t *consumerTracer) mapKind(gvk schema.GroupVersionKind) {
+	gk := gvk.GroupKind()
+	if t.paths == nil || t.paths.mapped(gk) {
+		return
+	}
+	gvr, err := t.lister.ResourceFor(gvk)
+	if err != nil {
+		t.logger.Debugw("Error mapping the kind to its resource", "kind", gvk, "error", err)
+		return
+	}
+	t.paths.kindResources[gk] = gvr.GroupResource()
+}
+
+// consumerReference returns a reference to a sink or a Kubernetes service that an address is resolved to.
+func (t *consumerTracer) consumerReference(ref GroupKindNamespacedName) *duckv1.KReference {
+	apiVersion := "v1"
+	if sink, ok := t.sinks[ref.String()]; ok {
+		apiVersion = sink.GetAPIVersion()
+	}
+	return &duckv1.KReference{
+		APIVersion: apiVersion,
+		Kind:       ref.Kind,
+		Namespace:  ref.Namespace,
+		Name:       ref.Name,
+	}
+}
+
+// trace returns the consumers that the events of the event type reach when they're sent to the destination.
+// The path holds the resources the events went through so far, the events are not followed into a cycle.
+func (t *consumerTracer) trace(ctx context.Context, destination duckv1.Destination, resolvedURI, namespace string, et *EventType, path map[string]bool) ([]consumer, error) {
+	sub := newSubscriber(destination, resolvedURI, namespace)
+	if sub.ref == nil {
+		if len(sub.uris) == 0 {
+			return nil, nil
+		}
+		return t.consumer(ctx, sub)
+	}
+
+	ref := convertReference(*sub.ref, namespace)
+	if !t.forwards(ref) {
+		// the sinks are consumers as well, whether they're in Backstage or not
+		if _, ok := t.sinks[ref.String()]; ok {
+			t.receive(ref.String(), et)
+		}
+		return t.consumer(ctx, sub)
+	}
+
+	key := ref.String()
+	if path[key] {
+		t.logger.Infow("Events are forwarded in a cycle; not following them further", "eventType", et.NamespacedName(), "resource", key)
+		return nil, nil
+	}
+	path[key] = true
+	defer delete(path, key)
+
+	var (
+		consumers []consumer
+		err       error
+	)
+	switch {
+	case ref.Group == eventingv1.SchemeGroupVersion.Group && ref.Kind == "Broker":
+		consumers, err = t.traceBroker(ctx, key, et, path)
+	case ref.Group == flowsv1.SchemeGroupVersion.Group && ref.Kind == "Sequence":
+		t.receive(key, et)
+		consumers, err = t.traceSequence(ctx, key, et, path)
+	case ref.Group == flowsv1.SchemeGroupVersion.Group && ref.Kind == "Parallel":
+		t.receive(key, et)
+		consumers, err = t.traceParallel(ctx, key, et, path)
+	default:
+		t.mapKind(sub.gvk())
+		consumers, err = t.traceChannel(ctx, key, et, path)
+	}
+	return withVia(consumers, meshResource{gk: ref.GroupKind(), namespace: ref.Namespace}), err
+}
+
+// consumer returns the subscriber as the consumer, if it's in Backstage.
+func (t *consumerTracer) consumer(ctx context.Context, sub *subscriber) ([]consumer, error) {
+	backstageId, err := t.backstageID(ctx, sub)
+	if err != nil {
+		return nil, fmt.Errorf("error getting subscriber backstage id: %w", err)
+	}
+	// we only care about subscribers that are in Backstage
+	if backstageId == "" {
+		return nil, nil
+	}
+
+	c := consumer{backstageId: backstageId}
+	// the subscribers that are only known by their URIs are mapped to the Backstage IDs by the configuration, there's
+	// no resource to see
+	if sub.ref != nil {
+		t.mapKind(sub.gvk())
+		c.via = []meshResource{{gk: sub.gvk().GroupKind(), namespace: sub.ref.Namespace}}
+	}
+	return []consumer{c}, nil
+}
+
+// traceBroker returns the consumers behind the triggers of the broker whose filters the event type can pass.
+func (t *consumerTracer) traceBroker(ctx context.Context, key string, et *EventType, path map[string]bool) ([]consumer, error) {
+	var consumers []consumer
+	for _, trigger := range t.triggersByBroker[key] {
+		result := evaluateTriggerFilters(trigger, staticAttributes(et, t.eventTypeAttributes[et.NamespacedName()]))
+		if result == filterNoMatch {
+			continue
+		}
+
+		triggerConsumers, err := t.trace(ctx, trigger.Spec.Subscriber, urlString(trigger.Status.SubscriberURI), trigger.Namespace, et, path)
+		if err != nil {
+			return nil, err
+		}
+		triggerConsumers = withVia(triggerConsumers, meshResource{gk: eventingv1.Kind("Trigger"), namespace: trigger.Namespace})
+		consumers = append(consumers, withIndeterminate(triggerConsumers, result == filterIndeterminate)...)
+	}
+	return consumers, nil
+}
+
+// traceChannel returns the consumers behind the subscriptions of the channel.
+func (t *consumerTracer) traceChannel(ctx context.Context, key string, et *EventType, path map[string]bool) ([]consumer, error) {
+	var consumers []consumer
+	for _, subscription := range t.subscriptionsByChannel[key] {
+		if subscription.Spec.Subscriber == nil {
+			continue
+		}
+
+		subscriptionConsumers, err := t.trace(ctx, *subscription.Spec.Subscriber, urlString(subscription.Status.PhysicalSubscription.SubscriberURI), subscription.Namespace, et, path)
+		if err != nil {
+			return nil, err
+		}
+		consumers = append(consumers, withVia(subscriptionConsumers, meshResource{gk: v1.Kind("Subscription"), namespace: subscription.Namespace})...)
+	}
+	return consumers, nil
+}
+
+// traceSequence returns the consumers behind the steps of the sequence, and behind its reply.
+// Only the first step receives the events of the event type itself, the later steps receive the replies of the
+// previous steps. Their types aren't known until runtime, so they're assumed to be of the same event type, which is
+// how the pipelines built on sequences usually pass the events on. The event type flows through all the steps that
+// way, and on to the reply after the last step, the same as it's shown as provided to all the steps.
+func (t *consumerTracer) traceSequence(ctx context.Context, key string, et *EventType, path map[string]bool) ([]consumer, error) {
+	sequence, ok := t.sequences[key]
+	if !ok {
+		t.logger.Debugw("Sequence not found", "sequence", key)
+		return nil, nil
+	}
+
+	var consumers []consumer
+	for _, step := range sequence.Spec.Steps {
+		stepConsumers, err := t.trace(ctx, step.Destination, "", sequence.Namespace, et, path)
+		if err != nil {
+			return nil, err
+		}
+		consumers = append(consumers, stepConsumers...)
+	}
+
+	if sequence.Spec.Reply != nil {
+		replyConsumers, err := t.trace(ctx, *sequence.Spec.Reply, "", sequence.Namespace, et, path)
+		if err != nil {
+			return nil, err
+		}
+		consumers = append(consumers, replyConsumers...)
+	}
+	return consumers, nil
+}
+
+// traceParallel returns the consumers behind the filters, the subscribers and the replies of the branches of the
+// parallel. The subscribers of the branches with a filter, and their replies, may or may not receive the events,
+// that's decided at runtime.
+func (t *consumerTracer) traceParallel(ctx context.Context, key string, et *EventType, path map[string]bool) ([]consumer, error) {
+	parallel, ok := t.parallels[key]
+	if !ok {
+		t.logger.Debugw("Parallel not found", "parallel", key)
+		return nil, nil
+	}
+
+	var consumers []consumer
+	for _, branch := range parallel.Spec.Branches {
+		if branch.Filter != nil {
+			filterConsumers, err := t.trace(ctx, *branch.Filter, "", parallel.Namespace, et, path)
+			if err != nil {
+				return nil, err
+			}
+			consumers = append(consumers, filterConsumers...)
+		}
+
+		subscriberConsumers, err := t.trace(ctx, branch.Subscriber, "", parallel.Namespace, et, path)
+		if err != nil {
+			return nil, err
+		}
+		consumers = append(consumers, withIndeterminate(subscriberConsumers, branch.Filter != nil)...)
+
+		// the replies of the subscriber go to the reply of the branch, or of the parallel if the branch has none
+		reply := branch.Reply
+		if reply == nil {
+			reply = parallel.Spec.Reply
+		}
+		if reply != nil {
+			replyConsumers, err := t.trace(ctx, *reply, "", parallel.Namespace, et, path)
+			if err != nil {
+				return nil, err
+			}
+			consumers = append(consumers, withIndeterminate(replyConsumers, branch.Filter != nil)...)
+		}
+	}
+	return consumers, nil
+}
+
+// receive registers that the event type is sent to the flow or the sink.
+func (t *consumerTracer) receive(key string, et *EventType) {
+	if t.received[key] == nil {
+		t.received[key] = make(map[string]bool)
+	}
+	if name := et.NamespacedName(); !t.received[key][name] {
+		t.received[key][name] = true
+		t.receivedEventTypes[key] = append(t.receivedEventTypes[key], name)
+	}
+}
+
+// withIndeterminate marks the consumers as indeterminate, if the way to them is.
+func withIndeterminate(consumers []consumer, indeterminate bool) []consumer {
+	if indeterminate {
+		for i := range consumers {
+			consumers[i].indeterminate = true
+		}
+	}
+	return consumers
+}
+
+// withVia adds the resource in front of the resources on the way to the consumers.
+func withVia(consumers []consumer, resource meshResource) []consumer {
+	for i := range consumers {
+		consumers[i].via = append([]meshResource{resource}, consumers[i].via...)
+	}
+	return consumers
+}
+
+// addConsumers registers the consumers that the events of the event type reach through the hop in the event type.
+// The events leave the broker or the channel of the event type through the given trigger or subscription.
+func (t *consumerTracer) addConsumers(et *EventType, through meshResource, hop *GroupKindNamespacedName, consumers []consumer, indeterminate bool) {
+	for _, c := range consumers {
+		c.indeterminate = c.indeterminate || indeterminate
+		t.paths.add(et, hop, c.backstageId, consumerPath{
+			via:           append([]meshResource{through}, c.via...),
+			indeterminate: c.indeterminate,
+		})
+
+		et.ConsumedBy = append(et.ConsumedBy, c.backstageId)
+		// mark the consumers that may or may not receive the event type, depending on the runtime attributes of the events
+		if c.indeterminate {
+			et.IndeterminateConsumedBy = append(et.IndeterminateConsumedBy, c.backstageId)
+		}
+
+		converted := EventTypeConsumer{
+			BackstageID:   c.backstageId,
+			Indeterminate: c.indeterminate,
+		}
+		if hop != nil {
+			h := *hop
+			converted.Hop = &h
+		}
+		et.Consumers = append(et.Consumers, converted)
+	}
+}
@@ -0,0 +1,150 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+)
+
+// DiscoveryModeEnvVar is the environment variable that sets the DiscoveryMode of the eventmesh backend.
+const DiscoveryModeEnvVar = "EVENTMESH_DISCOVERY_MODE"
+
+// DiscoveryMode is how the kinds of the sources, subscribables and sinks are discovered, both by the EventMeshCache
+// and when the event mesh is built with the caller's credentials.
+type DiscoveryMode string
+
+const (
+	// CRDDiscoveryMode discovers the kinds by listing the CRDs with the duck type labels.
+	// The caller needs to be able to list the CRDs cluster-wide. The cache watches the CRDs, so it picks up new kinds
+	// right away.
+	CRDDiscoveryMode DiscoveryMode = "crd"
+	// APIDiscoveryMode discovers the kinds with the discovery API, by their duck type categories.
+	// Any authenticated caller can use the discovery API, but the event types that the sources provide can't be
+	// known without their CRDs. The cache discovers the kinds again whenever it rebuilds the event mesh, at least
+	// once every resync period.
+	APIDiscoveryMode DiscoveryMode = "api"
+)
+
+// ParseDiscoveryMode parses the DiscoveryMode. The CRDDiscoveryMode is the default when the value is empty.
+func ParseDiscoveryMode(value string) (DiscoveryMode, error) {
+	switch mode := DiscoveryMode(strings.ToLower(strings.TrimSpace(value))); mode {
+	case "":
+		return CRDDiscoveryMode, nil
+	case CRDDiscoveryMode, APIDiscoveryMode:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown discovery mode %q, must be one of %q or %q", value, CRDDiscoveryMode, APIDiscoveryMode)
+	}
+}
+
+// apiResourceMatcher tells if the resources of the API discovery are the ones that the CRDs with some labels define.
+type apiResourceMatcher func(gv schema.GroupVersion, resource metav1.APIResource) bool
+
+// apiResourceMatchers are the matchers of the resources for the CRD labels that are used to discover the kinds.
+// map key: the label selector of the CRDs
+var apiResourceMatchers = map[string]apiResourceMatcher{
+	sourceCRDLabels.String():       hasCategory("sources"),
+	subscribableCRDLabels.String(): hasCategory("channel"),
+	// sinks don't have a category of their own, they're known by their groups and kinds. See isSinkCRD.
+	sinkCRDLabels.String(): func(gv schema.GroupVersion, resource metav1.APIResource) bool {
+		gk := gv.WithKind(resource.Kind).GroupKind()
+		return gk.Group == sinksGroup || gk == kafkaSinkGK
+	},
+}
+
+// hasCategory matches the resources that belong to the category.
+func hasCategory(category string) apiResourceMatcher {
+	return func(_ schema.GroupVersion, resource metav1.APIResource) bool {
+		return slices.Contains(resource.Categories, category)
+	}
+}
+
+// discoveryLister is a clientLister that discovers the kinds with the discovery API instead of listing the CRDs.
+// The CRDs it returns are made up from the discovered resources. They only have the group, the names and the
+// preferred version of the kinds.
+type discoveryLister struct {
+	*clientLister
+	discoveryClient discovery.DiscoveryInterface
+
+	// the preferred resources are discovered once per lister, the CRDs with different labels are picked from them.
+	discoverOnce sync.Once
+	resources    []*metav1.APIResourceList
+	discoverErr  error
+}
+
+var _ resourceLister = &discoveryLister{}
+
+func (l *discoveryLister) ListCRDs(_ context.Context, selector labels.Set) ([]*unstructured.Unstructured, error) {
+	matcher, ok := apiResourceMatchers[selector.String()]
+	if !ok {
+		return nil, fmt.Errorf("kinds with CRD labels %q can't be discovered with the discovery API", selector.String())
+	}
+
+	resourceLists, err := l.preferredResources()
+	if err != nil {
+		return nil, fmt.Errorf("error discovering the API resources: %w", err)
+	}
+
+	result := make([]*unstructured.Unstructured, 0)
+	for _, list := range resourceLists {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil {
+			continue
+		}
+		for _, resource := range list.APIResources {
+			// subresources, e.g. sources/status, are listed along with the resources
+			if strings.Contains(resource.Name, "/") || !slices.Contains(resource.Verbs, "list") {
+				continue
+			}
+			if matcher(gv, resource) {
+				result = append(result, discoveredCRD(gv, resource))
+			}
+		}
+	}
+	return result, nil
+}
+
+// preferredResources returns the resources of all groups, in the versions that the API server prefers.
+// The groups that can't be discovered, e.g. because their aggregated API server is down, are left out.
+func (l *discoveryLister) preferredResources() ([]*metav1.APIResourceList, error) {
+	l.discoverOnce.Do(func() {
+		l.resources, l.discoverErr = discovery.ServerPreferredResources(l.discoveryClient)
+		if discovery.IsGroupDiscoveryFailedError(l.discoverErr) {
+			l.discoverErr = nil
+		}
+	})
+	return l.resources, l.discoverErr
+}
+
+// discoveredCRD makes up the CRD that defines the discovered resource, with the discovered version as the only one.
+func discoveredCRD(gv schema.GroupVersion, resource metav1.APIResource) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": crdGVR.GroupVersion().String(),
+		"kind":       crdGK.Kind,
+		"metadata": map[string]interface{}{
+			"name": resource.Name + "." + gv.Group,
+		},
+		"spec": map[string]interface{}{
+			"group": gv.Group,
+			"names": map[string]interface{}{
+				"kind":   resource.Kind,
+				"plural": resource.Name,
+			},
+			"versions": []interface{}{
+				map[string]interface{}{
+					"name":    gv.Version,
+					"served":  true,
+					"storage": true,
+				},
+			},
+		},
+	}}
+}
@@ -0,0 +1,220 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	discoveryfake "k8s.io/client-go/discovery/fake"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	messagingv1 "knative.dev/eventing/pkg/apis/messaging/v1"
+	sourcesv1 "knative.dev/eventing/pkg/apis/sources/v1"
+	fakeclientset "knative.dev/eventing/pkg/client/clientset/versioned/fake"
+)
+
+func TestParseDiscoveryMode(t *testing.T) {
+	tests := []struct {
+		value string
+		want  DiscoveryMode
+		error bool
+	}{
+		{value: "", want: CRDDiscoveryMode},
+		{value: "crd", want: CRDDiscoveryMode},
+		{value: "api", want: APIDiscoveryMode},
+		{value: " API ", want: APIDiscoveryMode},
+		{value: "categories", error: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseDiscoveryMode(tt.value)
+			if (err != nil) != tt.error {
+				t.Errorf("ParseDiscoveryMode() error = %v, error %v", err, tt.error)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseDiscoveryMode() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildEventMeshWithAPIDiscovery(t *testing.T) {
+	discoveredResources := []*metav1.APIResourceList{
+		{
+			GroupVersion: "sources.knative.dev/v1",
+			APIResources: []metav1.APIResource{
+				{Name: "apiserversources", Kind: "ApiServerSource", Namespaced: true, Verbs: []string{"get", "list", "watch"}, Categories: []string{"all", "knative", "sources"}},
+				{Name: "apiserversources/status", Kind: "ApiServerSource", Namespaced: true, Verbs: []string{"get", "patch", "update"}, Categories: []string{"all", "knative", "sources"}},
+			},
+		},
+		{
+			GroupVersion: "messaging.knative.dev/v1",
+			APIResources: []metav1.APIResource{
+				{Name: "inmemorychannels", Kind: "InMemoryChannel", Namespaced: true, Verbs: []string{"get", "list", "watch"}, Categories: []string{"all", "knative", "messaging", "channel"}},
+				{Name: "subscriptions", Kind: "Subscription", Namespaced: true, Verbs: []string{"get", "list", "watch"}, Categories: []string{"all", "knative", "messaging"}},
+			},
+		},
+		{
+			GroupVersion: "sinks.knative.dev/v1alpha1",
+			APIResources: []metav1.APIResource{
+				{Name: "jobsinks", Kind: "JobSink", Namespaced: true, Verbs: []string{"get", "list", "watch"}, Categories: []string{"all", "knative", "eventing"}},
+			},
+		},
+		{
+			GroupVersion: "v1",
+			APIResources: []metav1.APIResource{
+				{Name: "services", Kind: "Service", Namespaced: true, Verbs: []string{"get", "list", "watch"}, Categories: []string{"all"}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		resources    []*metav1.APIResourceList
+		extraObjects []runtime.Object
+		// discoveryError is the error returned when discovering the API groups
+		discoveryError error
+		want           EventMesh
+	}{
+		{
+			name:      "Kinds are discovered by their categories without listing the CRDs",
+			resources: discoveredResources,
+			extraObjects: []runtime.Object{
+				&sourcesv1.ApiServerSource{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-src", Namespace: "test-ns"},
+				},
+				&messagingv1.InMemoryChannel{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-imc", Namespace: "test-ns"},
+				},
+				newSink("sinks.knative.dev/v1alpha1", "JobSink", "test-jobsink", nil, ""),
+			},
+			want: EventMesh{
+				DiscoveredKinds: []DiscoveredKind{
+					{Group: "messaging.knative.dev", Kind: "InMemoryChannel", Resource: "inmemorychannels", Version: "v1"},
+					{Group: "sinks.knative.dev", Kind: "JobSink", Resource: "jobsinks", Version: "v1alpha1"},
+					{Group: "sources.knative.dev", Kind: "ApiServerSource", Resource: "apiserversources", Version: "v1"},
+				},
+				Brokers:       []Broker{},
+				EventTypes:    []EventType{},
+				Triggers:      []Trigger{},
+				Subscriptions: []Subscription{},
+				Subscribables: []Subscribable{
+					{
+						Group:              "messaging.knative.dev",
+						Kind:               "InMemoryChannel",
+						Name:               "test-imc",
+						Namespace:          "test-ns",
+						ProvidedEventTypes: []string{},
+					},
+				},
+				Sequences: []Sequence{},
+				Parallels: []Parallel{},
+				Sinks: []Sink{
+					{
+						Group:              "sinks.knative.dev",
+						Kind:               "JobSink",
+						Name:               "test-jobsink",
+						Namespace:          "test-ns",
+						ConsumedEventTypes: []string{},
+					},
+				},
+				Sources: []Source{
+					{
+						Group:     "sources.knative.dev",
+						Kind:      "ApiServerSource",
+						Name:      "test-src",
+						Namespace: "test-ns",
+						// the event types of the sources are only declared in their CRDs
+						ProvidedEventTypeTypes: []string{},
+						ProvidedEventTypes:     []string{},
+					},
+				},
+			},
+		},
+		{
+			name:           "Kinds that can't be discovered are reported as warnings",
+			discoveryError: apierrors.NewInternalError(errors.New("boom")),
+			want: EventMesh{
+				Brokers:       []Broker{},
+				EventTypes:    []EventType{},
+				Triggers:      []Trigger{},
+				Subscriptions: []Subscription{},
+				Subscribables: make([]Subscribable, 0),
+				Sequences:     []Sequence{},
+				Parallels:     []Parallel{},
+				Sinks:         []Sink{},
+				Sources:       make([]Source, 0),
+				Warnings: []Warning{
+					{
+						Group:       "apiextensions.k8s.io",
+						Kind:        "CustomResourceDefinition",
+						Reason:      "error listing sink CRDs: error discovering the API resources: Internal error occurred: boom",
+						StatusClass: "5xx",
+					},
+					{
+						Group:       "apiextensions.k8s.io",
+						Kind:        "CustomResourceDefinition",
+						Reason:      "error listing source CRDs: error discovering the API resources: Internal error occurred: boom",
+						StatusClass: "5xx",
+					},
+					{
+						Group:       "apiextensions.k8s.io",
+						Kind:        "CustomResourceDefinition",
+						Reason:      "error listing subscribable CRDs: error discovering the API resources: Internal error occurred: boom",
+						StatusClass: "5xx",
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		logger := zap.NewNop().Sugar()
+
+		sc := runtime.NewScheme()
+		_ = messagingv1.AddToScheme(sc)
+		_ = sourcesv1.AddToScheme(sc)
+		_ = apiextensionsv1.AddToScheme(sc)
+
+		fakeDynamicClient := dynamicfake.NewSimpleDynamicClient(sc, tt.extraObjects...)
+		// the CRDs can't be listed, so they must not be needed
+		fakeDynamicClient.PrependReactor("list", "customresourcedefinitions", func(k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, apierrors.NewForbidden(crdGVR.GroupResource(), "", errors.New("not allowed"))
+		})
+
+		fakeDiscovery := &discoveryfake.FakeDiscovery{Fake: &k8stesting.Fake{Resources: tt.resources}}
+		if tt.discoveryError != nil {
+			fakeDiscovery.PrependReactor("get", "group", func(k8stesting.Action) (bool, runtime.Object, error) {
+				return true, nil, tt.discoveryError
+			})
+		}
+
+		lister := &discoveryLister{
+			clientLister: &clientLister{
+				clientset:     fakeclientset.NewSimpleClientset(),
+				dynamicClient: fakeDynamicClient,
+			},
+			discoveryClient: fakeDiscovery,
+		}
+
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildEventMesh(context.TODO(), lister, nil, nil, nil, logger)
+			if err != nil {
+				t.Fatalf("buildEventMesh() error = %v", err)
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Error("buildEventMesh() (-want, +got):", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package v1
+
+import (
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// convertDestination converts a destination of a resource in the given namespace.
+// References without a namespace point to the namespace of the resource.
+func convertDestination(destination duckv1.Destination, namespace string) Destination {
+	converted := Destination{
+		URI: urlString(destination.URI),
+	}
+	if destination.Ref != nil {
+		ref := convertReference(*destination.Ref, namespace)
+		converted.Ref = &ref
+	}
+	return converted
+}
+
+// convertReference converts a reference of a resource in the given namespace.
+// References without a namespace point to the namespace of the resource.
+func convertReference(ref duckv1.KReference, namespace string) GroupKindNamespacedName {
+	converted := GroupKindNamespacedName{
+		Group:     schema.FromAPIVersionAndKind(ref.APIVersion, ref.Kind).Group,
+		Kind:      ref.Kind,
+		Namespace: ref.Namespace,
+		Name:      ref.Name,
+	}
+	if converted.Namespace == "" {
+		converted.Namespace = namespace
+	}
+	return converted
+}
+
+// convertDelivery converts the delivery spec of a resource in the given namespace, along with the resolved URI of
+// its dead letter sink. It returns nil if there's no delivery spec.
+func convertDelivery(delivery *eventingduckv1.DeliverySpec, status eventingduckv1.DeliveryStatus, namespace string) *Delivery {
+	if delivery == nil {
+		return nil
+	}
+
+	converted := &Delivery{
+		Retry:             delivery.Retry,
+		DeadLetterSinkURI: urlString(status.DeadLetterSinkURI),
+	}
+	if delivery.DeadLetterSink != nil {
+		deadLetterSink := convertDestination(*delivery.DeadLetterSink, namespace)
+		converted.DeadLetterSink = &deadLetterSink
+	}
+	if delivery.BackoffPolicy != nil {
+		converted.BackoffPolicy = string(*delivery.BackoffPolicy)
+	}
+	if delivery.BackoffDelay != nil {
+		converted.BackoffDelay = *delivery.BackoffDelay
+	}
+	if delivery.Timeout != nil {
+		converted.Timeout = *delivery.Timeout
+	}
+	return converted
+}
+
+// convertConditions converts the status conditions of a resource.
+func convertConditions(conditions duckv1.Conditions) []Condition {
+	converted := make([]Condition, 0, len(conditions))
+	for _, c := range conditions {
+		condition := Condition{
+			Type:    string(c.Type),
+			Status:  string(c.Status),
+			Reason:  c.Reason,
+			Message: c.Message,
+		}
+		if !c.LastTransitionTime.Inner.IsZero() {
+			lastTransitionTime := c.LastTransitionTime.Inner.UTC()
+			condition.LastTransitionTime = &lastTransitionTime
+		}
+		converted = append(converted, condition)
+	}
+	return converted
+}
+
+// convertStatus converts the conditions and the address of a resource to the normalized status.
+// It returns nil if the resource doesn't report any status yet.
+func convertStatus(conditions duckv1.Conditions, address duckv1.AddressStatus) *ResourceStatus {
+	url := addressURL(address)
+	if len(conditions) == 0 && url == "" {
+		return nil
+	}
+
+	status := &ResourceStatus{
+		Ready:   string(corev1.ConditionUnknown),
+		Address: url,
+	}
+	for _, c := range conditions {
+		if c.Type != apis.ConditionReady {
+			continue
+		}
+		status.Ready = string(c.Status)
+		status.Reason = c.Reason
+		status.Message = c.Message
+		if !c.LastTransitionTime.Inner.IsZero() {
+			lastTransitionTime := c.LastTransitionTime.Inner.UTC()
+			status.LastTransitionTime = &lastTransitionTime
+		}
+	}
+	return status
+}
+
+// convertUnstructuredStatus converts the status of a resource of a duck type, e.g. a source or a channel, to the
+// normalized status. It returns nil if the resource doesn't report any status yet, or if it can't be parsed.
+func convertUnstructuredStatus(u *unstructured.Unstructured) *ResourceStatus {
+	statusMap, ok, err := unstructured.NestedMap(u.Object, "status")
+	if err != nil || !ok {
+		return nil
+	}
+
+	var status struct {
+		duckv1.Status        `json:",inline"`
+		duckv1.AddressStatus `json:",inline"`
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(statusMap, &status); err != nil {
+		return nil
+	}
+	return convertStatus(status.Conditions, status.AddressStatus)
+}
+
+// addressURL returns the URL of the address of a resource.
+func addressURL(address duckv1.AddressStatus) string {
+	if address.Address != nil && address.Address.URL != nil {
+		return address.Address.URL.String()
+	}
+	// clients must use the addresses when they're present, take the first one for the status
+	for _, a := range address.Addresses {
+		if a.URL != nil {
+			return a.URL.String()
+		}
+	}
+	return ""
+}
+
+func urlString(url *apis.URL) string {
+	if url == nil {
+		return ""
+	}
+	return url.String()
+}
+
+// convertObjectReference converts a reference to a resource that's created for another one, e.g. the channels of a
+// sequence, from its status. It returns nil if the resource is not created yet.
+func convertObjectReference(ref corev1.ObjectReference) *GroupKindNamespacedName {
+	if ref.Name == "" {
+		return nil
+	}
+	return &GroupKindNamespacedName{
+		Group:     schema.FromAPIVersionAndKind(ref.APIVersion, ref.Kind).Group,
+		Kind:      ref.Kind,
+		Namespace: ref.Namespace,
+		Name:      ref.Name,
+	}
+}
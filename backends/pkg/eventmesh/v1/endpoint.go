@@ -3,35 +3,62 @@ package v1
 import (
 	"context"
 	"fmt"
-	"log"
 
 	"knative.dev/backstage-plugins/backends/pkg/eventmesh/auth"
 
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 
+	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/rest"
 	"knative.dev/eventing/pkg/client/clientset/versioned"
+	"knative.dev/pkg/observability/semconv"
 )
 
+// defaultMaxReviewedNamespaces is how many namespaces the access is reviewed in when none are requested.
+const defaultMaxReviewedNamespaces = 50
+
 // Endpoint is the HTTP handler that's used to serve the event mesh data.
 type Endpoint struct {
 	inClusterConfig *rest.Config
-	logger          *zap.SugaredLogger
+	// cache and authorizer are optional. Without them, the event mesh is built on every request.
+	cache      *EventMeshCache
+	authorizer *auth.Authorizer
+	// backstageIDConfig is optional. Without it, the default Backstage ID resolution is used.
+	backstageIDConfig *BackstageIDConfigStore
+	// backstageIDs is optional. Without it, the Backstage IDs of the subscribers are resolved on every request.
+	backstageIDs *BackstageIDCache
+	// mapper is optional. Without it, the resources of the subscribers are guessed from their kinds.
+	mapper *ResourceMapper
+	// discoveryMode is how the kinds are discovered when the event mesh is built with the caller's token. The cache
+	// is set up with the same mode.
+	discoveryMode DiscoveryMode
+	// maxReviewedNamespaces is the most namespaces the access is reviewed in, 0 is unlimited
+	maxReviewedNamespaces int
+	logger                *zap.SugaredLogger
 }
 
 // ensure that Endpoint implements the StrictServerInterface
 var _ StrictServerInterface = &Endpoint{}
 
-func NewEndpoint(inClusterConfig *rest.Config, logger *zap.SugaredLogger) *Endpoint {
+func NewEndpoint(inClusterConfig *rest.Config, cache *EventMeshCache, authorizer *auth.Authorizer, backstageIDConfig *BackstageIDConfigStore, backstageIDs *BackstageIDCache, mapper *ResourceMapper, discoveryMode DiscoveryMode, logger *zap.SugaredLogger) *Endpoint {
 	return &Endpoint{
-		inClusterConfig: inClusterConfig,
-		logger:          logger,
+		inClusterConfig:       inClusterConfig,
+		cache:                 cache,
+		authorizer:            authorizer,
+		backstageIDConfig:     backstageIDConfig,
+		backstageIDs:          backstageIDs,
+		mapper:                mapper,
+		discoveryMode:         discoveryMode,
+		maxReviewedNamespaces: defaultMaxReviewedNamespaces,
+		logger:                logger,
 	}
 }
 
-func (e Endpoint) GetEventMesh(ctx context.Context, _ GetEventMeshRequestObject) (GetEventMeshResponseObject, error) {
-	logger := e.logger
+func (e Endpoint) GetEventMesh(ctx context.Context, request GetEventMeshRequestObject) (GetEventMeshResponseObject, error) {
+	ctx, span := startSpan(ctx, "GetEventMesh")
+	defer span.End()
 
 	authToken, ok := auth.GetAuthToken(ctx)
 	if !ok {
@@ -40,29 +67,181 @@ func (e Endpoint) GetEventMesh(ctx context.Context, _ GetEventMeshRequestObject)
 		}, nil
 	}
 
+	page, err := newEventMeshPage(request.Params.Kinds, request.Params.Limit, request.Params.Continue)
+	if err != nil {
+		return GetEventMesh400JSONResponse{
+			Error: err.Error(),
+		}, nil
+	}
+
+	response, notModified, err := e.respond(ctx, authToken, request.Params.Namespaces, page, request.Params.IfNoneMatch)
+	if err != nil {
+		return nil, err
+	}
+	if notModified {
+		return GetEventMesh304Response{Headers: GetEventMesh304ResponseHeaders{ETag: response.etag}}, nil
+	}
+	return response, nil
+}
+
+func (e Endpoint) GetNamespacedEventMesh(ctx context.Context, request GetNamespacedEventMeshRequestObject) (GetNamespacedEventMeshResponseObject, error) {
+	ctx, span := startSpan(ctx, "GetNamespacedEventMesh", semconv.K8SNamespaceName(request.Namespace))
+	defer span.End()
+
+	authToken, ok := auth.GetAuthToken(ctx)
+	if !ok {
+		return GetNamespacedEventMesh401JSONResponse{
+			Error: "Authorization header is missing",
+		}, nil
+	}
+
+	page, err := newEventMeshPage(request.Params.Kinds, request.Params.Limit, request.Params.Continue)
+	if err != nil {
+		return GetNamespacedEventMesh400JSONResponse{
+			Error: err.Error(),
+		}, nil
+	}
+
+	response, notModified, err := e.respond(ctx, authToken, []string{request.Namespace}, page, request.Params.IfNoneMatch)
+	if err != nil {
+		return nil, err
+	}
+	if notModified {
+		return GetNamespacedEventMesh304Response{Headers: GetNamespacedEventMesh304ResponseHeaders{ETag: response.etag}}, nil
+	}
+	return response, nil
+}
+
+// respond returns the page of the event mesh of the given namespaces, or of all namespaces if none are given, that's
+// visible to the owner of the token. It's served from the cache when possible, otherwise it's built with the token.
+// When the page matches the If-None-Match header, notModified is set and only the ETag of the response is set.
+func (e Endpoint) respond(ctx context.Context, authToken string, namespaces []string, page *eventMeshPage, ifNoneMatch *string) (response eventMeshResponse, notModified bool, err error) {
+	logger := e.logger
+
+	span := trace.SpanFromContext(ctx)
+	if snapshot := e.cachedSnapshot(); snapshot != nil {
+		// the ETag is the one of what the caller can see, so it changes when the caller's access does
+		eventMesh, err := e.filterSnapshot(ctx, authToken, snapshot, namespaces)
+		if err == nil {
+			span.SetAttributes(eventMeshCachedAttr.With(true))
+			response, err := newEventMeshResponse(page.apply(eventMesh))
+			if err != nil {
+				return eventMeshResponse{}, false, err
+			}
+			return response, ifNoneMatch != nil && etagMatches(*ifNoneMatch, response.etag), nil
+		}
+		logger.Errorw("Error reviewing access to the cached event mesh", "error", err)
+	}
+	span.SetAttributes(eventMeshCachedAttr.With(false))
+
+	eventMesh, err := e.buildWithToken(ctx, authToken, namespaces)
+	if err != nil {
+		return eventMeshResponse{}, false, err
+	}
+	response, err = newEventMeshResponse(page.apply(eventMesh))
+	if err != nil {
+		return eventMeshResponse{}, false, err
+	}
+	return response, ifNoneMatch != nil && etagMatches(*ifNoneMatch, response.etag), nil
+}
+
+// buildWithToken builds the event mesh of the given namespaces, or of all namespaces if none are given, with the
+// token.
+func (e Endpoint) buildWithToken(ctx context.Context, authToken string, namespaces []string) (EventMesh, error) {
+	logger := e.logger
+
+	span := trace.SpanFromContext(ctx)
+
 	config := rest.CopyConfig(e.inClusterConfig)
 	config.BearerToken = authToken
 
 	clientset, err := versioned.NewForConfig(config)
 	if err != nil {
-		log.Fatalf("Error creating clientset: %v", err)
+		return EventMesh{}, fmt.Errorf("error creating clientset: %w", err)
 	}
 
 	dynamicClient, err := dynamic.NewForConfig(config)
 	if err != nil {
-		log.Fatalf("Error creating dynamic client: %v", err)
+		return EventMesh{}, fmt.Errorf("error creating dynamic client: %w", err)
 	}
 
-	eventMesh, err := BuildEventMesh(ctx, clientset, dynamicClient, logger)
+	clients := &clientLister{
+		clientset:     clientset,
+		dynamicClient: dynamicClient,
+		namespaces:    namespaces,
+		mapper:        e.mapper,
+	}
+	var lister resourceLister = clients
+	if e.discoveryMode == APIDiscoveryMode {
+		discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+		if err != nil {
+			return EventMesh{}, fmt.Errorf("error creating discovery client: %w", err)
+		}
+		lister = &discoveryLister{clientLister: clients, discoveryClient: discoveryClient}
+	}
+
+	eventMesh, err := buildEventMesh(ctx, lister, e.backstageIDConfig.Load(), e.backstageIDs.scoped(authToken), nil, logger)
 	if err != nil {
 		logger.Errorw("Error building event mesh", "error", err)
-		return nil, fmt.Errorf("error building event mesh: %w", err)
+		recordSpanError(span, err)
+		return EventMesh{}, fmt.Errorf("error building event mesh: %w", err)
+	}
+
+	return eventMesh, nil
+}
+
+// cachedSnapshot returns the snapshot of the cache that the requests can be served from, or nil if there's none.
+func (e Endpoint) cachedSnapshot() *meshSnapshot {
+	logger := e.logger
+
+	if e.cache == nil || e.authorizer == nil {
+		return nil
 	}
 
-	return GetEventMesh200JSONResponse{
-		Brokers:       eventMesh.Brokers,
-		EventTypes:    eventMesh.EventTypes,
-		Subscribables: eventMesh.Subscribables,
-		Sources:       eventMesh.Sources,
-	}, nil
+	snapshot := e.cache.currentSnapshot()
+	if snapshot == nil {
+		logger.Debugw("Event mesh cache is not ready yet")
+		return nil
+	}
+	return snapshot
+}
+
+// filterSnapshot returns the part of the snapshot that the caller is allowed to see in the given namespaces, or in
+// the first maxReviewedNamespaces of the snapshot if none are given.
+func (e Endpoint) filterSnapshot(ctx context.Context, authToken string, snapshot *meshSnapshot, namespaces []string) (EventMesh, error) {
+	// only review the access to the requested namespaces
+	reviewedNamespaces := namespaces
+	limited := false
+	if len(reviewedNamespaces) == 0 {
+		reviewedNamespaces = snapshot.namespaces()
+		if e.maxReviewedNamespaces > 0 && len(reviewedNamespaces) > e.maxReviewedNamespaces {
+			reviewedNamespaces = reviewedNamespaces[:e.maxReviewedNamespaces]
+			limited = true
+		}
+	}
+
+	resources := snapshot.resources()
+	reviewed, err := e.authorizer.Access(ctx, authToken, resources, reviewedNamespaces)
+	if err != nil {
+		return EventMesh{}, err
+	}
+
+	var access listAccess = reviewed
+	if len(namespaces) > 0 {
+		access = newNamespacedAccess(access, namespaces)
+	}
+
+	eventMesh := filterEventMesh(snapshot.eventMesh, snapshot.kindResources, snapshot.consumers, access)
+	if limited {
+		for _, gr := range resources {
+			if !reviewed.CanListAll(gr) {
+				eventMesh.Warnings = append(eventMesh.Warnings, Warning{
+					Kind:   "Namespace",
+					Reason: fmt.Sprintf("the access is only reviewed in the first %d namespaces, ask for the namespaces to see the event mesh in the others", e.maxReviewedNamespaces),
+				})
+				break
+			}
+		}
+	}
+	return eventMesh, nil
 }
@@ -0,0 +1,111 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+
+	"knative.dev/backstage-plugins/backends/pkg/eventmesh/auth"
+)
+
+func TestFilterSnapshotReviewedNamespaces(t *testing.T) {
+	snapshot := &meshSnapshot{
+		eventMesh: EventMesh{
+			Brokers: []Broker{
+				{Namespace: "test-ns-1", Name: "test-broker", ProvidedEventTypes: []string{}},
+				{Namespace: "test-ns-2", Name: "test-broker", ProvidedEventTypes: []string{}},
+				{Namespace: "test-ns-3", Name: "test-broker", ProvidedEventTypes: []string{}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		clusterWide bool
+		namespaces  []string
+		wantBrokers []string
+		wantReviews int32
+		wantWarning bool
+	}{
+		{
+			name:        "Namespaces past the limit are not reviewed",
+			wantBrokers: []string{"test-ns-1", "test-ns-2"},
+			wantReviews: 2,
+			wantWarning: true,
+		},
+		{
+			name:        "Requested namespaces are not limited",
+			namespaces:  []string{"test-ns-1", "test-ns-2", "test-ns-3"},
+			wantBrokers: []string{"test-ns-1", "test-ns-2", "test-ns-3"},
+			wantReviews: 3,
+		},
+		{
+			name:        "Users who can list in all namespaces see all of them",
+			clusterWide: true,
+			wantBrokers: []string{"test-ns-1", "test-ns-2", "test-ns-3"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the API server allows listing everything, in all namespaces or in each of them, and counts the rules
+			// reviews
+			var reviews atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				if strings.HasSuffix(r.URL.Path, "/selfsubjectrulesreviews") {
+					reviews.Add(1)
+					_ = json.NewEncoder(w).Encode(&authorizationv1.SelfSubjectRulesReview{
+						TypeMeta: metav1.TypeMeta{APIVersion: "authorization.k8s.io/v1", Kind: "SelfSubjectRulesReview"},
+						Status: authorizationv1.SubjectRulesReviewStatus{
+							ResourceRules: []authorizationv1.ResourceRule{{Verbs: []string{"list"}, APIGroups: []string{"*"}, Resources: []string{"*"}}},
+						},
+					})
+					return
+				}
+				_ = json.NewEncoder(w).Encode(&authorizationv1.SelfSubjectAccessReview{
+					TypeMeta: metav1.TypeMeta{APIVersion: "authorization.k8s.io/v1", Kind: "SelfSubjectAccessReview"},
+					Status:   authorizationv1.SubjectAccessReviewStatus{Allowed: tt.clusterWide},
+				})
+			}))
+			defer server.Close()
+
+			config := &rest.Config{Host: server.URL}
+			e := Endpoint{
+				authorizer:            auth.NewAuthorizer(config, time.Minute),
+				maxReviewedNamespaces: 2,
+				logger:                zap.NewNop().Sugar(),
+			}
+
+			got, err := e.filterSnapshot(context.Background(), "token", snapshot, tt.namespaces)
+			if err != nil {
+				t.Fatalf("filterSnapshot() error = %v", err)
+			}
+
+			var brokers []string
+			for _, br := range got.Brokers {
+				brokers = append(brokers, br.Namespace)
+			}
+			if diff := cmp.Diff(tt.wantBrokers, brokers); diff != "" {
+				t.Error("Namespaces of the brokers (-want, +got):", diff)
+			}
+			if got := reviews.Load(); got != tt.wantReviews {
+				t.Errorf("Rules reviews = %d, want %d", got, tt.wantReviews)
+			}
+			if gotWarning := len(got.Warnings) > 0; gotWarning != tt.wantWarning {
+				t.Errorf("Warnings = %v, want a warning: %v", got.Warnings, tt.wantWarning)
+			}
+		})
+	}
+}
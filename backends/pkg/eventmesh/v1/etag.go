@@ -0,0 +1,68 @@
+package v1
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// eventMeshResponse is the JSON of an event mesh along with its ETag.
+// The event mesh is serialized once to compute the ETag, and the same bytes are sent when the caller doesn't have
+// that event mesh yet.
+type eventMeshResponse struct {
+	etag string
+	body []byte
+}
+
+var _ GetEventMeshResponseObject = eventMeshResponse{}
+var _ GetNamespacedEventMeshResponseObject = eventMeshResponse{}
+
+// newEventMeshResponse serializes the event mesh and computes its ETag, which is the hash of the JSON.
+// The JSON of an event mesh is stable, the lists are in a fixed order and the keys of the maps are sorted.
+func newEventMeshResponse(eventMesh EventMesh) (eventMeshResponse, error) {
+	body, err := json.Marshal(eventMesh)
+	if err != nil {
+		return eventMeshResponse{}, fmt.Errorf("error serializing event mesh: %w", err)
+	}
+	sum := sha256.Sum256(body)
+	return eventMeshResponse{
+		etag: `"` + hex.EncodeToString(sum[:16]) + `"`,
+		// same as the JSON encoder, the response ends with a newline
+		body: append(body, '\n'),
+	}, nil
+}
+
+func (response eventMeshResponse) VisitGetEventMeshResponse(w http.ResponseWriter) error {
+	return response.write(w)
+}
+
+func (response eventMeshResponse) VisitGetNamespacedEventMeshResponse(w http.ResponseWriter) error {
+	return response.write(w)
+}
+
+func (response eventMeshResponse) write(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", response.etag)
+	w.WriteHeader(http.StatusOK)
+
+	_, err := w.Write(response.body)
+	return err
+}
+
+// etagMatches tells if the value of an If-None-Match header matches the ETag.
+// The header is either `*` or a comma separated list of ETags, which may be weak ones, e.g. W/"abc", as the proxies
+// in between can weaken the ETags when they compress the responses.
+func etagMatches(ifNoneMatch, etag string) bool {
+	if strings.TrimSpace(ifNoneMatch) == "*" {
+		return true
+	}
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(candidate), "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
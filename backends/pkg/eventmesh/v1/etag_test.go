@@ -0,0 +1,177 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+
+	"knative.dev/backstage-plugins/backends/pkg/eventmesh/auth"
+)
+
+func TestETagMatches(t *testing.T) {
+	tests := []struct {
+		name        string
+		ifNoneMatch string
+		want        bool
+	}{
+		{name: "Same ETag", ifNoneMatch: `"abc"`, want: true},
+		{name: "Weak ETag", ifNoneMatch: `W/"abc"`, want: true},
+		{name: "Any ETag", ifNoneMatch: `*`, want: true},
+		{name: "One of the ETags", ifNoneMatch: `"def", "abc"`, want: true},
+		{name: "Other ETag", ifNoneMatch: `"def"`, want: false},
+		{name: "Unquoted ETag", ifNoneMatch: `abc`, want: false},
+		{name: "Empty", ifNoneMatch: ``, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := etagMatches(tt.ifNoneMatch, `"abc"`); got != tt.want {
+				t.Errorf("etagMatches(%q) = %v, want %v", tt.ifNoneMatch, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEventMeshResponseETag(t *testing.T) {
+	eventMesh := func(eventTypeLabels map[string]string) EventMesh {
+		return EventMesh{
+			Brokers: []Broker{{Namespace: "test-ns", Name: "test-broker", ProvidedEventTypes: []string{"test-ns/test-et"}}},
+			EventTypes: []EventType{
+				{Namespace: "test-ns", Name: "test-et", Type: "test-type", Labels: eventTypeLabels, ConsumedBy: []string{}},
+			},
+		}
+	}
+
+	first, err := newEventMeshResponse(eventMesh(map[string]string{"a": "1", "b": "2", "c": "3"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	same, err := newEventMeshResponse(eventMesh(map[string]string{"c": "3", "b": "2", "a": "1"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	changed, err := newEventMeshResponse(eventMesh(map[string]string{"a": "1"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if first.etag != same.etag {
+		t.Errorf("ETags of the same event mesh differ: %s, %s", first.etag, same.etag)
+	}
+	if first.etag == changed.etag {
+		t.Errorf("ETags of different event meshes are the same: %s", first.etag)
+	}
+}
+
+func TestEndpointNotModified(t *testing.T) {
+	// the API server only answers the access reviews, without any rules for the namespaces, and counts all the
+	// requests
+	var requests atomic.Int32
+	var allowed atomic.Bool
+	allowed.Store(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/selfsubjectrulesreviews") {
+			_ = json.NewEncoder(w).Encode(&authorizationv1.SelfSubjectRulesReview{
+				TypeMeta: metav1.TypeMeta{APIVersion: "authorization.k8s.io/v1", Kind: "SelfSubjectRulesReview"},
+			})
+			return
+		}
+		if !strings.HasSuffix(r.URL.Path, "/selfsubjectaccessreviews") {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(&authorizationv1.SelfSubjectAccessReview{
+			TypeMeta: metav1.TypeMeta{APIVersion: "authorization.k8s.io/v1", Kind: "SelfSubjectAccessReview"},
+			Status:   authorizationv1.SubjectAccessReviewStatus{Allowed: allowed.Load()},
+		})
+	}))
+	defer server.Close()
+
+	c := NewEventMeshCache(nil, nil, nil, nil, nil, zap.NewNop().Sugar())
+	store := func(names ...string) {
+		eventMesh := EventMesh{}
+		for _, name := range names {
+			eventMesh.Brokers = append(eventMesh.Brokers, Broker{Namespace: "test-ns", Name: name, ProvidedEventTypes: []string{}})
+		}
+		c.store(eventMesh, map[schema.GroupKind]schema.GroupResource{
+			{Group: "eventing.knative.dev", Kind: "Broker"}: {Group: "eventing.knative.dev", Resource: "brokers"},
+		}, nil)
+	}
+	store("test-broker")
+
+	config := &rest.Config{Host: server.URL}
+	endpoint := func() Endpoint {
+		// a new authorizer every time, so that nothing is served from its cache
+		return Endpoint{
+			inClusterConfig: config,
+			cache:           c,
+			authorizer:      auth.NewAuthorizer(config, time.Minute),
+			logger:          zap.NewNop().Sugar(),
+		}
+	}
+	get := func(token string, params GetEventMeshParams) (GetEventMeshResponseObject, int32) {
+		t.Helper()
+		requests.Store(0)
+		response, err := endpoint().GetEventMesh(auth.WithAuthToken(context.Background(), token), GetEventMeshRequestObject{Params: params})
+		if err != nil {
+			t.Fatalf("GetEventMesh() error = %v", err)
+		}
+		return response, requests.Load()
+	}
+
+	response, _ := get("token", GetEventMeshParams{})
+	first, ok := response.(eventMeshResponse)
+	if !ok {
+		t.Fatalf("GetEventMesh() = %T, want the event mesh", response)
+	}
+
+	// the caller has the event mesh already, the access is still reviewed before it's told so
+	response, calls := get("token", GetEventMeshParams{IfNoneMatch: &first.etag})
+	if diff := cmp.Diff(GetEventMesh304Response{Headers: GetEventMesh304ResponseHeaders{ETag: first.etag}}, response); diff != "" {
+		t.Error("GetEventMesh() (-want, +got):", diff)
+	}
+	if calls == 0 {
+		t.Error("API requests = 0, want the access to the cached event mesh to be reviewed")
+	}
+
+	tests := []struct {
+		name   string
+		params GetEventMeshParams
+		change func()
+	}{
+		{name: "other kinds", params: GetEventMeshParams{Kinds: []EventMeshKind{MeshKindEventTypes}}},
+		{name: "a limit", params: GetEventMeshParams{Limit: 1}, change: func() { store("test-broker", "test-broker-2") }},
+		{name: "a new event mesh", change: func() { store("test-broker-2") }},
+		{name: "revoked access", change: func() { store("test-broker"); allowed.Store(false) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.change != nil {
+				tt.change()
+			}
+			tt.params.IfNoneMatch = &first.etag
+			response, _ := get("token", tt.params)
+			got, ok := response.(eventMeshResponse)
+			if !ok {
+				t.Fatalf("GetEventMesh() = %T, want the event mesh", response)
+			}
+			if got.etag == first.etag {
+				t.Errorf("ETag = %s, want it to differ", got.etag)
+			}
+		})
+	}
+}
@@ -1,8 +1,12 @@
 package v1
 
 import (
+	"strings"
+
 	"knative.dev/backstage-plugins/backends/pkg/util"
 	"knative.dev/eventing/pkg/apis/eventing/v1beta2"
+	"knative.dev/eventing/pkg/apis/eventing/v1beta3"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
 )
 
 // NamespacedName returns the name and namespace of the event type in the format "<namespace>/<name>"
@@ -31,6 +35,7 @@ func convertEventType(et *v1beta2.EventType) EventType {
 		Name:        et.Name,
 		Namespace:   et.Namespace,
 		Type:        et.Spec.Type,
+		Source:      util.ToStrPtrOrNil(et.Spec.Source.String()),
 		Uid:         string(et.UID),
 		Description: util.ToStrPtrOrNil(et.Spec.Description),
 		SchemaData:  util.ToStrPtrOrNil(et.Spec.SchemaData),
@@ -40,5 +45,20 @@ func convertEventType(et *v1beta2.EventType) EventType {
 		Reference:   reference,
 		// this field will be populated later on, when we have process the triggers
 		ConsumedBy: make([]string, 0),
+		Status:     convertStatus(et.Status.Conditions, duckv1.AddressStatus{}),
+	}
+}
+
+// declaredAttributes returns the CloudEvents attributes, e.g. the extensions, that the event type declares with a
+// fixed value. The optional attributes and the ones whose value is a template are only known at runtime.
+// map key: CloudEvents attribute name
+func declaredAttributes(et *v1beta3.EventType) map[string]string {
+	attributes := make(map[string]string)
+	for _, attr := range et.Spec.Attributes {
+		if !attr.Required || attr.Value == "" || strings.ContainsAny(attr.Value, "{}") {
+			continue
+		}
+		attributes[attr.Name] = attr.Value
 	}
+	return attributes
 }
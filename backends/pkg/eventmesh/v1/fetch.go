@@ -0,0 +1,145 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"go.uber.org/zap"
+
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	eventingv1beta2 "knative.dev/eventing/pkg/apis/eventing/v1beta2"
+	eventingv1beta3 "knative.dev/eventing/pkg/apis/eventing/v1beta3"
+	flowsv1 "knative.dev/eventing/pkg/apis/flows/v1"
+	messagingv1 "knative.dev/eventing/pkg/apis/messaging/v1"
+
+	"knative.dev/backstage-plugins/backends/pkg/util"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// fetchConcurrency is the maximum number of calls to the API server that are made in parallel.
+const fetchConcurrency = 8
+
+// limitedLister is a resourceLister that limits the number of calls that are made in parallel.
+type limitedLister struct {
+	lister resourceLister
+	slots  chan struct{}
+}
+
+var _ resourceLister = &limitedLister{}
+
+func newLimitedLister(lister resourceLister, concurrency int) *limitedLister {
+	return &limitedLister{
+		lister: lister,
+		slots:  make(chan struct{}, concurrency),
+	}
+}
+
+// limitCall waits for a free slot, unless the request is gone in the meantime, and makes the call in it.
+func limitCall[T any](ctx context.Context, l *limitedLister, call func() (T, error)) (T, error) {
+	select {
+	case l.slots <- struct{}{}:
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+	defer func() { <-l.slots }()
+	return call()
+}
+
+func (l *limitedLister) ListBrokers(ctx context.Context) ([]*eventingv1.Broker, error) {
+	return limitCall(ctx, l, func() ([]*eventingv1.Broker, error) { return l.lister.ListBrokers(ctx) })
+}
+
+func (l *limitedLister) ListEventTypes(ctx context.Context) ([]*eventingv1beta2.EventType, error) {
+	return limitCall(ctx, l, func() ([]*eventingv1beta2.EventType, error) { return l.lister.ListEventTypes(ctx) })
+}
+
+func (l *limitedLister) ListEventTypeAttributes(ctx context.Context) ([]*eventingv1beta3.EventType, error) {
+	return limitCall(ctx, l, func() ([]*eventingv1beta3.EventType, error) { return l.lister.ListEventTypeAttributes(ctx) })
+}
+
+func (l *limitedLister) ListTriggers(ctx context.Context) ([]*eventingv1.Trigger, error) {
+	return limitCall(ctx, l, func() ([]*eventingv1.Trigger, error) { return l.lister.ListTriggers(ctx) })
+}
+
+func (l *limitedLister) ListSubscriptions(ctx context.Context) ([]*messagingv1.Subscription, error) {
+	return limitCall(ctx, l, func() ([]*messagingv1.Subscription, error) { return l.lister.ListSubscriptions(ctx) })
+}
+
+func (l *limitedLister) ListSequences(ctx context.Context) ([]*flowsv1.Sequence, error) {
+	return limitCall(ctx, l, func() ([]*flowsv1.Sequence, error) { return l.lister.ListSequences(ctx) })
+}
+
+func (l *limitedLister) ListParallels(ctx context.Context) ([]*flowsv1.Parallel, error) {
+	return limitCall(ctx, l, func() ([]*flowsv1.Parallel, error) { return l.lister.ListParallels(ctx) })
+}
+
+func (l *limitedLister) ListCRDs(ctx context.Context, selector labels.Set) ([]*unstructured.Unstructured, error) {
+	return limitCall(ctx, l, func() ([]*unstructured.Unstructured, error) { return l.lister.ListCRDs(ctx, selector) })
+}
+
+func (l *limitedLister) ListResources(ctx context.Context, gvr schema.GroupVersionResource) ([]*unstructured.Unstructured, error) {
+	return limitCall(ctx, l, func() ([]*unstructured.Unstructured, error) { return l.lister.ListResources(ctx, gvr) })
+}
+
+func (l *limitedLister) ListNamespacedResources(ctx context.Context, gvr schema.GroupVersionResource, namespace string) ([]*unstructured.Unstructured, error) {
+	return limitCall(ctx, l, func() ([]*unstructured.Unstructured, error) {
+		return l.lister.ListNamespacedResources(ctx, gvr, namespace)
+	})
+}
+
+func (l *limitedLister) GetResource(ctx context.Context, gvr schema.GroupVersionResource, namespace, name string) (*unstructured.Unstructured, error) {
+	return limitCall(ctx, l, func() (*unstructured.Unstructured, error) { return l.lister.GetResource(ctx, gvr, namespace, name) })
+}
+
+// ResourceFor isn't limited, the discovery is cached across the builds.
+func (l *limitedLister) ResourceFor(gvk schema.GroupVersionKind) (schema.GroupVersionResource, error) {
+	return l.lister.ResourceFor(gvk)
+}
+
+// crdResources are the resources of the kind that a CRD defines.
+type crdResources struct {
+	crd       *unstructured.Unstructured
+	gvr       schema.GroupVersionResource
+	resources []*unstructured.Unstructured
+}
+
+// listCRDResources lists the resources of the kinds that the CRDs define in parallel, in the order of the CRDs.
+// The CRDs whose resources can't be listed are reported in the warnings.
+func listCRDResources(ctx context.Context, lister resourceLister, crds []*unstructured.Unstructured, warnings *warnings, discovered *discoveredKinds, description string, logger *zap.SugaredLogger) []crdResources {
+	results := make([]*crdResources, len(crds))
+
+	var wg sync.WaitGroup
+	for i, crd := range crds {
+		gvr, err := util.GVRFromUnstructured(crd)
+		if err != nil {
+			logger.Errorw("Error getting GVR from CRD", "crd", crd.GetName(), "error", err)
+			warnings.add(crdGK, "", crd.GetName(), fmt.Errorf("error getting resource from CRD: %w", err))
+			continue
+		}
+		discovered.add(crd, gvr)
+
+		wg.Go(func() {
+			resources, err := lister.ListResources(ctx, gvr)
+			if err != nil {
+				logger.Errorw("Error listing "+description+" resources", "gvr", gvr, "error", err)
+				warnings.addKind(crdKind(crd), fmt.Errorf("error listing %s: %w", gvr.GroupResource(), err))
+				return
+			}
+			results[i] = &crdResources{crd: crd, gvr: gvr, resources: resources}
+		})
+	}
+	wg.Wait()
+
+	listed := make([]crdResources, 0, len(crds))
+	for _, result := range results {
+		if result != nil {
+			listed = append(listed, *result)
+		}
+	}
+	return listed
+}
@@ -0,0 +1,175 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	messagingv1 "knative.dev/eventing/pkg/apis/messaging/v1"
+	fakeclientset "knative.dev/eventing/pkg/client/clientset/versioned/fake"
+	testingv1 "knative.dev/eventing/pkg/reconciler/testing/v1"
+)
+
+// blockingLister is a resourceLister whose ListResources calls block until they're released.
+type blockingLister struct {
+	resourceLister
+
+	release chan struct{}
+	running atomic.Int32
+	max     atomic.Int32
+}
+
+func (l *blockingLister) ListResources(context.Context, schema.GroupVersionResource) ([]*unstructured.Unstructured, error) {
+	running := l.running.Add(1)
+	defer l.running.Add(-1)
+	for {
+		max := l.max.Load()
+		if running <= max || l.max.CompareAndSwap(max, running) {
+			break
+		}
+	}
+	<-l.release
+	return nil, nil
+}
+
+func TestLimitedLister(t *testing.T) {
+	const concurrency = 3
+
+	blocking := &blockingLister{release: make(chan struct{})}
+	lister := newLimitedLister(blocking, concurrency)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Go(func() {
+			_, _ = lister.ListResources(context.TODO(), schema.GroupVersionResource{})
+		})
+	}
+
+	// wait for the calls to fill the slots
+	deadline := time.Now().Add(5 * time.Second)
+	for blocking.running.Load() < concurrency && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	// the calls waiting for a slot give up when the request is gone
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+	if _, err := lister.ListResources(ctx, schema.GroupVersionResource{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("ListResources() with a cancelled context error = %v, want %v", err, context.Canceled)
+	}
+
+	close(blocking.release)
+	wg.Wait()
+
+	if got := blocking.max.Load(); got != concurrency {
+		t.Errorf("calls made in parallel = %d, want %d", got, concurrency)
+	}
+}
+
+func TestBuildEventMeshLooksUpSubscribersOnce(t *testing.T) {
+	trigger := func(name, subscriber string) runtime.Object {
+		return testingv1.NewTrigger(name, "test-ns", "test-broker",
+			WithTriggerSubscriber(reference("v1", "Service", "test-ns", subscriber)),
+		)
+	}
+
+	tests := []struct {
+		name      string
+		objects   []runtime.Object
+		forbidden bool
+		want      []string
+	}{
+		{
+			name: "The subscribers of a kind in a namespace are listed at once",
+			objects: []runtime.Object{
+				trigger("test-trigger-1", "test-subscriber"),
+				trigger("test-trigger-2", "test-subscriber"),
+				trigger("test-trigger-3", "test-subscriber"),
+				trigger("test-trigger-4", "test-other"),
+			},
+			want: []string{"list test-ns"},
+		},
+		{
+			name: "A lone subscriber is fetched on its own",
+			objects: []runtime.Object{
+				trigger("test-trigger-1", "test-subscriber"),
+				trigger("test-trigger-2", "test-subscriber"),
+			},
+			want: []string{"get test-ns/test-subscriber"},
+		},
+		{
+			name: "The subscribers that can't be listed are fetched one by one",
+			objects: []runtime.Object{
+				trigger("test-trigger-1", "test-subscriber"),
+				trigger("test-trigger-2", "test-subscriber"),
+				trigger("test-trigger-3", "test-other"),
+			},
+			forbidden: true,
+			want:      []string{"get test-ns/test-other", "get test-ns/test-subscriber", "list test-ns"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objects := append([]runtime.Object{testingv1.NewBroker("test-broker", "test-ns")}, tt.objects...)
+
+			sc := runtime.NewScheme()
+			_ = corev1.AddToScheme(sc)
+			_ = eventingv1.AddToScheme(sc)
+			_ = messagingv1.AddToScheme(sc)
+			_ = apiextensionsv1.AddToScheme(sc)
+
+			fakeDynamicClient := dynamicfake.NewSimpleDynamicClient(sc, backstageService("test-subscriber"), backstageService("test-other"))
+
+			var mu sync.Mutex
+			var calls []string
+			fakeDynamicClient.PrependReactor("*", "services", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				mu.Lock()
+				defer mu.Unlock()
+				switch a := action.(type) {
+				case k8stesting.GetAction:
+					calls = append(calls, "get "+a.GetNamespace()+"/"+a.GetName())
+				case k8stesting.ListAction:
+					calls = append(calls, "list "+a.GetNamespace())
+					if tt.forbidden {
+						return true, nil, apierrors.NewForbidden(corev1.Resource("services"), "", errors.New("test"))
+					}
+				}
+				// let the tracker serve the services
+				return false, nil, nil
+			})
+
+			eventMesh, err := BuildEventMesh(context.TODO(), fakeclientset.NewSimpleClientset(objects...), fakeDynamicClient, nil, nil, zap.NewNop().Sugar())
+			if err != nil {
+				t.Fatalf("BuildEventMesh() error = %v", err)
+			}
+
+			for _, trigger := range eventMesh.Triggers {
+				if trigger.BackstageID == "" {
+					t.Errorf("trigger %s/%s has no Backstage ID", trigger.Namespace, trigger.Name)
+				}
+			}
+			slices.Sort(calls)
+			if diff := cmp.Diff(tt.want, calls); diff != "" {
+				t.Error("calls for the subscribers (-want, +got):", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,294 @@
+package v1
+
+import (
+	"strings"
+
+	"github.com/antlr/antlr4/runtime/Go/antlr"
+	cesql "github.com/cloudevents/sdk-go/sql/v2"
+	"github.com/cloudevents/sdk-go/sql/v2/gen"
+	cesqlparser "github.com/cloudevents/sdk-go/sql/v2/parser"
+	"github.com/cloudevents/sdk-go/sql/v2/utils"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+)
+
+// filterResult is the outcome of evaluating a trigger filter against the static attributes of an EventType.
+// Filters are evaluated with three-valued logic, as some attributes (e.g. `id`, `subject` or extensions) are
+// only known when an actual event is received.
+type filterResult int
+
+const (
+	// filterNoMatch means no event of the EventType can pass the filter.
+	filterNoMatch filterResult = iota
+	// filterMatch means every event of the EventType passes the filter.
+	filterMatch
+	// filterIndeterminate means that the outcome depends on attributes that are only known at runtime.
+	filterIndeterminate
+)
+
+func (r filterResult) and(other filterResult) filterResult {
+	if r == filterNoMatch || other == filterNoMatch {
+		return filterNoMatch
+	}
+	if r == filterIndeterminate || other == filterIndeterminate {
+		return filterIndeterminate
+	}
+	return filterMatch
+}
+
+func (r filterResult) or(other filterResult) filterResult {
+	if r == filterMatch || other == filterMatch {
+		return filterMatch
+	}
+	if r == filterIndeterminate || other == filterIndeterminate {
+		return filterIndeterminate
+	}
+	return filterNoMatch
+}
+
+func (r filterResult) xor(other filterResult) filterResult {
+	if r == filterIndeterminate || other == filterIndeterminate {
+		return filterIndeterminate
+	}
+	return fromBool(r != other)
+}
+
+func (r filterResult) not() filterResult {
+	switch r {
+	case filterMatch:
+		return filterNoMatch
+	case filterNoMatch:
+		return filterMatch
+	default:
+		return filterIndeterminate
+	}
+}
+
+func fromBool(b bool) filterResult {
+	if b {
+		return filterMatch
+	}
+	return filterNoMatch
+}
+
+// staticAttributes returns the CloudEvents attributes that every event of the given EventType is known to carry.
+// The declared attributes are the ones that the EventType declares with a fixed value, see declaredAttributes.
+// map key: CloudEvents attribute name, e.g. "type"
+func staticAttributes(et *EventType, declared map[string]string) map[string]string {
+	attributes := make(map[string]string, len(declared)+3)
+	for name, value := range declared {
+		attributes[name] = value
+	}
+	attributes["type"] = et.Type
+	if et.Source != nil {
+		attributes["source"] = *et.Source
+	}
+	if et.SchemaURL != nil {
+		attributes["dataschema"] = *et.SchemaURL
+	}
+	return attributes
+}
+
+// evaluateTriggerFilters evaluates the filters of a trigger against the static attributes of an EventType.
+// A trigger without any filters receives all events.
+func evaluateTriggerFilters(trigger *eventingv1.Trigger, attributes map[string]string) filterResult {
+	// when `filters` are set, the `filter` field is ignored by the broker
+	if len(trigger.Spec.Filters) > 0 {
+		return evaluateSubscriptionsAPIFilters(trigger.Spec.Filters, attributes)
+	}
+
+	if trigger.Spec.Filter != nil {
+		return evaluateFilterAttributes(trigger.Spec.Filter.Attributes, attributes)
+	}
+
+	return filterMatch
+}
+
+// evaluateFilterAttributes evaluates the `spec.filter.attributes` of a trigger against the static attributes of an EventType.
+// Every attribute in the filter must match exactly, except the ones with an empty value, which accept any value.
+func evaluateFilterAttributes(filter eventingv1.TriggerFilterAttributes, attributes map[string]string) filterResult {
+	exact := make(map[string]string, len(filter))
+	for name, value := range filter {
+		if value == eventingv1.TriggerAnyFilter {
+			continue
+		}
+		exact[name] = value
+	}
+	return evaluateAttributeFilter(exact, attributes, equals)
+}
+
+// evaluateSubscriptionsAPIFilters evaluates the `spec.filters` of a trigger against the static attributes of an EventType.
+// All the filters in the list must pass for an event to be delivered, same as in the Knative Eventing broker.
+func evaluateSubscriptionsAPIFilters(filters []eventingv1.SubscriptionsAPIFilter, attributes map[string]string) filterResult {
+	result := filterMatch
+	for _, filter := range filters {
+		result = result.and(evaluateSubscriptionsAPIFilter(filter, attributes))
+	}
+	return result
+}
+
+// evaluateSubscriptionsAPIFilter evaluates a single filter expression of the CloudEvents Subscriptions API.
+// A filter expression normally has a single dialect set. If there are more, all of them need to pass.
+func evaluateSubscriptionsAPIFilter(filter eventingv1.SubscriptionsAPIFilter, attributes map[string]string) filterResult {
+	result := filterMatch
+
+	result = result.and(evaluateAttributeFilter(filter.Exact, attributes, equals))
+	result = result.and(evaluateAttributeFilter(filter.Prefix, attributes, strings.HasPrefix))
+	result = result.and(evaluateAttributeFilter(filter.Suffix, attributes, strings.HasSuffix))
+
+	if len(filter.All) > 0 {
+		result = result.and(evaluateSubscriptionsAPIFilters(filter.All, attributes))
+	}
+
+	if len(filter.Any) > 0 {
+		anyResult := filterNoMatch
+		for _, f := range filter.Any {
+			anyResult = anyResult.or(evaluateSubscriptionsAPIFilter(f, attributes))
+		}
+		result = result.and(anyResult)
+	}
+
+	if filter.Not != nil {
+		result = result.and(evaluateSubscriptionsAPIFilter(*filter.Not, attributes).not())
+	}
+
+	if filter.CESQL != "" {
+		result = result.and(evaluateCESQL(filter.CESQL, attributes))
+	}
+
+	return result
+}
+
+// evaluateAttributeFilter checks every attribute in the filter with the given matcher.
+// Attributes that are not known statically make the result indeterminate.
+func evaluateAttributeFilter(filter map[string]string, attributes map[string]string, matches func(value, expected string) bool) filterResult {
+	result := filterMatch
+	for name, expected := range filter {
+		value, ok := attributes[strings.ToLower(name)]
+		if !ok {
+			result = result.and(filterIndeterminate)
+			continue
+		}
+		result = result.and(fromBool(matches(value, expected)))
+	}
+	return result
+}
+
+func equals(value, expected string) bool {
+	return value == expected
+}
+
+// evaluateCESQL evaluates a CESQL expression against the static attributes of an EventType.
+// Logical operators are evaluated by us with three-valued logic, while the remaining expressions (comparisons,
+// LIKE, IN, functions etc.) are evaluated by the CloudEvents SDK, but only when all the attributes they reference
+// are known statically.
+func evaluateCESQL(expression string, attributes map[string]string) (result filterResult) {
+	// the SDK parser panics on some malformed expressions instead of returning an error.
+	// we can't tell anything about a filter we can't parse.
+	defer func() {
+		if r := recover(); r != nil {
+			result = filterIndeterminate
+		}
+	}()
+
+	if _, err := cesqlparser.Parse(expression); err != nil {
+		return filterIndeterminate
+	}
+
+	var input antlr.CharStream = antlr.NewInputStream(expression)
+	input = cesqlparser.NewCaseChangingStream(input, true)
+	lexer := gen.NewCESQLParserLexer(input)
+	parser := gen.NewCESQLParserParser(antlr.NewCommonTokenStream(lexer, antlr.TokenDefaultChannel))
+	parser.RemoveErrorListeners()
+
+	evaluator := cesqlEvaluator{
+		attributes: attributes,
+		event:      staticEvent(attributes),
+	}
+	return evaluator.evaluate(parser.Cesql().(*gen.CesqlContext).Expression())
+}
+
+// staticEvent builds a CloudEvent that carries only the given attributes.
+func staticEvent(attributes map[string]string) cloudevents.Event {
+	event := cloudevents.NewEvent()
+	for name, value := range attributes {
+		switch name {
+		case "type":
+			event.SetType(value)
+		case "source":
+			event.SetSource(value)
+		case "dataschema":
+			event.SetDataSchema(value)
+		default:
+			event.SetExtension(name, value)
+		}
+	}
+	return event
+}
+
+type cesqlEvaluator struct {
+	attributes map[string]string
+	event      cloudevents.Event
+}
+
+func (e cesqlEvaluator) evaluate(tree antlr.ParseTree) filterResult {
+	switch ctx := tree.(type) {
+	case *gen.SubExpressionContext:
+		return e.evaluate(ctx.Expression())
+	case *gen.UnaryLogicExpressionContext:
+		return e.evaluate(ctx.Expression()).not()
+	case *gen.BinaryLogicExpressionContext:
+		left := e.evaluate(ctx.Expression(0))
+		right := e.evaluate(ctx.Expression(1))
+		switch {
+		case ctx.AND() != nil:
+			return left.and(right)
+		case ctx.OR() != nil:
+			return left.or(right)
+		default:
+			return left.xor(right)
+		}
+	default:
+		return e.evaluatePredicate(tree)
+	}
+}
+
+// evaluatePredicate evaluates a non-logical expression with the CloudEvents SDK.
+func (e cesqlEvaluator) evaluatePredicate(tree antlr.ParseTree) filterResult {
+	if !e.knowsAllIdentifiers(tree) {
+		return filterIndeterminate
+	}
+
+	expression, ok := cesqlparser.NewExpressionVisitor().Visit(tree).(cesql.Expression)
+	if !ok {
+		return filterIndeterminate
+	}
+
+	value, err := expression.Evaluate(e.event)
+	if err != nil {
+		// the broker would fail to evaluate the expression as well and drop the event
+		return filterNoMatch
+	}
+
+	value, err = utils.Cast(value, cesql.BooleanType)
+	if err != nil {
+		return filterNoMatch
+	}
+	return fromBool(value.(bool))
+}
+
+// knowsAllIdentifiers checks whether all the attributes referenced in the expression are known statically.
+func (e cesqlEvaluator) knowsAllIdentifiers(tree antlr.Tree) bool {
+	if identifier, ok := tree.(*gen.IdentifierContext); ok {
+		_, known := e.attributes[strings.ToLower(identifier.GetText())]
+		return known
+	}
+
+	for _, child := range tree.GetChildren() {
+		if !e.knowsAllIdentifiers(child) {
+			return false
+		}
+	}
+	return true
+}
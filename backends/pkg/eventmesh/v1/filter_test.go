@@ -0,0 +1,263 @@
+package v1
+
+import (
+	"testing"
+
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+)
+
+func TestEvaluateFilterAttributes(t *testing.T) {
+	attributes := map[string]string{
+		"type":       "dev.knative.foo.created",
+		"source":     "https://foo.example.com/bar",
+		"dataschema": "https://foo.example.com/schema",
+	}
+
+	tests := []struct {
+		name   string
+		filter eventingv1.TriggerFilterAttributes
+		want   filterResult
+	}{
+		{
+			name:   "no attributes",
+			filter: nil,
+			want:   filterMatch,
+		},
+		{
+			name:   "any type",
+			filter: eventingv1.TriggerFilterAttributes{"type": ""},
+			want:   filterMatch,
+		},
+		{
+			name:   "type and source match",
+			filter: eventingv1.TriggerFilterAttributes{"type": "dev.knative.foo.created", "source": "https://foo.example.com/bar"},
+			want:   filterMatch,
+		},
+		{
+			name:   "type matches, source does not",
+			filter: eventingv1.TriggerFilterAttributes{"type": "dev.knative.foo.created", "source": "https://bar.example.com"},
+			want:   filterNoMatch,
+		},
+		{
+			name:   "dataschema mismatch",
+			filter: eventingv1.TriggerFilterAttributes{"dataschema": "https://foo.example.com/other-schema"},
+			want:   filterNoMatch,
+		},
+		{
+			name:   "extension",
+			filter: eventingv1.TriggerFilterAttributes{"type": "dev.knative.foo.created", "myextension": "foo"},
+			want:   filterIndeterminate,
+		},
+		{
+			name:   "extension with any value",
+			filter: eventingv1.TriggerFilterAttributes{"myextension": ""},
+			want:   filterMatch,
+		},
+		{
+			name:   "extension and a mismatch",
+			filter: eventingv1.TriggerFilterAttributes{"source": "https://bar.example.com", "myextension": "foo"},
+			want:   filterNoMatch,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := evaluateFilterAttributes(tt.filter, attributes); got != tt.want {
+				t.Errorf("evaluateFilterAttributes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluateSubscriptionsAPIFilters(t *testing.T) {
+	attributes := map[string]string{
+		"type":   "dev.knative.foo.created",
+		"source": "https://foo.example.com/bar",
+	}
+
+	tests := []struct {
+		name    string
+		filters []eventingv1.SubscriptionsAPIFilter
+		want    filterResult
+	}{
+		{
+			name:    "no filters",
+			filters: nil,
+			want:    filterMatch,
+		},
+		{
+			name:    "exact match on type",
+			filters: []eventingv1.SubscriptionsAPIFilter{{Exact: map[string]string{"type": "dev.knative.foo.created"}}},
+			want:    filterMatch,
+		},
+		{
+			name:    "exact mismatch on type",
+			filters: []eventingv1.SubscriptionsAPIFilter{{Exact: map[string]string{"type": "dev.knative.foo.deleted"}}},
+			want:    filterNoMatch,
+		},
+		{
+			name:    "prefix match on type",
+			filters: []eventingv1.SubscriptionsAPIFilter{{Prefix: map[string]string{"type": "dev.knative.foo."}}},
+			want:    filterMatch,
+		},
+		{
+			name:    "suffix mismatch on source",
+			filters: []eventingv1.SubscriptionsAPIFilter{{Suffix: map[string]string{"source": "/baz"}}},
+			want:    filterNoMatch,
+		},
+		{
+			name:    "exact on runtime attribute",
+			filters: []eventingv1.SubscriptionsAPIFilter{{Exact: map[string]string{"subject": "foo"}}},
+			want:    filterIndeterminate,
+		},
+		{
+			name: "mismatch wins over runtime attribute",
+			filters: []eventingv1.SubscriptionsAPIFilter{
+				{Exact: map[string]string{"subject": "foo"}},
+				{Exact: map[string]string{"type": "dev.knative.foo.deleted"}},
+			},
+			want: filterNoMatch,
+		},
+		{
+			name: "any with one match",
+			filters: []eventingv1.SubscriptionsAPIFilter{{Any: []eventingv1.SubscriptionsAPIFilter{
+				{Exact: map[string]string{"type": "dev.knative.foo.deleted"}},
+				{Prefix: map[string]string{"type": "dev.knative.foo"}},
+			}}},
+			want: filterMatch,
+		},
+		{
+			name: "any with runtime attribute and no match",
+			filters: []eventingv1.SubscriptionsAPIFilter{{Any: []eventingv1.SubscriptionsAPIFilter{
+				{Exact: map[string]string{"type": "dev.knative.foo.deleted"}},
+				{Exact: map[string]string{"myextension": "foo"}},
+			}}},
+			want: filterIndeterminate,
+		},
+		{
+			name: "all with one mismatch",
+			filters: []eventingv1.SubscriptionsAPIFilter{{All: []eventingv1.SubscriptionsAPIFilter{
+				{Exact: map[string]string{"type": "dev.knative.foo.created"}},
+				{Prefix: map[string]string{"source": "https://bar"}},
+			}}},
+			want: filterNoMatch,
+		},
+		{
+			name:    "not of a match",
+			filters: []eventingv1.SubscriptionsAPIFilter{{Not: &eventingv1.SubscriptionsAPIFilter{Exact: map[string]string{"type": "dev.knative.foo.created"}}}},
+			want:    filterNoMatch,
+		},
+		{
+			name:    "not of a runtime attribute",
+			filters: []eventingv1.SubscriptionsAPIFilter{{Not: &eventingv1.SubscriptionsAPIFilter{Exact: map[string]string{"subject": "foo"}}}},
+			want:    filterIndeterminate,
+		},
+		{
+			name:    "cesql",
+			filters: []eventingv1.SubscriptionsAPIFilter{{CESQL: "type LIKE 'dev.knative.foo.%'"}},
+			want:    filterMatch,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := evaluateSubscriptionsAPIFilters(tt.filters, attributes); got != tt.want {
+				t.Errorf("evaluateSubscriptionsAPIFilters() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluateCESQL(t *testing.T) {
+	attributes := map[string]string{
+		"type":   "dev.knative.foo.created",
+		"source": "https://foo.example.com/bar",
+	}
+
+	tests := []struct {
+		name       string
+		expression string
+		want       filterResult
+	}{
+		{
+			name:       "equality match",
+			expression: "type = 'dev.knative.foo.created'",
+			want:       filterMatch,
+		},
+		{
+			name:       "equality mismatch",
+			expression: "type = 'dev.knative.foo.deleted'",
+			want:       filterNoMatch,
+		},
+		{
+			name:       "case insensitive keywords and identifiers",
+			expression: "TYPE in ('dev.knative.foo.created', 'dev.knative.foo.deleted')",
+			want:       filterMatch,
+		},
+		{
+			name:       "function invocation",
+			expression: "UPPER(source) LIKE 'HTTPS://FOO.%'",
+			want:       filterMatch,
+		},
+		{
+			name:       "runtime attribute",
+			expression: "subject = 'foo'",
+			want:       filterIndeterminate,
+		},
+		{
+			name:       "id is only known at runtime",
+			expression: "id = 'foo'",
+			want:       filterIndeterminate,
+		},
+		{
+			name:       "exists on runtime attribute",
+			expression: "EXISTS myextension",
+			want:       filterIndeterminate,
+		},
+		{
+			name:       "exists on static attribute",
+			expression: "EXISTS source",
+			want:       filterMatch,
+		},
+		{
+			name:       "and with mismatch and runtime attribute",
+			expression: "type = 'dev.knative.foo.deleted' AND subject = 'foo'",
+			want:       filterNoMatch,
+		},
+		{
+			name:       "and with match and runtime attribute",
+			expression: "type = 'dev.knative.foo.created' AND subject = 'foo'",
+			want:       filterIndeterminate,
+		},
+		{
+			name:       "or with match and runtime attribute",
+			expression: "(subject = 'foo') OR type = 'dev.knative.foo.created'",
+			want:       filterMatch,
+		},
+		{
+			name:       "not",
+			expression: "NOT (type = 'dev.knative.foo.created')",
+			want:       filterNoMatch,
+		},
+		{
+			name:       "xor",
+			expression: "type = 'dev.knative.foo.created' XOR source = 'https://foo.example.com/bar'",
+			want:       filterNoMatch,
+		},
+		{
+			name:       "evaluation error",
+			expression: "type + 1 = 2",
+			want:       filterNoMatch,
+		},
+		{
+			name:       "syntax error",
+			expression: "type = ",
+			want:       filterIndeterminate,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := evaluateCESQL(tt.expression, attributes); got != tt.want {
+				t.Errorf("evaluateCESQL(%q) = %v, want %v", tt.expression, got, tt.want)
+			}
+		})
+	}
+}
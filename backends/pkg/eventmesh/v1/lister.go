@@ -0,0 +1,290 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	eventingv1beta2 "knative.dev/eventing/pkg/apis/eventing/v1beta2"
+	eventingv1beta3 "knative.dev/eventing/pkg/apis/eventing/v1beta3"
+	flowsv1 "knative.dev/eventing/pkg/apis/flows/v1"
+	messagingv1 "knative.dev/eventing/pkg/apis/messaging/v1"
+	"knative.dev/eventing/pkg/client/clientset/versioned"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/pager"
+)
+
+var (
+	// crdGVR is the GroupVersionResource of the CustomResourceDefinitions.
+	// Sources, subscribables and sinks are discovered by listing the CRDs with the duck type labels, unless the
+	// discovery API is used instead. See discoveryLister.
+	crdGVR = schema.GroupVersionResource{
+		Group:    "apiextensions.k8s.io",
+		Version:  "v1",
+		Resource: "customresourcedefinitions",
+	}
+
+	// crdGK is the GroupKind of the CustomResourceDefinitions.
+	crdGK = schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}
+
+	sourceCRDLabels       = labels.Set{"duck.knative.dev/source": "true"}
+	subscribableCRDLabels = labels.Set{"messaging.knative.dev/subscribable": "true"}
+	// sinkCRDLabels select the addressable CRDs, of which only the ones that define sinks are used. See isSinkCRD.
+	sinkCRDLabels = labels.Set{"duck.knative.dev/addressable": "true"}
+)
+
+// listPageSize is the maximum number of resources that are fetched with a single LIST call.
+const listPageSize = 500
+
+// crdKind returns the kind of the resources that the CRD defines.
+func crdKind(crd *unstructured.Unstructured) schema.GroupKind {
+	group, _, _ := unstructured.NestedString(crd.Object, "spec", "group")
+	kind, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "kind")
+	return schema.GroupKind{Group: group, Kind: kind}
+}
+
+// discoveredKinds collects the kinds that are discovered from the CRDs, along with the versions they're listed with.
+type discoveredKinds struct {
+	mu    sync.Mutex
+	kinds []DiscoveredKind
+}
+
+// add registers the kind of the CRD and the resource that it's listed with.
+func (d *discoveredKinds) add(crd *unstructured.Unstructured, gvr schema.GroupVersionResource) {
+	gk := crdKind(crd)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.kinds = append(d.kinds, DiscoveredKind{
+		Group:    gk.Group,
+		Kind:     gk.Kind,
+		Resource: gvr.Resource,
+		Version:  gvr.Version,
+	})
+}
+
+// list returns the discovered kinds, sorted by their group and kind.
+func (d *discoveredKinds) list() []DiscoveredKind {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	sort.Slice(d.kinds, func(i, j int) bool {
+		if d.kinds[i].Group != d.kinds[j].Group {
+			return d.kinds[i].Group < d.kinds[j].Group
+		}
+		return d.kinds[i].Kind < d.kinds[j].Kind
+	})
+	return d.kinds
+}
+
+// resourceLister provides the Kubernetes resources that the event mesh is built from.
+// The resources are either fetched from the API server with the caller's credentials, or served from the
+// informer caches of the EventMeshCache.
+// Implementations must not return NotFound errors for resource types that don't exist in the cluster,
+// but an empty list instead.
+type resourceLister interface {
+	ListBrokers(ctx context.Context) ([]*eventingv1.Broker, error)
+	ListEventTypes(ctx context.Context) ([]*eventingv1beta2.EventType, error)
+	// ListEventTypeAttributes lists the event types in the version that declares their CloudEvents attributes.
+	ListEventTypeAttributes(ctx context.Context) ([]*eventingv1beta3.EventType, error)
+	ListTriggers(ctx context.Context) ([]*eventingv1.Trigger, error)
+	ListSubscriptions(ctx context.Context) ([]*messagingv1.Subscription, error)
+	ListSequences(ctx context.Context) ([]*flowsv1.Sequence, error)
+	ListParallels(ctx context.Context) ([]*flowsv1.Parallel, error)
+	// ListCRDs lists the CustomResourceDefinitions that have the given labels.
+	ListCRDs(ctx context.Context, selector labels.Set) ([]*unstructured.Unstructured, error)
+	// ListResources lists the resources of the given GVR in all namespaces.
+	ListResources(ctx context.Context, gvr schema.GroupVersionResource) ([]*unstructured.Unstructured, error)
+	// ListNamespacedResources lists the resources of the given GVR in a single namespace, e.g. the subscribers.
+	ListNamespacedResources(ctx context.Context, gvr schema.GroupVersionResource, namespace string) ([]*unstructured.Unstructured, error)
+	// GetResource fetches a single resource. It returns a NotFound error if the resource doesn't exist.
+	GetResource(ctx context.Context, gvr schema.GroupVersionResource, namespace, name string) (*unstructured.Unstructured, error)
+	// ResourceFor returns the resource of the kind, e.g. of a subscriber. It returns a NoKindMatch error if the cluster
+	// doesn't serve the kind.
+	ResourceFor(gvk schema.GroupVersionKind) (schema.GroupVersionResource, error)
+}
+
+// clientLister is a resourceLister that fetches the resources from the API server on every call.
+type clientLister struct {
+	clientset     versioned.Interface
+	dynamicClient dynamic.Interface
+	// namespaces restricts the lists to the given namespaces. When empty, the resources in all namespaces are listed.
+	namespaces []string
+	// mapper is optional. Without it, the resources are guessed from the kinds.
+	mapper *ResourceMapper
+}
+
+var _ resourceLister = &clientLister{}
+
+func (l *clientLister) ListBrokers(ctx context.Context) ([]*eventingv1.Broker, error) {
+	result := make([]*eventingv1.Broker, 0)
+	for _, ns := range l.listNamespaces() {
+		brokers, err := listPages[eventingv1.Broker](ctx, metav1.ListOptions{}, l.clientset.EventingV1().Brokers(ns).List)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, brokers...)
+	}
+	return result, nil
+}
+
+func (l *clientLister) ListEventTypes(ctx context.Context) ([]*eventingv1beta2.EventType, error) {
+	result := make([]*eventingv1beta2.EventType, 0)
+	for _, ns := range l.listNamespaces() {
+		eventTypes, err := listPages[eventingv1beta2.EventType](ctx, metav1.ListOptions{}, l.clientset.EventingV1beta2().EventTypes(ns).List)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, eventTypes...)
+	}
+	return result, nil
+}
+
+func (l *clientLister) ListEventTypeAttributes(ctx context.Context) ([]*eventingv1beta3.EventType, error) {
+	result := make([]*eventingv1beta3.EventType, 0)
+	for _, ns := range l.listNamespaces() {
+		eventTypes, err := listPages[eventingv1beta3.EventType](ctx, metav1.ListOptions{}, l.clientset.EventingV1beta3().EventTypes(ns).List)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, eventTypes...)
+	}
+	return result, nil
+}
+
+func (l *clientLister) ListTriggers(ctx context.Context) ([]*eventingv1.Trigger, error) {
+	result := make([]*eventingv1.Trigger, 0)
+	for _, ns := range l.listNamespaces() {
+		triggers, err := listPages[eventingv1.Trigger](ctx, metav1.ListOptions{}, l.clientset.EventingV1().Triggers(ns).List)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, triggers...)
+	}
+	return result, nil
+}
+
+func (l *clientLister) ListSubscriptions(ctx context.Context) ([]*messagingv1.Subscription, error) {
+	result := make([]*messagingv1.Subscription, 0)
+	for _, ns := range l.listNamespaces() {
+		subscriptions, err := listPages[messagingv1.Subscription](ctx, metav1.ListOptions{}, l.clientset.MessagingV1().Subscriptions(ns).List)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, subscriptions...)
+	}
+	return result, nil
+}
+
+func (l *clientLister) ListSequences(ctx context.Context) ([]*flowsv1.Sequence, error) {
+	result := make([]*flowsv1.Sequence, 0)
+	for _, ns := range l.listNamespaces() {
+		sequences, err := listPages[flowsv1.Sequence](ctx, metav1.ListOptions{}, l.clientset.FlowsV1().Sequences(ns).List)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, sequences...)
+	}
+	return result, nil
+}
+
+func (l *clientLister) ListParallels(ctx context.Context) ([]*flowsv1.Parallel, error) {
+	result := make([]*flowsv1.Parallel, 0)
+	for _, ns := range l.listNamespaces() {
+		parallels, err := listPages[flowsv1.Parallel](ctx, metav1.ListOptions{}, l.clientset.FlowsV1().Parallels(ns).List)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, parallels...)
+	}
+	return result, nil
+}
+
+func (l *clientLister) ListCRDs(ctx context.Context, selector labels.Set) ([]*unstructured.Unstructured, error) {
+	crds, err := listPages[unstructured.Unstructured](ctx, metav1.ListOptions{LabelSelector: selector.String()}, l.dynamicClient.Resource(crdGVR).List)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return crds, nil
+}
+
+func (l *clientLister) ListResources(ctx context.Context, gvr schema.GroupVersionResource) ([]*unstructured.Unstructured, error) {
+	result := make([]*unstructured.Unstructured, 0)
+	for _, ns := range l.listNamespaces() {
+		resources, err := listPages[unstructured.Unstructured](ctx, metav1.ListOptions{}, l.dynamicClient.Resource(gvr).Namespace(ns).List)
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, resources...)
+	}
+	return result, nil
+}
+
+func (l *clientLister) ListNamespacedResources(ctx context.Context, gvr schema.GroupVersionResource, namespace string) ([]*unstructured.Unstructured, error) {
+	resources, err := listPages[unstructured.Unstructured](ctx, metav1.ListOptions{}, l.dynamicClient.Resource(gvr).Namespace(namespace).List)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return resources, nil
+}
+
+func (l *clientLister) GetResource(ctx context.Context, gvr schema.GroupVersionResource, namespace, name string) (*unstructured.Unstructured, error) {
+	return l.dynamicClient.Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+func (l *clientLister) ResourceFor(gvk schema.GroupVersionKind) (schema.GroupVersionResource, error) {
+	return l.mapper.resourceFor(gvk)
+}
+
+// listNamespaces returns the namespaces to list the namespaced resources in.
+func (l *clientLister) listNamespaces() []string {
+	if len(l.namespaces) == 0 {
+		return []string{metav1.NamespaceAll}
+	}
+	return l.namespaces
+}
+
+// listPages lists the resources in pages of listPageSize, so that the API server doesn't have to send the large lists
+// at once. The list is fetched again in a single call if it changes too much in between for the pages to be consistent.
+func listPages[T any, L runtime.Object](ctx context.Context, opts metav1.ListOptions, list func(context.Context, metav1.ListOptions) (L, error)) ([]*T, error) {
+	p := pager.New(func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+		return list(ctx, opts)
+	})
+	p.PageSize = listPageSize
+
+	obj, _, err := p.List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*T, 0)
+	err = meta.EachListItem(obj, func(item runtime.Object) error {
+		typed, ok := any(item).(*T)
+		if !ok {
+			return fmt.Errorf("unexpected list item %T", item)
+		}
+		result = append(result, typed)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
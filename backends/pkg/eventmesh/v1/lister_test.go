@@ -0,0 +1,62 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestListPages(t *testing.T) {
+	const count = 2*listPageSize + 1
+	sinks := make([]unstructured.Unstructured, 0, count)
+	for i := 0; i < count; i++ {
+		sinks = append(sinks, *newSink("sinks.knative.dev/v1alpha1", "JobSink", fmt.Sprintf("test-jobsink-%04d", i), nil, ""))
+	}
+
+	// the fake clients don't page the lists, so the pages are served here
+	var calls []metav1.ListOptions
+	list := func(_ context.Context, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+		calls = append(calls, opts)
+
+		start := 0
+		if opts.Continue != "" {
+			start, _ = strconv.Atoi(opts.Continue)
+		}
+		end := min(start+int(opts.Limit), len(sinks))
+
+		page := &unstructured.UnstructuredList{Items: sinks[start:end]}
+		if end < len(sinks) {
+			page.SetContinue(strconv.Itoa(end))
+		}
+		return page, nil
+	}
+
+	got, err := listPages[unstructured.Unstructured](context.TODO(), metav1.ListOptions{LabelSelector: "test=true"}, list)
+	if err != nil {
+		t.Fatalf("listPages() error = %v", err)
+	}
+
+	if len(got) != count {
+		t.Fatalf("listPages() returned %d resources, want %d", len(got), count)
+	}
+	for i := range got {
+		if got[i].GetName() != sinks[i].GetName() {
+			t.Fatalf("listPages()[%d] = %s, want %s", i, got[i].GetName(), sinks[i].GetName())
+		}
+	}
+
+	want := []metav1.ListOptions{
+		{LabelSelector: "test=true", Limit: listPageSize},
+		{LabelSelector: "test=true", Limit: listPageSize, Continue: "500"},
+		{LabelSelector: "test=true", Limit: listPageSize, Continue: "1000"},
+	}
+	if diff := cmp.Diff(want, calls); diff != "" {
+		t.Error("LIST calls (-want, +got):", diff)
+	}
+}
@@ -0,0 +1,68 @@
+package v1
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/restmapper"
+)
+
+// defaultMapperResetInterval is how often the discovery is done again at most, when a kind isn't found.
+const defaultMapperResetInterval = 30 * time.Second
+
+// ResourceMapper maps the kinds of the subscribers and their owners to their resources with the discovery API.
+// The discovery is cached, and done again at most once per resetInterval when a kind isn't found.
+type ResourceMapper struct {
+	mapper        *restmapper.DeferredDiscoveryRESTMapper
+	resetInterval time.Duration
+	now           func() time.Time
+
+	lock sync.Mutex
+	// reset is when the discovery was invalidated the last time
+	reset time.Time
+}
+
+// NewResourceMapper creates a ResourceMapper that discovers the resources with the client.
+func NewResourceMapper(discoveryClient discovery.DiscoveryInterface) *ResourceMapper {
+	return &ResourceMapper{
+		mapper:        restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(discoveryClient)),
+		resetInterval: defaultMapperResetInterval,
+		now:           time.Now,
+	}
+}
+
+// resourceFor returns the resource of the kind. It returns a NoKindMatch error if the cluster doesn't serve the kind.
+// Without a mapper, the resource is guessed from the kind.
+func (m *ResourceMapper) resourceFor(gvk schema.GroupVersionKind) (schema.GroupVersionResource, error) {
+	if m == nil {
+		gvr, _ := meta.UnsafeGuessKindToResource(gvk)
+		return gvr, nil
+	}
+
+	mapping, err := m.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if meta.IsNoMatchError(err) && m.resetDue() {
+		m.mapper.Reset()
+		mapping, err = m.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	}
+	if err != nil {
+		return schema.GroupVersionResource{}, err
+	}
+	return mapping.Resource, nil
+}
+
+// resetDue returns whether the discovery can be done again, and marks it as done if so.
+func (m *ResourceMapper) resetDue() bool {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	now := m.now()
+	if now.Before(m.reset.Add(m.resetInterval)) {
+		return false
+	}
+	m.reset = now
+	return true
+}
@@ -0,0 +1,201 @@
+package v1
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	discoveryfake "k8s.io/client-go/discovery/fake"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	fakeclientset "knative.dev/eventing/pkg/client/clientset/versioned/fake"
+	testingv1 "knative.dev/eventing/pkg/reconciler/testing/v1"
+)
+
+var (
+	// octopusResources define a custom kind whose plural can't be guessed from the kind
+	octopusResources = &metav1.APIResourceList{
+		GroupVersion: "example.com/v1",
+		APIResources: []metav1.APIResource{
+			{Name: "octopi", Kind: "Octopus", Namespaced: true, Verbs: []string{"get", "list", "watch"}},
+		},
+	}
+	serviceResources = &metav1.APIResourceList{
+		GroupVersion: "v1",
+		APIResources: []metav1.APIResource{
+			{Name: "services", Kind: "Service", Namespaced: true, Verbs: []string{"get", "list", "watch"}},
+		},
+	}
+
+	octopiGVR = schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "octopi"}
+)
+
+func TestResourceMapper(t *testing.T) {
+	tests := []struct {
+		name      string
+		resources []*metav1.APIResourceList
+		// installed are the resources that are installed after the first mapping
+		installed []*metav1.APIResourceList
+		// elapsed is the time between the first mapping and the second one
+		elapsed time.Duration
+		gvk     schema.GroupVersionKind
+		want    schema.GroupVersionResource
+		noMatch bool
+	}{
+		{
+			name:      "A kind whose plural can't be guessed is mapped to its resource",
+			resources: []*metav1.APIResourceList{serviceResources, octopusResources},
+			gvk:       schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Octopus"},
+			want:      octopiGVR,
+		},
+		{
+			name:      "A core kind is mapped to its resource",
+			resources: []*metav1.APIResourceList{serviceResources, octopusResources},
+			gvk:       corev1.SchemeGroupVersion.WithKind("Service"),
+			want:      corev1.SchemeGroupVersion.WithResource("services"),
+		},
+		{
+			name:      "A kind that's not served is not mapped",
+			resources: []*metav1.APIResourceList{serviceResources},
+			gvk:       schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Octopus"},
+			noMatch:   true,
+		},
+		{
+			name:      "A kind that's installed later is mapped after the reset interval",
+			resources: []*metav1.APIResourceList{serviceResources},
+			installed: []*metav1.APIResourceList{serviceResources, octopusResources},
+			elapsed:   defaultMapperResetInterval,
+			gvk:       schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Octopus"},
+			want:      octopiGVR,
+		},
+		{
+			name:      "A kind that's installed later is not mapped within the reset interval",
+			resources: []*metav1.APIResourceList{serviceResources},
+			installed: []*metav1.APIResourceList{serviceResources, octopusResources},
+			elapsed:   defaultMapperResetInterval / 2,
+			gvk:       schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Octopus"},
+			noMatch:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeDiscovery := &discoveryfake.FakeDiscovery{Fake: &k8stesting.Fake{Resources: tt.resources}}
+
+			now := time.Now()
+			mapper := NewResourceMapper(fakeDiscovery)
+			mapper.now = func() time.Time { return now }
+
+			if tt.installed != nil {
+				// the discovery is done on the first mapping, before the kind is installed
+				if _, err := mapper.resourceFor(tt.gvk); !meta.IsNoMatchError(err) {
+					t.Fatalf("resourceFor() before the kind is installed error = %v, want a NoKindMatch error", err)
+				}
+				fakeDiscovery.Resources = tt.installed
+				now = now.Add(tt.elapsed)
+			}
+
+			got, err := mapper.resourceFor(tt.gvk)
+			if tt.noMatch {
+				if !meta.IsNoMatchError(err) {
+					t.Errorf("resourceFor() error = %v, want a NoKindMatch error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resourceFor() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("resourceFor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildEventMeshWithCustomSubscribers(t *testing.T) {
+	objects := []runtime.Object{
+		testingv1.NewBroker("test-broker", "test-ns"),
+		testingv1.NewTrigger("test-trigger-1", "test-ns", "test-broker",
+			WithTriggerSubscriber(reference("example.com/v1", "Octopus", "test-ns", "test-octopus-1")),
+		),
+		testingv1.NewTrigger("test-trigger-2", "test-ns", "test-broker",
+			WithTriggerSubscriber(reference("example.com/v1", "Octopus", "test-ns", "test-octopus-2")),
+		),
+		testingv1.NewTrigger("test-trigger-3", "test-ns", "test-broker",
+			WithTriggerSubscriber(reference("example.com/v1", "Squid", "test-ns", "test-squid")),
+		),
+		testingv1.NewTrigger("test-trigger-4", "test-ns", "test-broker",
+			WithTriggerSubscriber(reference("example.com/v1", "Squid", "test-ns", "test-other-squid")),
+		),
+	}
+
+	sc := runtime.NewScheme()
+	_ = corev1.AddToScheme(sc)
+	_ = eventingv1.AddToScheme(sc)
+	_ = apiextensionsv1.AddToScheme(sc)
+
+	fakeDynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(sc, map[schema.GroupVersionResource]string{
+		octopiGVR: "OctopusList",
+	})
+	// the tracker would guess the resource of the kind, so the octopi are added to their resource explicitly
+	for _, name := range []string{"test-octopus-1", "test-octopus-2"} {
+		octopus := &unstructured.Unstructured{}
+		octopus.SetAPIVersion("example.com/v1")
+		octopus.SetKind("Octopus")
+		octopus.SetNamespace("test-ns")
+		octopus.SetName(name)
+		octopus.SetLabels(map[string]string{BackstageKubernetesIDLabel: name})
+		if err := fakeDynamicClient.Tracker().Create(octopiGVR, octopus, "test-ns"); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	lister := &clientLister{
+		clientset:     fakeclientset.NewSimpleClientset(objects...),
+		dynamicClient: fakeDynamicClient,
+		mapper:        NewResourceMapper(&discoveryfake.FakeDiscovery{Fake: &k8stesting.Fake{Resources: []*metav1.APIResourceList{serviceResources, octopusResources}}}),
+	}
+
+	eventMesh, err := buildEventMesh(context.TODO(), lister, nil, nil, nil, zap.NewNop().Sugar())
+	if err != nil {
+		t.Fatalf("buildEventMesh() error = %v", err)
+	}
+
+	got := make(map[string]string, len(eventMesh.Triggers))
+	for _, trigger := range eventMesh.Triggers {
+		got[trigger.Name] = trigger.BackstageID
+	}
+	want := map[string]string{
+		"test-trigger-1": "test-octopus-1",
+		"test-trigger-2": "test-octopus-2",
+		"test-trigger-3": "",
+		"test-trigger-4": "",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Error("Backstage IDs of the triggers (-want, +got):", diff)
+	}
+
+	// the kind that's not served is reported once, not for every subscriber of that kind
+	wantWarnings := []Warning{
+		{
+			Group:  "example.com",
+			Kind:   "Squid",
+			Reason: `unknown kind of subscribers: no matches for kind "Squid" in version "example.com/v1"`,
+		},
+	}
+	if diff := cmp.Diff(wantWarnings, eventMesh.Warnings); diff != "" {
+		t.Error("Warnings (-want, +got):", diff)
+	}
+}
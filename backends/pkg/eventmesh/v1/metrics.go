@@ -0,0 +1,182 @@
+package v1
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	flowsv1 "knative.dev/eventing/pkg/apis/flows/v1"
+	"knative.dev/pkg/observability/attributekey"
+
+	"knative.dev/backstage-plugins/backends/pkg/util"
+)
+
+// scopeName is the instrumentation scope of the metrics of the event mesh backend.
+const scopeName = "knative.dev/backstage-plugins/backends/pkg/eventmesh/v1"
+
+const (
+	// backstageIDCacheBuild is the cache of the Backstage IDs that lives as long as a build of the event mesh.
+	backstageIDCacheBuild = "build"
+	// backstageIDCacheShared is the BackstageIDCache, which is shared by the builds.
+	backstageIDCacheShared = "shared"
+)
+
+// the phases of the build of the event mesh. The resources are fetched in parallel, so the durations of the fetch
+// phases overlap.
+const (
+	buildPhaseBrokers           = "brokers"
+	buildPhaseSubscribables     = "subscribables"
+	buildPhaseSources           = "sources"
+	buildPhaseSinks             = "sinks"
+	buildPhaseSequences         = "sequences"
+	buildPhaseParallels         = "parallels"
+	buildPhaseEventTypes        = "event_types"
+	buildPhaseTriggers          = "triggers"
+	buildPhaseSubscriptions     = "subscriptions"
+	buildPhaseSubscriberLookups = "subscriber_lookups"
+	buildPhaseConsumers         = "consumers"
+)
+
+var (
+	// backstageIDCacheAttr is the cache that a Backstage ID is looked up in.
+	backstageIDCacheAttr = attributekey.String("kn.eventmesh.backstage_id.cache")
+	// backstageIDCacheHitAttr is whether the Backstage ID was found in the cache.
+	backstageIDCacheHitAttr = attributekey.Bool("kn.eventmesh.backstage_id.cache.hit")
+	// buildPhaseAttr is the phase of the build of the event mesh, see the buildPhase constants.
+	buildPhaseAttr = attributekey.String("kn.eventmesh.build.phase")
+)
+
+// meshMetrics are the instruments of the metrics of the event mesh backend.
+type meshMetrics struct {
+	transportMetrics
+
+	backstageIDLookups metric.Int64Counter
+
+	buildPhaseDuration metric.Float64Histogram
+
+	meshBrokers            metric.Int64Gauge
+	meshEventTypes         metric.Int64Gauge
+	meshOrphanedEventTypes metric.Int64Gauge
+}
+
+// instruments are the meshMetrics that are recorded. They're created with the global meter provider, which is set up
+// by the observability of knative.dev/pkg, the metrics go nowhere until then.
+// The tests replace them with the ones of their own meter provider while the builds of other tests may still record
+// them, so they're only ever swapped as a whole.
+var instruments atomic.Pointer[meshMetrics]
+
+func init() {
+	instruments.Store(newMeshMetrics(otel.Meter(scopeName)))
+}
+
+// newMeshMetrics creates the instruments with the meter.
+func newMeshMetrics(meter metric.Meter) *meshMetrics {
+	m := &meshMetrics{transportMetrics: newTransportMetrics(meter)}
+
+	var err error
+	m.backstageIDLookups, err = meter.Int64Counter(
+		"kn.eventmesh.backstage_id.lookups",
+		metric.WithDescription("The number of lookups of the Backstage IDs of the subscribers in the caches."),
+		metric.WithUnit("{lookup}"),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	m.buildPhaseDuration, err = meter.Float64Histogram(
+		"kn.eventmesh.build.phase.duration",
+		metric.WithDescription("The duration of the phases of the builds of the event mesh."),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	m.meshBrokers, err = meter.Int64Gauge(
+		"kn.eventmesh.brokers",
+		metric.WithDescription("The number of brokers in the cached event mesh."),
+		metric.WithUnit("{broker}"),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	m.meshEventTypes, err = meter.Int64Gauge(
+		"kn.eventmesh.event_types",
+		metric.WithDescription("The number of event types in the cached event mesh."),
+		metric.WithUnit("{event_type}"),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	m.meshOrphanedEventTypes, err = meter.Int64Gauge(
+		"kn.eventmesh.event_types.orphaned",
+		metric.WithDescription("The number of event types in the cached event mesh whose reference is not in the event mesh."),
+		metric.WithUnit("{event_type}"),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	return m
+}
+
+// recordBackstageIDLookup records that the Backstage ID of a subscriber was looked up in the cache.
+func recordBackstageIDLookup(ctx context.Context, cache string, hit bool) {
+	instruments.Load().backstageIDLookups.Add(ctx, 1, metric.WithAttributes(backstageIDCacheAttr.With(cache), backstageIDCacheHitAttr.With(hit)))
+}
+
+// recordBuildPhase records the duration of the phase of the build of the event mesh that began at start.
+func recordBuildPhase(ctx context.Context, phase string, start time.Time) {
+	instruments.Load().buildPhaseDuration.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(buildPhaseAttr.With(phase)))
+}
+
+// timedBuildPhase returns a function that runs the phase of the build of the event mesh and records its duration.
+func timedBuildPhase(ctx context.Context, phase string, f func()) func() {
+	return func() {
+		defer recordBuildPhase(ctx, phase, time.Now())
+		f()
+	}
+}
+
+// recordEventMeshSize records the size of the event mesh. Only the cached event mesh is recorded, the event meshes
+// that are built for the callers are cut down to what they're allowed to see.
+func recordEventMeshSize(ctx context.Context, eventMesh EventMesh) {
+	m := instruments.Load()
+	m.meshBrokers.Record(ctx, int64(len(eventMesh.Brokers)))
+	m.meshEventTypes.Record(ctx, int64(len(eventMesh.EventTypes)))
+	m.meshOrphanedEventTypes.Record(ctx, int64(orphanedEventTypes(eventMesh)))
+}
+
+// orphanedEventTypes returns the number of the event types that reference a broker, channel, sequence or parallel
+// that's not in the event mesh, e.g. because it was deleted. These don't show up as provided by anything.
+// The event types without a reference are not orphaned, the sources can provide them.
+func orphanedEventTypes(eventMesh EventMesh) int {
+	references := make(map[string]bool)
+	for _, br := range eventMesh.Brokers {
+		references[util.GKNamespacedName(eventingv1.SchemeGroupVersion.Group, "Broker", br.Namespace, br.Name)] = true
+	}
+	for _, s := range eventMesh.Subscribables {
+		references[util.GKNamespacedName(s.Group, s.Kind, s.Namespace, s.Name)] = true
+	}
+	for _, s := range eventMesh.Sequences {
+		references[util.GKNamespacedName(flowsv1.SchemeGroupVersion.Group, "Sequence", s.Namespace, s.Name)] = true
+	}
+	for _, p := range eventMesh.Parallels {
+		references[util.GKNamespacedName(flowsv1.SchemeGroupVersion.Group, "Parallel", p.Namespace, p.Name)] = true
+	}
+
+	orphaned := 0
+	for _, et := range eventMesh.EventTypes {
+		if et.Reference != nil && !references[et.Reference.String()] {
+			orphaned++
+		}
+	}
+	return orphaned
+}
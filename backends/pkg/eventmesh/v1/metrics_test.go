@@ -0,0 +1,175 @@
+package v1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.uber.org/zap"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	fakeclientset "knative.dev/eventing/pkg/client/clientset/versioned/fake"
+	testingv1 "knative.dev/eventing/pkg/reconciler/testing/v1"
+)
+
+func TestBuildEventMeshRecordsBuildPhases(t *testing.T) {
+	reader := setupTestMeterProvider(t)
+
+	objects := []runtime.Object{
+		testingv1.NewBroker("test-broker", "test-ns"),
+		testingv1.NewTrigger("test-trigger", "test-ns", "test-broker",
+			WithTriggerSubscriber(reference("v1", "Service", "test-ns", "test-subscriber")),
+		),
+	}
+
+	sc := runtime.NewScheme()
+	_ = corev1.AddToScheme(sc)
+	_ = eventingv1.AddToScheme(sc)
+	_ = apiextensionsv1.AddToScheme(sc)
+
+	lister := &clientLister{
+		clientset:     fakeclientset.NewSimpleClientset(objects...),
+		dynamicClient: dynamicfake.NewSimpleDynamicClient(sc, backstageService("test-subscriber")),
+	}
+
+	if _, err := buildEventMesh(context.TODO(), lister, nil, nil, nil, zap.NewNop().Sugar()); err != nil {
+		t.Fatalf("buildEventMesh() error = %v", err)
+	}
+
+	got := make(map[string]uint64)
+	for _, dp := range collectMetric(t, reader, "kn.eventmesh.build.phase.duration").(metricdata.Histogram[float64]).DataPoints {
+		phase, _ := dp.Attributes.Value("kn.eventmesh.build.phase")
+		got[phase.Emit()] = dp.Count
+	}
+	want := map[string]uint64{
+		buildPhaseBrokers:           1,
+		buildPhaseSubscribables:     1,
+		buildPhaseSources:           1,
+		buildPhaseSinks:             1,
+		buildPhaseSequences:         1,
+		buildPhaseParallels:         1,
+		buildPhaseEventTypes:        1,
+		buildPhaseTriggers:          1,
+		buildPhaseSubscriptions:     1,
+		buildPhaseSubscriberLookups: 1,
+		buildPhaseConsumers:         1,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Error("Recorded build phases (-want, +got):", diff)
+	}
+}
+
+func TestRecordEventMeshSize(t *testing.T) {
+	brokerRef := &GroupKindNamespacedName{Group: "eventing.knative.dev", Kind: "Broker", Namespace: "test-ns", Name: "test-broker"}
+	channelRef := &GroupKindNamespacedName{Group: "messaging.knative.dev", Kind: "InMemoryChannel", Namespace: "test-ns", Name: "test-channel"}
+	sequenceRef := &GroupKindNamespacedName{Group: "flows.knative.dev", Kind: "Sequence", Namespace: "test-ns", Name: "test-sequence"}
+	deletedRef := &GroupKindNamespacedName{Group: "eventing.knative.dev", Kind: "Broker", Namespace: "test-ns", Name: "deleted-broker"}
+
+	tests := []struct {
+		name      string
+		eventMesh EventMesh
+		want      map[string]int64
+	}{
+		{
+			name:      "An empty event mesh",
+			eventMesh: EventMesh{},
+			want: map[string]int64{
+				"kn.eventmesh.brokers":              0,
+				"kn.eventmesh.event_types":          0,
+				"kn.eventmesh.event_types.orphaned": 0,
+			},
+		},
+		{
+			name: "The event types that reference the resources in the event mesh or nothing are not orphaned",
+			eventMesh: EventMesh{
+				Brokers:       []Broker{{Namespace: "test-ns", Name: "test-broker"}},
+				Subscribables: []Subscribable{{Group: "messaging.knative.dev", Kind: "InMemoryChannel", Namespace: "test-ns", Name: "test-channel"}},
+				Sequences:     []Sequence{{Namespace: "test-ns", Name: "test-sequence"}},
+				EventTypes: []EventType{
+					{Namespace: "test-ns", Name: "test-eventtype-1", Reference: brokerRef},
+					{Namespace: "test-ns", Name: "test-eventtype-2", Reference: channelRef},
+					{Namespace: "test-ns", Name: "test-eventtype-3", Reference: sequenceRef},
+					{Namespace: "test-ns", Name: "test-eventtype-4"},
+				},
+			},
+			want: map[string]int64{
+				"kn.eventmesh.brokers":              1,
+				"kn.eventmesh.event_types":          4,
+				"kn.eventmesh.event_types.orphaned": 0,
+			},
+		},
+		{
+			name: "The event types that reference the resources that are not in the event mesh are orphaned",
+			eventMesh: EventMesh{
+				Brokers: []Broker{{Namespace: "test-ns", Name: "test-broker"}},
+				EventTypes: []EventType{
+					{Namespace: "test-ns", Name: "test-eventtype-1", Reference: brokerRef},
+					{Namespace: "test-ns", Name: "test-eventtype-2", Reference: deletedRef},
+					{Namespace: "test-ns", Name: "test-eventtype-3", Reference: channelRef},
+				},
+			},
+			want: map[string]int64{
+				"kn.eventmesh.brokers":              1,
+				"kn.eventmesh.event_types":          3,
+				"kn.eventmesh.event_types.orphaned": 2,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := setupTestMeterProvider(t)
+
+			recordEventMeshSize(context.TODO(), tt.eventMesh)
+
+			got := make(map[string]int64, len(tt.want))
+			for name := range tt.want {
+				got[name] = collectMetric(t, reader, name).(metricdata.Gauge[int64]).DataPoints[0].Value
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Error("Event mesh size (-want, +got):", diff)
+			}
+		})
+	}
+}
+
+// setupTestMeterProvider sets up a meter provider whose metrics can be read by the test, and creates the instruments
+// with it. The previous instruments are restored when the test is done.
+func setupTestMeterProvider(t *testing.T) *sdkmetric.ManualReader {
+	t.Helper()
+
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	previous := instruments.Swap(newMeshMetrics(provider.Meter(scopeName)))
+	t.Cleanup(func() {
+		instruments.Store(previous)
+	})
+	return reader
+}
+
+// collectMetric returns the data of the metric with the name, failing the test if it wasn't recorded.
+func collectMetric(t *testing.T, reader sdkmetric.Reader, name string) metricdata.Aggregation {
+	t.Helper()
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.TODO(), &rm); err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name == name {
+				return m.Data
+			}
+		}
+	}
+	t.Fatalf("metric %s was not recorded", name)
+	return nil
+}
@@ -0,0 +1,181 @@
+package v1
+
+import (
+	"cmp"
+	"slices"
+
+	"knative.dev/backstage-plugins/backends/pkg/util"
+)
+
+// normalizeEventMesh sorts the lists of the event mesh and removes the duplicates from them, so that the same
+// resources always result in the same event mesh, regardless of the order the API server returns them in or the
+// number of ways the events reach a consumer.
+// The order of the steps of the sequences, the branches of the parallels, the filters of the triggers and the
+// conditions of the resources is meaningful, so those are kept as they are.
+func normalizeEventMesh(eventMesh *EventMesh) {
+	for i := range eventMesh.Brokers {
+		br := &eventMesh.Brokers[i]
+		br.ProvidedEventTypes = sortedUnique(br.ProvidedEventTypes)
+	}
+	eventMesh.Brokers = sortedUniqueFunc(eventMesh.Brokers, brokerSortKey)
+
+	for i := range eventMesh.EventTypes {
+		normalizeConsumers(&eventMesh.EventTypes[i])
+	}
+	eventMesh.EventTypes = sortedUniqueFunc(eventMesh.EventTypes, eventTypeSortKey)
+
+	for i := range eventMesh.Subscribables {
+		sb := &eventMesh.Subscribables[i]
+		sb.ProvidedEventTypes = sortedUnique(sb.ProvidedEventTypes)
+	}
+	eventMesh.Subscribables = sortedUniqueFunc(eventMesh.Subscribables, subscribableSortKey)
+
+	for i := range eventMesh.Sources {
+		src := &eventMesh.Sources[i]
+		src.ProvidedEventTypeTypes = sortedUnique(src.ProvidedEventTypeTypes)
+		src.ProvidedEventTypes = sortedUnique(src.ProvidedEventTypes)
+	}
+	eventMesh.Sources = sortedUniqueFunc(eventMesh.Sources, sourceSortKey)
+
+	for i := range eventMesh.Sinks {
+		sk := &eventMesh.Sinks[i]
+		sk.ConsumedEventTypes = sortedUnique(sk.ConsumedEventTypes)
+	}
+	eventMesh.Sinks = sortedUniqueFunc(eventMesh.Sinks, sinkSortKey)
+
+	for i := range eventMesh.Sequences {
+		sq := &eventMesh.Sequences[i]
+		sq.ProvidedEventTypes = sortedUnique(sq.ProvidedEventTypes)
+		for j := range sq.Steps {
+			sq.Steps[j].ProvidedEventTypes = sortedUnique(sq.Steps[j].ProvidedEventTypes)
+		}
+	}
+	eventMesh.Sequences = sortedUniqueFunc(eventMesh.Sequences, sequenceSortKey)
+
+	for i := range eventMesh.Parallels {
+		p := &eventMesh.Parallels[i]
+		p.ProvidedEventTypes = sortedUnique(p.ProvidedEventTypes)
+	}
+	eventMesh.Parallels = sortedUniqueFunc(eventMesh.Parallels, parallelSortKey)
+
+	eventMesh.Triggers = sortedUniqueFunc(eventMesh.Triggers, triggerSortKey)
+	eventMesh.Subscriptions = sortedUniqueFunc(eventMesh.Subscriptions, subscriptionSortKey)
+
+	slices.SortStableFunc(eventMesh.Warnings, func(a, b Warning) int {
+		return cmp.Or(
+			cmp.Compare(a.Group, b.Group),
+			cmp.Compare(a.Kind, b.Kind),
+			cmp.Compare(a.Namespace, b.Namespace),
+			cmp.Compare(a.Name, b.Name),
+			cmp.Compare(a.Reason, b.Reason),
+		)
+	})
+	eventMesh.Warnings = slices.Compact(eventMesh.Warnings)
+}
+
+// The sort keys identify the objects in the lists of the event mesh, which are sorted by them. The event mesh is also
+// paged by them, see eventMeshPage.
+
+func brokerSortKey(br Broker) string {
+	return br.Namespace + "/" + br.Name
+}
+
+func eventTypeSortKey(et EventType) string {
+	return et.NamespacedName()
+}
+
+func subscribableSortKey(sb Subscribable) string {
+	return util.GKNamespacedName(sb.Group, sb.Kind, sb.Namespace, sb.Name)
+}
+
+func sourceSortKey(src Source) string {
+	return util.GKNamespacedName(src.Group, src.Kind, src.Namespace, src.Name)
+}
+
+func sinkSortKey(sk Sink) string {
+	return util.GKNamespacedName(sk.Group, sk.Kind, sk.Namespace, sk.Name)
+}
+
+func sequenceSortKey(sq Sequence) string {
+	return sq.Namespace + "/" + sq.Name
+}
+
+func parallelSortKey(p Parallel) string {
+	return p.Namespace + "/" + p.Name
+}
+
+func triggerSortKey(t Trigger) string {
+	return t.Namespace + "/" + t.Name
+}
+
+func subscriptionSortKey(sub Subscription) string {
+	return sub.Namespace + "/" + sub.Name
+}
+
+// normalizeConsumers sorts the consumers of the event type and merges the ones that the events reach in several ways,
+// e.g. when several triggers point at the same subscriber.
+// A consumer is only indeterminate when the events may or may not reach it in all of those ways.
+func normalizeConsumers(et *EventType) {
+	et.ConsumedBy = sortedUnique(et.ConsumedBy)
+
+	if et.Consumers != nil {
+		slices.SortStableFunc(et.Consumers, compareConsumers)
+		consumers := et.Consumers[:0]
+		for _, c := range et.Consumers {
+			if n := len(consumers); n > 0 && compareConsumers(consumers[n-1], c) == 0 {
+				consumers[n-1].Indeterminate = consumers[n-1].Indeterminate && c.Indeterminate
+				continue
+			}
+			consumers = append(consumers, c)
+		}
+		et.Consumers = consumers
+
+		// the consumers that the events reach for sure in some way are not indeterminate
+		determinate := make(map[string]bool)
+		for _, c := range et.Consumers {
+			if !c.Indeterminate {
+				determinate[c.BackstageID] = true
+			}
+		}
+		et.IndeterminateConsumedBy = slices.DeleteFunc(et.IndeterminateConsumedBy, func(id string) bool {
+			return determinate[id]
+		})
+	}
+
+	et.IndeterminateConsumedBy = sortedUnique(et.IndeterminateConsumedBy)
+	if len(et.IndeterminateConsumedBy) == 0 {
+		et.IndeterminateConsumedBy = nil
+	}
+}
+
+// compareConsumers orders the consumers by their Backstage IDs, then by the hops the events reach them through.
+func compareConsumers(a, b EventTypeConsumer) int {
+	return cmp.Or(
+		cmp.Compare(a.BackstageID, b.BackstageID),
+		cmp.Compare(hopKey(a.Hop), hopKey(b.Hop)),
+	)
+}
+
+func hopKey(hop *GroupKindNamespacedName) string {
+	if hop == nil {
+		return ""
+	}
+	return hop.String()
+}
+
+// sortedUnique sorts the values and removes the duplicates, in place. Nil and empty slices are returned as they are.
+func sortedUnique(values []string) []string {
+	slices.Sort(values)
+	return slices.Compact(values)
+}
+
+// sortedUniqueFunc sorts the items by their keys and removes the items with duplicate keys, in place.
+// Of the items with the same key, the first one is kept.
+func sortedUniqueFunc[T any](items []T, key func(T) string) []T {
+	slices.SortStableFunc(items, func(a, b T) int {
+		return cmp.Compare(key(a), key(b))
+	})
+	return slices.CompactFunc(items, func(a, b T) bool {
+		return key(a) == key(b)
+	})
+}
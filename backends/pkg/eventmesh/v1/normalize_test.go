@@ -0,0 +1,300 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	messagingv1 "knative.dev/eventing/pkg/apis/messaging/v1"
+	sourcesv1 "knative.dev/eventing/pkg/apis/sources/v1"
+	fakeclientset "knative.dev/eventing/pkg/client/clientset/versioned/fake"
+	testingv1 "knative.dev/eventing/pkg/reconciler/testing/v1"
+	testingv1beta2 "knative.dev/eventing/pkg/reconciler/testing/v1beta2"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+func TestNormalizeEventMesh(t *testing.T) {
+	hop := func(name string) *GroupKindNamespacedName {
+		return &GroupKindNamespacedName{Group: "eventing.knative.dev", Kind: "Broker", Namespace: "test-ns", Name: name}
+	}
+
+	tests := []struct {
+		name      string
+		eventMesh EventMesh
+		want      EventMesh
+	}{
+		{
+			name:      "Empty event mesh",
+			eventMesh: EventMesh{Brokers: []Broker{}, EventTypes: []EventType{}},
+			want:      EventMesh{Brokers: []Broker{}, EventTypes: []EventType{}},
+		},
+		{
+			name: "Lists are sorted and duplicates are removed",
+			eventMesh: EventMesh{
+				Brokers: []Broker{
+					{Namespace: "test-ns", Name: "test-broker-2", ProvidedEventTypes: []string{"test-ns/b", "test-ns/a", "test-ns/b"}},
+					{Namespace: "other-ns", Name: "test-broker"},
+					{Namespace: "test-ns", Name: "test-broker-1"},
+				},
+				Sources: []Source{
+					{Group: "sources.knative.dev", Kind: "PingSource", Namespace: "test-ns", Name: "test-src", ProvidedEventTypeTypes: []string{"b", "a"}},
+					{Group: "sources.knative.dev", Kind: "ApiServerSource", Namespace: "test-ns", Name: "test-src"},
+				},
+				Sinks: []Sink{
+					{Group: "sinks.knative.dev", Kind: "JobSink", Namespace: "test-ns", Name: "test-sink", ConsumedEventTypes: []string{"test-ns/b", "test-ns/a", "test-ns/a"}},
+				},
+				Warnings: []Warning{
+					{Group: "eventing.knative.dev", Kind: "Trigger", Reason: "forbidden"},
+					{Group: "eventing.knative.dev", Kind: "Broker", Reason: "forbidden"},
+					{Group: "eventing.knative.dev", Kind: "Trigger", Reason: "forbidden"},
+				},
+			},
+			want: EventMesh{
+				Brokers: []Broker{
+					{Namespace: "other-ns", Name: "test-broker"},
+					{Namespace: "test-ns", Name: "test-broker-1"},
+					{Namespace: "test-ns", Name: "test-broker-2", ProvidedEventTypes: []string{"test-ns/a", "test-ns/b"}},
+				},
+				Sources: []Source{
+					{Group: "sources.knative.dev", Kind: "ApiServerSource", Namespace: "test-ns", Name: "test-src"},
+					{Group: "sources.knative.dev", Kind: "PingSource", Namespace: "test-ns", Name: "test-src", ProvidedEventTypeTypes: []string{"a", "b"}},
+				},
+				Sinks: []Sink{
+					{Group: "sinks.knative.dev", Kind: "JobSink", Namespace: "test-ns", Name: "test-sink", ConsumedEventTypes: []string{"test-ns/a", "test-ns/b"}},
+				},
+				Warnings: []Warning{
+					{Group: "eventing.knative.dev", Kind: "Broker", Reason: "forbidden"},
+					{Group: "eventing.knative.dev", Kind: "Trigger", Reason: "forbidden"},
+				},
+			},
+		},
+		{
+			name: "Consumers that the events reach in several ways are merged",
+			eventMesh: EventMesh{
+				EventTypes: []EventType{
+					{
+						Namespace:               "test-ns",
+						Name:                    "test-et",
+						ConsumedBy:              []string{"test-svc-2", "test-svc-1", "test-svc-2", "test-svc-1"},
+						IndeterminateConsumedBy: []string{"test-svc-2", "test-svc-1"},
+						Consumers: []EventTypeConsumer{
+							{BackstageID: "test-svc-2", Hop: hop("test-broker"), Indeterminate: true},
+							{BackstageID: "test-svc-1", Hop: hop("test-broker"), Indeterminate: true},
+							{BackstageID: "test-svc-2", Hop: hop("test-broker"), Indeterminate: true},
+							{BackstageID: "test-svc-1", Hop: hop("test-broker")},
+						},
+					},
+				},
+			},
+			want: EventMesh{
+				EventTypes: []EventType{
+					{
+						Namespace:               "test-ns",
+						Name:                    "test-et",
+						ConsumedBy:              []string{"test-svc-1", "test-svc-2"},
+						IndeterminateConsumedBy: []string{"test-svc-2"},
+						Consumers: []EventTypeConsumer{
+							{BackstageID: "test-svc-1", Hop: hop("test-broker")},
+							{BackstageID: "test-svc-2", Hop: hop("test-broker"), Indeterminate: true},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Consumers reached through different hops are kept",
+			eventMesh: EventMesh{
+				EventTypes: []EventType{
+					{
+						Namespace:  "test-ns",
+						Name:       "test-et",
+						ConsumedBy: []string{"test-svc"},
+						Consumers: []EventTypeConsumer{
+							{BackstageID: "test-svc", Hop: hop("test-broker-2")},
+							{BackstageID: "test-svc"},
+							{BackstageID: "test-svc", Hop: hop("test-broker-1")},
+						},
+					},
+				},
+			},
+			want: EventMesh{
+				EventTypes: []EventType{
+					{
+						Namespace:  "test-ns",
+						Name:       "test-et",
+						ConsumedBy: []string{"test-svc"},
+						Consumers: []EventTypeConsumer{
+							{BackstageID: "test-svc"},
+							{BackstageID: "test-svc", Hop: hop("test-broker-1")},
+							{BackstageID: "test-svc", Hop: hop("test-broker-2")},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.eventMesh
+			normalizeEventMesh(&got)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Error("normalizeEventMesh() (-want, +got):", diff)
+			}
+		})
+	}
+}
+
+// TestBuildEventMeshGolden builds the event mesh from the same resources several times and compares it to the golden
+// files in testdata. The fake clients list the resources in random order, so the event mesh is only the same every
+// time when all its lists are sorted.
+// Run the test with -update to regenerate the golden files.
+func TestBuildEventMeshGolden(t *testing.T) {
+	tests := []struct {
+		name    string
+		golden  string
+		objects []runtime.Object
+		// extraObjects are the objects that are only listed with the dynamic client
+		extraObjects []runtime.Object
+	}{
+		{
+			name:   "Several triggers point at the same subscriber",
+			golden: "shared-subscriber.golden.json",
+			objects: []runtime.Object{
+				testingv1.NewBroker("test-broker-b", "test-ns"),
+				testingv1.NewBroker("test-broker-a", "test-ns"),
+				testingv1.NewBroker("test-broker", "other-ns"),
+				testingv1beta2.NewEventType("test-eventtype-b", "test-ns",
+					testingv1beta2.WithEventTypeType("test-type-b"),
+					testingv1beta2.WithEventTypeReference(brokerReference("test-broker-a", "test-ns")),
+				),
+				testingv1beta2.NewEventType("test-eventtype-a", "test-ns",
+					testingv1beta2.WithEventTypeType("test-type-a"),
+					testingv1beta2.WithEventTypeReference(brokerReference("test-broker-a", "test-ns")),
+				),
+				testingv1beta2.NewEventType("test-eventtype-c", "test-ns",
+					testingv1beta2.WithEventTypeType("test-type-c"),
+					testingv1beta2.WithEventTypeReference(brokerReference("test-broker-b", "test-ns")),
+				),
+				testingv1.NewTrigger("test-trigger-3", "test-ns", "test-broker-a",
+					WithTriggerSubscriber(reference("v1", "Service", "test-ns", "test-subscriber-b")),
+				),
+				testingv1.NewTrigger("test-trigger-2", "test-ns", "test-broker-a",
+					WithTriggerSubscriber(reference("v1", "Service", "test-ns", "test-subscriber-a")),
+					WithEventTypeFilter("test-type-a"),
+				),
+				testingv1.NewTrigger("test-trigger-1", "test-ns", "test-broker-a",
+					WithTriggerSubscriber(reference("v1", "Service", "test-ns", "test-subscriber-a")),
+				),
+				testingv1.NewTrigger("test-trigger-4", "test-ns", "test-broker-a",
+					WithTriggerSubscriber(brokerReference("test-broker-b", "test-ns")),
+				),
+				testingv1.NewTrigger("test-trigger-5", "test-ns", "test-broker-b",
+					WithTriggerSubscriber(reference("messaging.knative.dev/v1", "InMemoryChannel", "test-ns", "test-imc")),
+				),
+				&messagingv1.Subscription{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-subscription-2", Namespace: "test-ns"},
+					Spec: messagingv1.SubscriptionSpec{
+						Channel:    duckv1.KReference{APIVersion: "messaging.knative.dev/v1", Kind: "InMemoryChannel", Name: "test-imc"},
+						Subscriber: &duckv1.Destination{Ref: reference("v1", "Service", "test-ns", "test-subscriber-a")},
+					},
+				},
+				&messagingv1.Subscription{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-subscription-1", Namespace: "test-ns"},
+					Spec: messagingv1.SubscriptionSpec{
+						Channel:    duckv1.KReference{APIVersion: "messaging.knative.dev/v1", Kind: "InMemoryChannel", Name: "test-imc"},
+						Subscriber: &duckv1.Destination{Ref: reference("v1", "Service", "test-ns", "test-subscriber-b")},
+					},
+				},
+			},
+			extraObjects: []runtime.Object{
+				&apiextensionsv1.CustomResourceDefinition{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "inmemorychannels.messaging.knative.dev",
+						Labels: map[string]string{"messaging.knative.dev/subscribable": "true"},
+					},
+					Spec: inMemoryChannelCRDSpec,
+				},
+				&messagingv1.InMemoryChannel{ObjectMeta: metav1.ObjectMeta{Name: "test-imc-2", Namespace: "test-ns"}},
+				&messagingv1.InMemoryChannel{ObjectMeta: metav1.ObjectMeta{Name: "test-imc", Namespace: "test-ns"}},
+				sinkCRD("sinks.knative.dev", "JobSink", "jobsinks"),
+				newSink("sinks.knative.dev/v1alpha1", "JobSink", "test-jobsink-b", nil, ""),
+				newSink("sinks.knative.dev/v1alpha1", "JobSink", "test-jobsink-a", nil, ""),
+				backstageService("test-subscriber-b"),
+				backstageService("test-subscriber-a"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			golden := filepath.Join("testdata", tt.golden)
+
+			var first []byte
+			for i := 0; i < 10; i++ {
+				got := buildGoldenEventMesh(t, tt.objects, tt.extraObjects)
+				if first == nil {
+					first = got
+				} else if diff := cmp.Diff(string(first), string(got)); diff != "" {
+					t.Fatal("BuildEventMesh() isn't deterministic (-first, +got):", diff)
+				}
+			}
+
+			if *update {
+				if err := os.WriteFile(golden, first, 0o644); err != nil {
+					t.Fatalf("Error updating the golden file %s: %v", golden, err)
+				}
+			}
+
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("Error reading the golden file %s: %v", golden, err)
+			}
+			if diff := cmp.Diff(string(want), string(first)); diff != "" {
+				t.Errorf("BuildEventMesh() differs from %s (-want, +got): %s", golden, diff)
+			}
+		})
+	}
+}
+
+// buildGoldenEventMesh builds the event mesh with fake clients and returns it in the format of the golden files.
+func buildGoldenEventMesh(t *testing.T, objects, extraObjects []runtime.Object) []byte {
+	t.Helper()
+
+	sc := runtime.NewScheme()
+	_ = corev1.AddToScheme(sc)
+	_ = eventingv1.AddToScheme(sc)
+	_ = messagingv1.AddToScheme(sc)
+	_ = sourcesv1.AddToScheme(sc)
+	_ = apiextensionsv1.AddToScheme(sc)
+
+	fakeDynamicClient := dynamicfake.NewSimpleDynamicClient(sc, extraObjects...)
+	fakeClient := fakeclientset.NewSimpleClientset(objects...)
+
+	eventMesh, err := BuildEventMesh(context.TODO(), fakeClient, fakeDynamicClient, nil, nil, zap.NewNop().Sugar())
+	if err != nil {
+		t.Fatalf("BuildEventMesh() error = %v", err)
+	}
+
+	out, err := json.MarshalIndent(eventMesh, "", "  ")
+	if err != nil {
+		t.Fatalf("Error marshalling the event mesh: %v", err)
+	}
+	return append(out, '\n')
+}
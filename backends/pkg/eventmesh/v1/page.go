@@ -0,0 +1,160 @@
+package v1
+
+import (
+	"cmp"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+// eventMeshKinds are the lists of the event mesh, in the order that the event mesh is paged through them.
+var eventMeshKinds = []EventMeshKind{
+	MeshKindBrokers,
+	MeshKindEventTypes,
+	MeshKindSubscribables,
+	MeshKindSources,
+	MeshKindSinks,
+	MeshKindSequences,
+	MeshKindParallels,
+	MeshKindTriggers,
+	MeshKindSubscriptions,
+}
+
+// continueToken is the position in the event mesh that the next page starts after.
+// The position is the sort key of the last object of the previous page rather than an offset, so that no objects are
+// skipped or repeated when objects before it are added or deleted in between the pages.
+type continueToken struct {
+	Kind EventMeshKind `json:"kind"`
+	Key  string        `json:"key"`
+}
+
+func (t continueToken) encode() string {
+	data, _ := json.Marshal(t)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeContinueToken(value string) (*continueToken, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid continue token: %w", err)
+	}
+	token := &continueToken{}
+	if err := json.Unmarshal(data, token); err != nil {
+		return nil, fmt.Errorf("invalid continue token: %w", err)
+	}
+	if !slices.Contains(eventMeshKinds, token.Kind) {
+		return nil, fmt.Errorf("invalid continue token: unknown kind %q", token.Kind)
+	}
+	return token, nil
+}
+
+// eventMeshPage selects the objects of the event mesh that a page holds.
+type eventMeshPage struct {
+	// kinds are the lists that are selected. All lists are selected when it's nil.
+	kinds map[EventMeshKind]bool
+	// limit is the maximum number of objects of the page. There's no limit when it's 0.
+	limit int
+	// after is the position that the page starts after. The page starts at the beginning when it's nil.
+	after *continueToken
+
+	count int
+	last  *continueToken
+	next  *continueToken
+}
+
+// newEventMeshPage validates the paging parameters of a request.
+func newEventMeshPage(kinds []EventMeshKind, limit int, continueValue string) (*eventMeshPage, error) {
+	if limit < 0 {
+		return nil, fmt.Errorf("invalid limit %d, it must be positive", limit)
+	}
+	page := &eventMeshPage{limit: limit}
+
+	if len(kinds) > 0 {
+		page.kinds = make(map[EventMeshKind]bool, len(kinds))
+		for _, kind := range kinds {
+			if !slices.Contains(eventMeshKinds, kind) {
+				return nil, fmt.Errorf("unknown kind %q", kind)
+			}
+			page.kinds[kind] = true
+		}
+	}
+
+	if continueValue != "" {
+		token, err := decodeContinueToken(continueValue)
+		if err != nil {
+			return nil, err
+		}
+		page.after = token
+	}
+
+	return page, nil
+}
+
+// apply returns the objects of the event mesh that the page holds, along with the continue token of the next page if
+// there are more objects. The lists of the event mesh must be sorted, see normalizeEventMesh.
+// The warnings and the discovered kinds are about the whole event mesh, so they're kept on every page.
+func (p *eventMeshPage) apply(eventMesh EventMesh) EventMesh {
+	if p.kinds == nil && p.limit == 0 && p.after == nil {
+		return eventMesh
+	}
+
+	page := EventMesh{
+		Brokers:         pageList(p, MeshKindBrokers, eventMesh.Brokers, brokerSortKey),
+		EventTypes:      pageList(p, MeshKindEventTypes, eventMesh.EventTypes, eventTypeSortKey),
+		Subscribables:   pageList(p, MeshKindSubscribables, eventMesh.Subscribables, subscribableSortKey),
+		Sources:         pageList(p, MeshKindSources, eventMesh.Sources, sourceSortKey),
+		Sinks:           pageList(p, MeshKindSinks, eventMesh.Sinks, sinkSortKey),
+		Sequences:       pageList(p, MeshKindSequences, eventMesh.Sequences, sequenceSortKey),
+		Parallels:       pageList(p, MeshKindParallels, eventMesh.Parallels, parallelSortKey),
+		Triggers:        pageList(p, MeshKindTriggers, eventMesh.Triggers, triggerSortKey),
+		Subscriptions:   pageList(p, MeshKindSubscriptions, eventMesh.Subscriptions, subscriptionSortKey),
+		Warnings:        eventMesh.Warnings,
+		DiscoveredKinds: eventMesh.DiscoveredKinds,
+	}
+	if p.next != nil {
+		page.Continue = p.next.encode()
+	}
+	return page
+}
+
+// selects returns whether the objects of the kind can be on the page, i.e. the kind is selected and doesn't come
+// before the position that the page starts after.
+func (p *eventMeshPage) selects(kind EventMeshKind) bool {
+	if p.kinds != nil && !p.kinds[kind] {
+		return false
+	}
+	return p.after == nil || slices.Index(eventMeshKinds, kind) >= slices.Index(eventMeshKinds, p.after.Kind)
+}
+
+// pageList returns the objects of the list that are on the page. The list must be sorted by the key.
+// The pages are filled in the order of eventMeshKinds, so the lists must be paged in that order too.
+func pageList[T any](p *eventMeshPage, kind EventMeshKind, items []T, key func(T) string) []T {
+	result := make([]T, 0)
+	if p.next != nil || !p.selects(kind) {
+		return result
+	}
+
+	start := 0
+	if p.after != nil && p.after.Kind == kind {
+		var found bool
+		start, found = slices.BinarySearchFunc(items, p.after.Key, func(item T, k string) int {
+			return cmp.Compare(key(item), k)
+		})
+		if found {
+			start++
+		}
+	}
+
+	for _, item := range items[start:] {
+		if p.limit > 0 && p.count == p.limit {
+			// there are more objects than fit on the page
+			p.next = p.last
+			break
+		}
+		result = append(result, item)
+		p.count++
+		p.last = &continueToken{Kind: kind, Key: key(item)}
+	}
+	return result
+}
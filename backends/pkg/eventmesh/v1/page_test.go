@@ -0,0 +1,216 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewEventMeshPage(t *testing.T) {
+	tests := []struct {
+		name          string
+		kinds         []EventMeshKind
+		limit         int
+		continueValue string
+		error         bool
+	}{
+		{
+			name: "No paging",
+		},
+		{
+			name:          "Valid paging",
+			kinds:         []EventMeshKind{MeshKindBrokers, MeshKindTriggers},
+			limit:         10,
+			continueValue: continueToken{Kind: MeshKindTriggers, Key: "test-ns/test-trigger"}.encode(),
+		},
+		{
+			name:  "Negative limit",
+			limit: -1,
+			error: true,
+		},
+		{
+			name:  "Unknown kind",
+			kinds: []EventMeshKind{"pods"},
+			error: true,
+		},
+		{
+			name:          "Malformed continue token",
+			continueValue: "not a token",
+			error:         true,
+		},
+		{
+			name:          "Continue token of an unknown kind",
+			continueValue: continueToken{Kind: "pods", Key: "test-ns/test-pod"}.encode(),
+			error:         true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newEventMeshPage(tt.kinds, tt.limit, tt.continueValue)
+			if (err != nil) != tt.error {
+				t.Errorf("newEventMeshPage() error = %v, error %v", err, tt.error)
+			}
+		})
+	}
+}
+
+func TestEventMeshPage(t *testing.T) {
+	brokers := []Broker{
+		{Namespace: "test-ns", Name: "test-broker-1"},
+		{Namespace: "test-ns", Name: "test-broker-2"},
+	}
+	eventTypes := []EventType{
+		{Namespace: "test-ns", Name: "test-et-1"},
+		{Namespace: "test-ns", Name: "test-et-2"},
+	}
+	triggers := []Trigger{
+		{Namespace: "test-ns", Name: "test-trigger"},
+	}
+	warnings := []Warning{{Group: "sources.knative.dev", Kind: "PingSource", Reason: "forbidden"}}
+
+	eventMesh := EventMesh{
+		Brokers:       brokers,
+		EventTypes:    eventTypes,
+		Subscribables: []Subscribable{},
+		Sources:       []Source{},
+		Sinks:         []Sink{},
+		Sequences:     []Sequence{},
+		Parallels:     []Parallel{},
+		Triggers:      triggers,
+		Subscriptions: []Subscription{},
+		Warnings:      warnings,
+	}
+
+	// page returns an event mesh page with the given lists, the others are empty
+	page := func(modify func(*EventMesh)) EventMesh {
+		em := EventMesh{
+			Brokers:       []Broker{},
+			EventTypes:    []EventType{},
+			Subscribables: []Subscribable{},
+			Sources:       []Source{},
+			Sinks:         []Sink{},
+			Sequences:     []Sequence{},
+			Parallels:     []Parallel{},
+			Triggers:      []Trigger{},
+			Subscriptions: []Subscription{},
+			Warnings:      warnings,
+		}
+		modify(&em)
+		return em
+	}
+
+	tests := []struct {
+		name          string
+		eventMesh     EventMesh
+		kinds         []EventMeshKind
+		limit         int
+		continueValue string
+		want          EventMesh
+	}{
+		{
+			name:      "Without paging parameters the whole event mesh is returned",
+			eventMesh: eventMesh,
+			want:      eventMesh,
+		},
+		{
+			name:      "Only the selected kinds are returned",
+			eventMesh: eventMesh,
+			kinds:     []EventMeshKind{MeshKindTriggers, MeshKindBrokers},
+			want: page(func(em *EventMesh) {
+				em.Brokers = brokers
+				em.Triggers = triggers
+			}),
+		},
+		{
+			name:      "The page is filled across the lists",
+			eventMesh: eventMesh,
+			limit:     3,
+			want: page(func(em *EventMesh) {
+				em.Brokers = brokers
+				em.EventTypes = eventTypes[:1]
+				em.Continue = continueToken{Kind: MeshKindEventTypes, Key: "test-ns/test-et-1"}.encode()
+			}),
+		},
+		{
+			name:          "The next page starts after the continue token",
+			eventMesh:     eventMesh,
+			limit:         3,
+			continueValue: continueToken{Kind: MeshKindEventTypes, Key: "test-ns/test-et-1"}.encode(),
+			want: page(func(em *EventMesh) {
+				em.EventTypes = eventTypes[1:]
+				em.Triggers = triggers
+			}),
+		},
+		{
+			name:      "A page that ends with the last object has no continue token",
+			eventMesh: eventMesh,
+			kinds:     []EventMeshKind{MeshKindBrokers},
+			limit:     2,
+			want: page(func(em *EventMesh) {
+				em.Brokers = brokers
+			}),
+		},
+		{
+			name: "The next page starts after the position of a deleted object",
+			eventMesh: page(func(em *EventMesh) {
+				em.Brokers = brokers
+			}),
+			continueValue: continueToken{Kind: MeshKindBrokers, Key: "test-ns/test-broker-1a"}.encode(),
+			want: page(func(em *EventMesh) {
+				em.Brokers = brokers[1:]
+			}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := newEventMeshPage(tt.kinds, tt.limit, tt.continueValue)
+			if err != nil {
+				t.Fatalf("newEventMeshPage() error = %v", err)
+			}
+
+			got := p.apply(tt.eventMesh)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Error("apply() (-want, +got):", diff)
+			}
+		})
+	}
+}
+
+func TestEventMeshPageAll(t *testing.T) {
+	eventMesh := EventMesh{
+		Brokers:    []Broker{{Namespace: "a", Name: "b1"}, {Namespace: "a", Name: "b2"}, {Namespace: "b", Name: "b1"}},
+		EventTypes: []EventType{{Namespace: "a", Name: "et1"}},
+		Sinks:      []Sink{{Group: "sinks.knative.dev", Kind: "JobSink", Namespace: "a", Name: "s1"}},
+		Triggers:   []Trigger{{Namespace: "a", Name: "t1"}, {Namespace: "a", Name: "t2"}},
+	}
+
+	for limit := 1; limit <= 8; limit++ {
+		var got EventMesh
+		continueValue := ""
+		for pages := 0; ; pages++ {
+			if pages > 7 {
+				t.Fatalf("limit %d: paging doesn't end", limit)
+			}
+			p, err := newEventMeshPage(nil, limit, continueValue)
+			if err != nil {
+				t.Fatalf("limit %d: newEventMeshPage() error = %v", limit, err)
+			}
+			page := p.apply(eventMesh)
+			got.Brokers = append(got.Brokers, page.Brokers...)
+			got.EventTypes = append(got.EventTypes, page.EventTypes...)
+			got.Sinks = append(got.Sinks, page.Sinks...)
+			got.Triggers = append(got.Triggers, page.Triggers...)
+
+			if page.Continue == "" {
+				break
+			}
+			continueValue = page.Continue
+		}
+
+		if diff := cmp.Diff(eventMesh, got); diff != "" {
+			t.Errorf("limit %d: the pages differ from the event mesh (-want, +got): %s", limit, diff)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package v1
+
+import (
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+	flowsv1 "knative.dev/eventing/pkg/apis/flows/v1"
+
+	"knative.dev/backstage-plugins/backends/pkg/util"
+)
+
+// convertParallel converts a Knative Flows Parallel to a simplified representation that is easier to consume by the Backstage plugin.
+// see Parallel.
+func convertParallel(parallel *flowsv1.Parallel) Parallel {
+	converted := Parallel{
+		Namespace:      parallel.Namespace,
+		Name:           parallel.Name,
+		UID:            string(parallel.UID),
+		Labels:         parallel.Labels,
+		Annotations:    util.FilterAnnotations(parallel.Annotations),
+		Branches:       make([]ParallelBranch, 0, len(parallel.Spec.Branches)),
+		IngressChannel: convertObjectReference(parallel.Status.IngressChannelStatus.Channel),
+		// this field will be populated later on, when the event types sent to the parallel are known
+		ProvidedEventTypes: []string{},
+		Status:             convertStatus(parallel.Status.Conditions, parallel.Status.AddressStatus),
+	}
+	for i, branch := range parallel.Spec.Branches {
+		convertedBranch := ParallelBranch{
+			Subscriber: convertDestination(branch.Subscriber, parallel.Namespace),
+			// the resolved dead letter sinks are only reported by the underlying subscriptions
+			Delivery: convertDelivery(branch.Delivery, eventingduckv1.DeliveryStatus{}, parallel.Namespace),
+			// this field will be populated later on, when the subscriber is fetched
+			BackstageID: "",
+		}
+		if branch.Filter != nil {
+			filter := convertDestination(*branch.Filter, parallel.Namespace)
+			convertedBranch.Filter = &filter
+		}
+		if branch.Reply != nil {
+			reply := convertDestination(*branch.Reply, parallel.Namespace)
+			convertedBranch.Reply = &reply
+		}
+		// the channels and the subscriptions of each branch are created by the parallel
+		if i < len(parallel.Status.BranchStatuses) {
+			status := parallel.Status.BranchStatuses[i]
+			convertedBranch.FilterChannel = convertObjectReference(status.FilterChannelStatus.Channel)
+			convertedBranch.FilterSubscription = convertObjectReference(status.FilterSubscriptionStatus.Subscription)
+			convertedBranch.Subscription = convertObjectReference(status.SubscriptionStatus.Subscription)
+		}
+		converted.Branches = append(converted.Branches, convertedBranch)
+	}
+	if parallel.Spec.Reply != nil {
+		reply := convertDestination(*parallel.Spec.Reply, parallel.Namespace)
+		converted.Reply = &reply
+	}
+	return converted
+}
@@ -0,0 +1,47 @@
+package v1
+
+import (
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+	flowsv1 "knative.dev/eventing/pkg/apis/flows/v1"
+
+	"knative.dev/backstage-plugins/backends/pkg/util"
+)
+
+// convertSequence converts a Knative Flows Sequence to a simplified representation that is easier to consume by the Backstage plugin.
+// see Sequence.
+func convertSequence(sequence *flowsv1.Sequence) Sequence {
+	converted := Sequence{
+		Namespace:   sequence.Namespace,
+		Name:        sequence.Name,
+		UID:         string(sequence.UID),
+		Labels:      sequence.Labels,
+		Annotations: util.FilterAnnotations(sequence.Annotations),
+		Steps:       make([]SequenceStep, 0, len(sequence.Spec.Steps)),
+		// this field will be populated later on, when the event types sent to the sequence are known
+		ProvidedEventTypes: []string{},
+		Status:             convertStatus(sequence.Status.Conditions, sequence.Status.AddressStatus),
+	}
+	for i, step := range sequence.Spec.Steps {
+		convertedStep := SequenceStep{
+			Subscriber: convertDestination(step.Destination, sequence.Namespace),
+			// the resolved dead letter sinks are only reported by the underlying subscriptions
+			Delivery: convertDelivery(step.Delivery, eventingduckv1.DeliveryStatus{}, sequence.Namespace),
+			// these fields will be populated later on, like the ones of the sequence
+			ProvidedEventTypes: []string{},
+			BackstageID:        "",
+		}
+		// the channel and the subscription of each step are created by the sequence
+		if i < len(sequence.Status.ChannelStatuses) {
+			convertedStep.Channel = convertObjectReference(sequence.Status.ChannelStatuses[i].Channel)
+		}
+		if i < len(sequence.Status.SubscriptionStatuses) {
+			convertedStep.Subscription = convertObjectReference(sequence.Status.SubscriptionStatuses[i].Subscription)
+		}
+		converted.Steps = append(converted.Steps, convertedStep)
+	}
+	if sequence.Spec.Reply != nil {
+		reply := convertDestination(*sequence.Spec.Reply, sequence.Namespace)
+		converted.Reply = &reply
+	}
+	return converted
+}
@@ -10,6 +10,7 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"path"
@@ -17,6 +18,7 @@ import (
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/gorilla/mux"
+	"github.com/oapi-codegen/runtime"
 	strictnethttp "github.com/oapi-codegen/runtime/strictmiddleware/nethttp"
 )
 
@@ -24,7 +26,13 @@ import (
 type ServerInterface interface {
 	// Retrieve EventMesh
 	// (GET /getEventMesh)
-	GetEventMesh(w http.ResponseWriter, r *http.Request)
+	GetEventMesh(w http.ResponseWriter, r *http.Request, params GetEventMeshParams)
+	// Retrieve EventMesh of a namespace
+	// (GET /namespaces/{namespace}/eventmesh)
+	GetNamespacedEventMesh(w http.ResponseWriter, r *http.Request, namespace string, params GetNamespacedEventMeshParams)
+	// Watch EventMesh
+	// (GET /watchEventMesh)
+	WatchEventMesh(w http.ResponseWriter, r *http.Request, params WatchEventMeshParams)
 }
 
 // ServerInterfaceWrapper converts contexts to parameters.
@@ -39,14 +47,192 @@ type MiddlewareFunc func(http.Handler) http.Handler
 // GetEventMesh operation middleware
 func (siw *ServerInterfaceWrapper) GetEventMesh(w http.ResponseWriter, r *http.Request) {
 
+	var err error
+
 	ctx := r.Context()
 
 	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
 
 	r = r.WithContext(ctx)
 
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetEventMeshParams
+
+	// ------------- Optional query parameter "namespaces" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "namespaces", r.URL.Query(), &params.Namespaces)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "namespaces", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "kinds" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "kinds", r.URL.Query(), &params.Kinds)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "kinds", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "continue" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "continue", r.URL.Query(), &params.Continue)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "continue", Err: err})
+		return
+	}
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "If-None-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-None-Match")]; found {
+		var IfNoneMatch string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "If-None-Match", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-None-Match", valueList[0], &IfNoneMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "If-None-Match", Err: err})
+			return
+		}
+
+		params.IfNoneMatch = &IfNoneMatch
+
+	}
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetEventMesh(w, r)
+		siw.Handler.GetEventMesh(w, r, params)
+	}))
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetNamespacedEventMesh operation middleware
+func (siw *ServerInterfaceWrapper) GetNamespacedEventMesh(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "namespace" -------------
+	var namespace string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "namespace", mux.Vars(r)["namespace"], &namespace, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "namespace", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetNamespacedEventMeshParams
+
+	// ------------- Optional query parameter "kinds" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "kinds", r.URL.Query(), &params.Kinds)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "kinds", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "continue" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "continue", r.URL.Query(), &params.Continue)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "continue", Err: err})
+		return
+	}
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "If-None-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-None-Match")]; found {
+		var IfNoneMatch string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "If-None-Match", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-None-Match", valueList[0], &IfNoneMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "If-None-Match", Err: err})
+			return
+		}
+
+		params.IfNoneMatch = &IfNoneMatch
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetNamespacedEventMesh(w, r, namespace, params)
+	}))
+
+	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
+		handler = siw.HandlerMiddlewares[i](handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// WatchEventMesh operation middleware
+func (siw *ServerInterfaceWrapper) WatchEventMesh(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params WatchEventMeshParams
+
+	// ------------- Optional query parameter "namespaces" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "namespaces", r.URL.Query(), &params.Namespaces)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "namespaces", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "resourceVersion" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "resourceVersion", r.URL.Query(), &params.ResourceVersion)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "resourceVersion", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.WatchEventMesh(w, r, params)
 	}))
 
 	for i := len(siw.HandlerMiddlewares) - 1; i >= 0; i-- {
@@ -171,22 +357,60 @@ func HandlerWithOptions(si ServerInterface, options GorillaServerOptions) http.H
 
 	r.HandleFunc(options.BaseURL+"/getEventMesh", wrapper.GetEventMesh).Methods("GET")
 
+	r.HandleFunc(options.BaseURL+"/namespaces/{namespace}/eventmesh", wrapper.GetNamespacedEventMesh).Methods("GET")
+
+	r.HandleFunc(options.BaseURL+"/watchEventMesh", wrapper.WatchEventMesh).Methods("GET")
+
 	return r
 }
 
 type GetEventMeshRequestObject struct {
+	Params GetEventMeshParams
 }
 
 type GetEventMeshResponseObject interface {
 	VisitGetEventMeshResponse(w http.ResponseWriter) error
 }
 
-type GetEventMesh200JSONResponse EventMesh
+type GetEventMesh200ResponseHeaders struct {
+	ETag string
+}
+
+type GetEventMesh200JSONResponse struct {
+	Body    EventMesh
+	Headers GetEventMesh200ResponseHeaders
+}
 
 func (response GetEventMesh200JSONResponse) VisitGetEventMeshResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
 	w.WriteHeader(200)
 
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type GetEventMesh304ResponseHeaders struct {
+	ETag string
+}
+
+type GetEventMesh304Response struct {
+	Headers GetEventMesh304ResponseHeaders
+}
+
+func (response GetEventMesh304Response) VisitGetEventMeshResponse(w http.ResponseWriter) error {
+	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
+	w.WriteHeader(304)
+	return nil
+}
+
+type GetEventMesh400JSONResponse struct {
+	Error string `json:"error"`
+}
+
+func (response GetEventMesh400JSONResponse) VisitGetEventMeshResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
 	return json.NewEncoder(w).Encode(response)
 }
 
@@ -201,11 +425,128 @@ func (response GetEventMesh401JSONResponse) VisitGetEventMeshResponse(w http.Res
 	return json.NewEncoder(w).Encode(response)
 }
 
+type GetNamespacedEventMeshRequestObject struct {
+	Namespace string `json:"namespace"`
+	Params    GetNamespacedEventMeshParams
+}
+
+type GetNamespacedEventMeshResponseObject interface {
+	VisitGetNamespacedEventMeshResponse(w http.ResponseWriter) error
+}
+
+type GetNamespacedEventMesh200ResponseHeaders struct {
+	ETag string
+}
+
+type GetNamespacedEventMesh200JSONResponse struct {
+	Body    EventMesh
+	Headers GetNamespacedEventMesh200ResponseHeaders
+}
+
+func (response GetNamespacedEventMesh200JSONResponse) VisitGetNamespacedEventMeshResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type GetNamespacedEventMesh304ResponseHeaders struct {
+	ETag string
+}
+
+type GetNamespacedEventMesh304Response struct {
+	Headers GetNamespacedEventMesh304ResponseHeaders
+}
+
+func (response GetNamespacedEventMesh304Response) VisitGetNamespacedEventMeshResponse(w http.ResponseWriter) error {
+	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
+	w.WriteHeader(304)
+	return nil
+}
+
+type GetNamespacedEventMesh400JSONResponse struct {
+	Error string `json:"error"`
+}
+
+func (response GetNamespacedEventMesh400JSONResponse) VisitGetNamespacedEventMeshResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetNamespacedEventMesh401JSONResponse struct {
+	Error string `json:"error"`
+}
+
+func (response GetNamespacedEventMesh401JSONResponse) VisitGetNamespacedEventMeshResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type WatchEventMeshRequestObject struct {
+	Params WatchEventMeshParams
+}
+
+type WatchEventMeshResponseObject interface {
+	VisitWatchEventMeshResponse(w http.ResponseWriter) error
+}
+
+type WatchEventMesh200ApplicationxNdjsonResponse struct {
+	Body          io.Reader
+	ContentLength int64
+}
+
+func (response WatchEventMesh200ApplicationxNdjsonResponse) VisitWatchEventMeshResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.WriteHeader(200)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
+}
+
+type WatchEventMesh401JSONResponse struct {
+	Error string `json:"error"`
+}
+
+func (response WatchEventMesh401JSONResponse) VisitWatchEventMeshResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type WatchEventMesh503JSONResponse struct {
+	Error string `json:"error"`
+}
+
+func (response WatchEventMesh503JSONResponse) VisitWatchEventMeshResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(503)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
 // StrictServerInterface represents all server handlers.
 type StrictServerInterface interface {
 	// Retrieve EventMesh
 	// (GET /getEventMesh)
 	GetEventMesh(ctx context.Context, request GetEventMeshRequestObject) (GetEventMeshResponseObject, error)
+	// Retrieve EventMesh of a namespace
+	// (GET /namespaces/{namespace}/eventmesh)
+	GetNamespacedEventMesh(ctx context.Context, request GetNamespacedEventMeshRequestObject) (GetNamespacedEventMeshResponseObject, error)
+	// Watch EventMesh
+	// (GET /watchEventMesh)
+	WatchEventMesh(ctx context.Context, request WatchEventMeshRequestObject) (WatchEventMeshResponseObject, error)
 }
 
 type StrictHandlerFunc = strictnethttp.StrictHTTPHandlerFunc
@@ -238,9 +579,11 @@ type strictHandler struct {
 }
 
 // GetEventMesh operation middleware
-func (sh *strictHandler) GetEventMesh(w http.ResponseWriter, r *http.Request) {
+func (sh *strictHandler) GetEventMesh(w http.ResponseWriter, r *http.Request, params GetEventMeshParams) {
 	var request GetEventMeshRequestObject
 
+	request.Params = params
+
 	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
 		return sh.ssi.GetEventMesh(ctx, request.(GetEventMeshRequestObject))
 	}
@@ -261,37 +604,177 @@ func (sh *strictHandler) GetEventMesh(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// GetNamespacedEventMesh operation middleware
+func (sh *strictHandler) GetNamespacedEventMesh(w http.ResponseWriter, r *http.Request, namespace string, params GetNamespacedEventMeshParams) {
+	var request GetNamespacedEventMeshRequestObject
+
+	request.Namespace = namespace
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetNamespacedEventMesh(ctx, request.(GetNamespacedEventMeshRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetNamespacedEventMesh")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetNamespacedEventMeshResponseObject); ok {
+		if err := validResponse.VisitGetNamespacedEventMeshResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// WatchEventMesh operation middleware
+func (sh *strictHandler) WatchEventMesh(w http.ResponseWriter, r *http.Request, params WatchEventMeshParams) {
+	var request WatchEventMeshRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.WatchEventMesh(ctx, request.(WatchEventMeshRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "WatchEventMesh")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(WatchEventMeshResponseObject); ok {
+		if err := validResponse.VisitWatchEventMeshResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/9Ra228TORf/Vyx/n8TLJE2B77J5a2EXVcAKcdE+kEp1Zk4ypjO21/YEQpX/fXVsJ3NP",
-	"JqFAeWrGl3N+535s947GMldSgLCGTu+oiVPImft5qeUtaPyVgIk1V5ZLQadhnHBDGDE8VxlfcEiIBqXB",
-	"gLAM1xG5IIy8FMzyFZDfVyAsF0sS9tqUWSQAzHD8lCSWwhQ5kPma2BTIJYtvjWVLICorllyMaUSVlgq0",
-	"5eDQMSGkZ+U/k4TjB8ve1JYtpM6ZpVNqrOZiSSNq1wrK703UkO6ipIsyIJi5A40Q4AvLVQZI+BbWdEpX",
-	"LCsAiQSqcv4JYosDGZtDdr/QXjmS34RKsBzaFv2T5bCHLM3XIz9MoyGokYlRLO7h5KYOsCtJDOKotFzx",
-	"BBLnZ+/Xyiu4oT1uLHIFXEOQhCHbfVu360DzsQbnLF+PHIGRw3AdUW4hH2rOMMC0Zmv8LnjSxvnh6vk+",
-	"5Zw/fvJ09J///u//o98m548HqCeiX0ZLOfKWR+p0s4mohr8LriFBAavKdss8sp0PR7Vg69T2dYevuenX",
-	"YNK2jLspTAIoqZVqlMEKMmKsLmLrM0Qqs8TPe6PluCNhlo3JlX3kZyrrHxliQHOW8a+QECYSYpyp5cCU",
-	"4tVt+jKe8SkvC37EsiwYyBAuHIs4K4z1Btu5xb81LOiU/uuszLNnIcmehQzb4Rmwx5FLtbcBVZ27AYr8",
-	"lfIMyGcgMRME8jkkFdXWtgSxIlx8C6BwMCcGFNPMAplDzAoDREiLu/W6QsMZFMuBlYRVHHiQPnaSdanE",
-	"yELHXfp45yfayjDbidOs4+l2QinmCGDO5lknoOp0B6z69IngKkTaEBsBXvGmaOflTTFKDfcGszNNvz+e",
-	"1hKU23+trqD0+TF5n4IBwjQQxYzB5IN2jwh8iUGFZIazC55Z0JAQWditUDd/uMEKhxuyKESMP4+s7kFd",
-	"yeW6baVnuzlvpptZMZk8icvKhr/cGNzs3NV5pt+ouwTfXyi3O7+1TNYEacr1vPw6AJC+T9G5aqkKcm4t",
-	"JORzCoIYmYNN0SdTphQIMx7WfXz3Vu+wq917G9inw3r/c+/t4B62R7aEGhagQXiu+zLpCy0L9ZKLZAfG",
-	"/XJ53i15zizz0JWGmFnMqVYX0LTZO7fa9yc18HczB29Gp7NggxmNZpXMNaPTuxldcMiSc/97t94LNKOb",
-	"zWaY3B7zh7evOjrLt6+2zZBfVYeZWqvM9AwjN0zHMq8yLXTWH65NZi6jV61aZ7aLtZGPNUiGiXeoZe5z",
-	"oKPb5gFNcgiBvb1yJSV3ldU+52uJ2LMwlNzOnnmJWyJyy0USkR38yDXGIoQ6Iy+LOWgBFgzR4DuAdll1",
-	"pNqgKnsv3lx5hltDbIlF5DO3KdY7HF2BNs265tsTLpbjW98bjBNYDfMGlO0gLFzURFUHcHnk4XZ/6uzm",
-	"8R3P0L0Mj0qZDX/3Jg8qjtru3+XNoWPu6dBPaw/D3l+rN+ywx+GifEKQDQ6x0Nr/gAjrcsULxd+BXoEO",
-	"DvIwuqqTjHQ4/Hti0Rwh+jHBfx+h33Glc+ASrXJo67xIC6gq3aqL+3DiMQriMW682UpR3mk0zhTYJ9zj",
-	"jdsxF4WVe5bD4jm59h2pDglaOzzds9SGi9tvaIMP9VxdLvgQrym3NyAdThDV611ncaveuOy98zmx0FUp",
-	"HF/ugi/WL5diJsgcSJwyITDzMV8icpmHhvznVcgKzh9QJyvcBlTLHIxhyx/TkfYqgl6J15BLvX7mrfdQ",
-	"qubphhtQO3uVgRW0GoD3X0f3sf5Jz1G9mH7mo1S/oh5Azm+cWga9VGGBhLjQ3K7dFU54DAKmQV8UNt09",
-	"i+MmP1xKklqr6AZpcLGQbcVhuFtJNFjNYQVlD+Bfv4RXUchAllunyHLBxZsrGtGQpFDF48l4glaSCgRT",
-	"nE7pk/FkfI6yMps65GdLsLW3tyXYNrC3YAstTAOQ1wkWG8u4wLK0feJiIqn6KsLFrOKwXyV0Sl9UuaIV",
-	"jZLCeGU+nkzwD5IF4cAwpTIeu91nn4y/2PWtyKBHIsfEqb1ZhuMYjFkUWbbeKT3pFHKManw6OT8KWL1e",
-	"gtbS/YNEGQMfBCtsKjX/6m609p+x/f4Oj2zJVSU7rrksnX6sO+vH6811RE2R50yvvaG969UUt/knAAD/",
-	"/7NFVfn5IQAA",
+	"H4sIAAAAAAAC/+x9bXPbOJLwX0Hxea7mropWnGRmX/wtiTO73iQTX+zZ+bBJlSGyJWFEAhwAtKyb8n+/",
+	"witBEpRIWXEye8mXWAQJdDfQr2igf08yVlaMApUiOfs9EdkKSqz/fMnZGrj6KweRcVJJwmhyZp8jIhBG",
+	"gpRVQRYEcsSh4iCASqzeQ2yBMHpDsSS3gF7fApWELpH9Vq6wVB0AFkT9ZChjVNQloPkWyRWglzhbC4mX",
+	"gKqiXhI6S9Kk4qwCLglo6DClzAxlfuY5UT9wcdl6bcF4iWVylgjJCV0maSK3FTS/79MOdi+afhUOCpi5",
+	"BlqBAHe4rApQHa9hm5wlt7ioIbn3vbL5r5BJ9aDAcyiOC9pb3eWDoKK4hP6M/oRL2NFtUm5PzOMkHQO1",
+	"GkRUOBsYSTftGa7pYtSIFWe3JIdcr7PrbQWiP/RbIqQaFdQ7SHUhkPvOLbsINP9qgfOk3J7oDk40DJ/S",
+	"hEgox06nfYA5x1v1W0gsa/3x/+ewSM6S//ek4cYnlhWffADBap7BlXn7Pk1qkvfx+/nifBdRnz57/v3J",
+	"D3/6819O/nr69NkIsqbJ3cmSnZgVo3pP7u/ThMNvNeGQK8KEk6RfM5D5tZ+2mDQ6S58ia/QVo4Zh+jj6",
+	"Jit9NEVQ5p8aoVPPgVOQIBC3pOuLjwILec0xFfrDaxJji7e9d9SwisDqayTVA/WrGV/6tyFHC85KxCg4",
+	"MCVDmDK5MjPjyZ9jCSeqr9iKKUEIvIzA9s40GDqs6hLTEw44x/MCkP0I4TmrZQCvB86tEw94e6n8THU3",
+	"kmn6FbcGS1HPFQBz4D1A7VJRD0/EmlQnrDIC76RihErgyZnkNejlg0VsYj/o5wYbRuFkw3ieole4hOIV",
+	"FoDMd2jB+AH4XHnIPxh88gdg0PBsGwPDnT1IUnTNa0jRj7gQgBhHP9M1ZZsOhOqdYYHRHUqxzh6UPwDO",
+	"t/0eOwysWz1KA7yo1DJ/nccWYdiqIKGQSYEwDWSsWkWKB9gCESmcmudihi7kd4aZNlhmKyQkB1yijNWK",
+	"3BXmWlirvqjkW0SoQ9d87xr9QH0Wd+/uk69eHDl0FOLgHkbIvwJ087E+PX2eeQmof8KT5ql5cOOmqQ1n",
+	"XNV53WKnZffcNQCmDaaxKTyHgtwC3/YRcS1OquXut6ggSxGZwQyt2KZBQCDMAXGQnECOMM3RZgVci4et",
+	"bhIKy80KqHmUYfqdRHPfM+T9WZrjbM0Wi3MocATEl0FrACbeojksmAVmS+gyRVivvIur9+gvfzp9ivKa",
+	"4z5fXF6fzp5dPYD9LbiXrCDZMLym2QGsYUT2S1TpthQVhALmSiTAnVmSBBdtaIOGB4CcA87fgpTArwhd",
+	"7+OGcxCSUE26pPfxz5zEllHrlQ8XDd5G2iL1zDKC6g8V+m0kCF3reeNQMS4hd1xuBFKbFispq7Mnikfy",
+	"QsxCvpmJ22yWFbWQwGcFy3ARqteaF3tMmx78k/SZjHHWB5ANW5WEkrIuEa3LOXBFCMNBQqHrnAMlJ9XL",
+	"hBI13QhLCWUlWzR4HqBFqHz+rEFMQbU0kkuSElgtI3LLNCgAAGerht3tWKNZ6Onp4Rx0H5VQzZKLrC7f",
+	"qAhqBE4gjbxoQZJZpQJEroAjjDgsgAPNwNheitgchNDWjTMNU4TV+kwVI+q/EIfCeK12SoaNSM1Gu9np",
+	"b5zV1RtCc+/56L+0FR9jphareMxn6BcrVAOciEACJJKMpYgovLuAW3Rdf/7LvIVSjMUE8FuSwVHZbApj",
+	"RVcJERnTM63IGVkorXZjSq7VX2zh8RU+8pD7t42dTqRArz6cz5DS7i8uL9AtcKH5Un0QLgOz7AoilMDa",
+	"ELlyfC4k48rsdl9asr/6cI7IwsyQoivkKdJewIYIa1nrp/0RFRgOzi2q9PyJ/ipcqiXWp4f6Wjc5QBQx",
+	"2hNuEZqtTZhmlsNtzApdR+kdBg36XV8SurzS3cd6dLSMSU7TEvZsTRH1sypqjgukllR7vIrQpUUnNqCl",
+	"bZxKO6Y6gKI77e3xb5/utdjMPFlyBjRooIuZb9o6fQdi1YfdN7kVKFl1UsAtFEhIXmfSYLRiRS4CK7RU",
+	"X+RY4sAGD943y5TggvyPNfK0VWdlyv7AnAk+iKG4oTCcWdhoDC4KG64Q3sa3MiYJgiu7RKzpNxZfyRiV",
+	"hNZxz0W3NJRbg9bBC1DOiHpE4U6iSmFqF0FDbj39hniMFlsthp3Vi1qzor63UkLhXBKpKSq1FlMLqmQc",
+	"kJltYYTPBnOqFrN7MRRVaul4I7zm1PUNRkLgpeaKgw3FlgQV+0RsZyYdn3imsTyU+siB0rsGLWX5WVms",
+	"dXhHGMsVEK4Ep0gRLhhdGixlTDQTgWqhtb+BRK6gHL102gj1ltB40sGOwGMT7uov/TAY2Vn+6JcVKQBt",
+	"QLlRCMo55B1X0n9iGShVL68BKk0EJKDCHEtAc8hwLQBRJu1KafrQDEAM/XAQOBxFPo9ZjPnU4EUBRYQi",
+	"l66pT5CqaTpMGri+YyAJ+K0GmsUm6co19UESTdNhILm+oyApToiAox5HQDGPDwRDeX8xEKza7ANhGiJg",
+	"uIYDAdGfR0EJJUUEoLA5Ala7+UDggk52gFj5nacoiKZ5EETX3OH4ax00MXE007LClCpmsNq3icGKqQhV",
+	"zqPvIiQ5WS6jCvvatvTRkL5lHwZOtz8AAQtGDHanK/uw/2Jb+jqq4mxeQGnVT8W1LIRcSR4p2vE6bSlp",
+	"jTQHtXs5r0khjZbmIGTkZSKQkKQovH4ejaUF+GAdNBQeFEnqTbIugzW87wRRsBy6Sz0Un6F032m0xl21",
+	"VnN/hnyzIXXEoJIrzurlSntt6ku/EBnPTYhFrohAQGttC6j/FVEaOrTIs58occx3EOtTzApTYJzcYq5d",
+	"agWPI8FLD5d78jqEzz286sDpn3t4/RMLt/8dwO+eXQZ4uGfXDT6dQR1e4eT+ogL3+teOKW5e0hPtQvZ9",
+	"mzrYBZih13c4k8XWbR3oqTVGsg17pCiHCmiu2JKZudfmjDOanefv+XOGXr5//+bdiw9vzG+Bcqai0yt8",
+	"q77aDjky472PsfsNrT0VZzs6D2+vuaVf7G5QjDbSdH5Ad67ewLZNZG9Z4zyHPEUly02OB+Mq3gYScuv+",
+	"2H0Q7dq29kAU/XubIru3Stzmnve+Uz2bdgiPb+v7uXNHL/Kmm6Jgm2ZPf8WqVAVgMN2mfoxmH6odcLd5",
+	"KmFExM7wk+5Wjc+IONTdiodV3gQLd/xsBELupYPrdbBD1LJq0sRHZrRNmHrrN0m9bZ6kTv83nxsYm13B",
+	"5FNIvJfDBOlJPi0VGtGXpM2TEGz/sAN/89wh0jwxGDW/G9T8swBH/6xBtjuow9o/D9EfO9lOa0xxXxwj",
+	"/HMoePXPdqSxEaZ4IYGHsu9Cai9yDt5Z5qBwcMEfJXTb6SJ/fvqX0/a/k++fxUJrTjdO8YLEiI0o5640",
+	"kcJxPkWozaca+6ESn2xX2wU01ZrdtbtvZi9g7qufXlxe/f39dZImL87PX58rRf3+/OLHC/3n+eu3r6/1",
+	"X07XtTk0eHUsj+o1dUVxJVZMJmnw8IWSRq0n76xgaj08NxKq9ewlY+sS83XyaSAjobvyB23M+A69bzos",
+	"SbH5/I+VpxgkGSjDWZgIY4WFYnksEBEpgrsMKtmE3hakkDrwpnYGLVI3P+qHwQg3aFHTrLcXuD/f0JIr",
+	"f7kdzB7JX27NNHVzKp6ExkHoH7SSQHZkV/RT98JEiYck7nkIBrHiJkzbBpdQFCDdiWwGrnGYccH1Xq0O",
+	"6Fmvx3hFwRaot3jsC+2YoIshpEEgS9lVTaTNp28sGN9gnmsFkaK6injt3sAOQriOJyaFXqNZNwdGX1sz",
+	"ENlAdr/2rJfkWjuNrdgolETqHZ8VUCRYCXKlRMQKVxVQMRuXnkpoDhJ4SSiW8GoHS1zEX/Q7jPVcgGaD",
+	"oG2zYgJcRMbys7DekXKNDD5YSk7mtYQg7K73LnQ2GsIS8ZrqjMb/hNlyhm5IfpOiG1FrVr4xaSoSqBLH",
+	"4r9SJBgiMkzwyXQebV2peIlJAtKb8HptcchASVi7Zp17/lgMO34hffak7f0i+ugJ3TtSz4JM5qMndo/J",
+	"eBs3qs9beECuhXnlHEtsQK84ZFhCbma+O2dX+m2zR9oC/vePGryPydlHOwcfk/RjoPE/Jme/f0wWBIr8",
+	"qfnbv28Q+pjc39+Pw9vA/POHt7EskbdeNuu3+okcwmZymOaMlfuyNVqmdmwjoDWzwZ78jaggm5lvb1q+",
+	"yLVe5z8x2d4hDSSsdUoqzvI6Mz47pls0lJ3ikDLNI5E6MMl+inUeZFRYDXFiNATkIYTDc70vo3+ImyZn",
+	"9Y/I4bfyYGcqf2DX7bTNXwUxsQEb3b1ijMDM/erm93rDY4O3ffuI2LD8ilWhsjRGiyOi05GMhy9UJv1u",
+	"NZhVhhaEC5mizYpkOgZt88tC2w4RKaBYmCQyY3p5y6sxvFSzM7uMGrYWlzC2k4mp0Ta/YBmiQkSotudb",
+	"NbDKWosn1trQWDxPVTeiZo15XPSYmhg1LgqvvkOztCfT3ceTD5Z4SNQBkzRZseoBYr5laEU2hRqjxBtK",
+	"jDarirXpcLANpc2jVlcl3iJm/lPT6wyiNkEtmeaMFYDpoTs/4bzHeHOIfD1qDbzojt7EEox0HDi1SVte",
+	"tJg4LrU2ycgDOgNZbcG3vQS3JqVTOVXu7IvNFtkf6B0nqQeitm2wwt2HeK7ly4nn6XbbePExPuOxvcEB",
+	"J9l2uxPkeqoptpovg7hqPMlkUgzox4JtBLpsyehJ8Z/QN/dCwiXQ4Ub695SM2la3h2TmHNNsBeKLBpMc",
+	"pBP9EQd7LP3PtMQGmJTZYzqKhWUIXXIQ4pXRuw/QI5/dDzyQuvslQbRjxZhVs+dxZFkwOORnPMTbTYTr",
+	"sVoIWKq51W5tp50cHGWxCZRDVmDebBq2+4ZSQHELwua3GJNTMawJtDXc+iinhjlUxXbiMZ7PddI4Pvdf",
+	"wVljL4dGHzvuiJdBfWLajVYxoxgV0qy2TmDXJ3H50GlrAeqla1VB6IZIppWBsVP1t40b0FIz2gT1YeKW",
+	"s7AITF1UgBTtQPLDfYa+k2VIEvH6206MsYKXRNjdB9qoUn0UR39G2g5SQBl9gKKbTXZ8h2RKCLo5Zbmb",
+	"L+1792li5mUiJ5uPHq7iTD9XEzY8d3R2kEjyk3fYhw+DuSNeAmBisqEjGAdPrZh2F4Ggat2ZYxTCnw6P",
+	"uz/2JAZeA/Up8OhGn+K+Ce4XcBKkd7LLddPjOteEcgZCBevNWUsTZTNAbUFGLE0zQuTAjB3a4qgikT33",
+	"KwgN6iFmtrdZzYubFJH2+za1xZ3Mi55JM37MiTXwnNd24t243tm04XSdMUfWRm0VHO8Cif5Ef5GLJBQo",
+	"9j03pz3IOjcrELrWonzBapo/7F6IPHqOFufN7lfrhoUeZIM3LRjesr8inLHCSh3tQ3Xoiob9N1qYwfSv",
+	"8VT9ickfH0TTjoAzBI7Jtqsgfyd+JuIAD9p/enQP2sdSl6yVBiwkVEJzSZP85Hnki3nRDtqv7fKoA+Ha",
+	"739GO070kWKfh3dk/3NwyC/tfzrAjud//ju6mJpxI8LH8POiQ0lCTY7/5MNXqr8Yqvs83Pjq+go8XEO3",
+	"0e5tiw6Dol61ugA/VEayN8SPO7ZRv3a3W/tZPE8F8f85vzN7sCN4iOf6YFEZhCv0rF1Pkae6AQu9Aa3a",
+	"FgXbeFsgiKNrDnk0mfm1+rPjJYRNyu6fRD0sjdZc76OzyTD6B5tf6d/qghkqYWmultG9603zN3ixxuaV",
+	"A2zGK52NqNaFv5HKcS1QlZaWmkw0c+bHMrcJhilPeFHzAVNxnxvsZI8GvDd2sIJn6HVZyS0izQeIaI88",
+	"vJXG+uI9//dXNj9Rnxzi+QpzImKkNBohcx7TflY4T9yBmqxJ1BiDs0MCDdE3aieR9mGC3mbcjBG5zTsD",
+	"glShFyYL+/O80RB1mKooV7A7Ubub/7VTAAtWwhFF8AE5A5Zx9+ULqNfEIyQL9NZ7YgXnuPE+v984nR9H",
+	"+Iw9pMez1lRfMTbURD/xc+2l9WH7CryMTk5GRApFTYndiaQHmRP22z/WkZxIlsx+jjlEjI1NfIpf+fVZ",
+	"RFkf9eRFRa6A3wL35za/CqF2yCSNEGvxDCkxAfVJou0ICVkRX2GsqTFwo7qFKrAgNN9vg8xx9eF+u+HI",
+	"psIUD7Z5ZwR6X7WBNObc7a5TFISuXx5iXqctw9oK7FZsxeRPOzPcHdMff4jNpWcHrl0QtjEXiu0K29gb",
+	"Lyfr2KsWSaYY9GLoPl11Bai/ijA8MuWWW9ut1IfWYjfu+n3k9saZUxWWxOpNd+1uBRki4VvWB/LdKvfU",
+	"+trtDOgluQWdjm6uUMX6qkS6Nlf63ihUb5zz0c+OtzEAOwUuvoi9Z+xWDwd94d7j7RCPXgHTLg3+bAZk",
+	"RAN8jWUf3L02VV8GdwzOqG3ZOd8/fEXYgXZm2MNh25cCOneR2WNOTSaYYe6SlfYE0ZczUAM4H8FMDUYb",
+	"YayaZITHSdMfJERyQd9ByfjW5Vx9JUbr4RM3wnQdJIZSle27WI5txu4a+guVBRqE6Y9YHGiYwF9fuGHs",
+	"zkVnB2X4SsZJKuGdEz/oqrez+bAIxLgY9cW5s+t2b4A21nVrqzPY5twdxD7Q9P1SG5Y+a0rsKBDVXL/R",
+	"LRLVlaCVS8AalU7gB4ix7CFbqaOFcdVPFGuEsfnucwnj+NCTj/FPzynR30SdpA+6ZXetEf11JBu1Wm0F",
+	"yXARcvXMDXUzVBPBLtyTNXV/HrU8gkNoih/xwB3nOfAobZt6WXsI3BJBo6jcGvnmMctPtJCaQOSR6jTC",
+	"Io+pTjNvmwbScZ+qFC8uL370ZxCGlWbznlGf9jyJq29RsNooaNHSk9rEnqH3tH2Np74bw93iGXF9ishR",
+	"zhdF0Xxujm2XtZCoxDJbHXQRcoD64bfFYBpJWX5Bt18jrBmI3yKUffX66r/fIrirOIjmTn0NMCgHAktT",
+	"TYbXHbtBDYneXrx5jb7LWDmzDbP/+G7PqtYDToAb1EWwu9yoPU6TSiwKzu1rzOaA4LcaFy4ipRCF7sU7",
+	"rt/+hR49l2o8NpTJw2e+4rAgd0emhZCYyyalbxcpwnl+CBFEvTg+HkDzcVjMMg765p+DMYi51NfN5Y/R",
+	"O9QPC0W5j795HA/xOMbd4/wIDofNsvlyvkZz4PBoQanGLpAhV3ZsBIt579Io89bMfyZuHkMKm1Ejk2mQ",
+	"aWayZ840t8aMwUvc2LptAqTNP5R4DajikEFuLgRy2dFdyn05S2G/Qxqs4xZbS3+F8pHd0KEBpybufNX+",
+	"0r+faxSdtsf0ivyOXisdeo+H5ApxDJUUQebpHJRGb8686iTmXr3B1N2QWhe5vSqz4iwDfd/jRhd4UkVF",
+	"dBmDdimRw+v8xe/mGVPr75h318eh2F0WcMJFRzZRwJ/ysJVg3BEPU/ocI0HosthxXZGIwzJNWk6+NOkw",
+	"4G0uvf4qstRai8zUKdwpMD9TCfeGOzZq5W90RWjO6LINDHDOuAZTIe6ROFMJHHOS50CHUy9fFVgMVmDX",
+	"jU4IZ/qHJf/fr68v/RFql1/R2REUOjtNwVMxmtvyepbw39/dKS7/4e6uN4G2ppCbQF1uzV5mqbvNcNG5",
+	"IuX7u7tjHe319ST1vPRFmqIbZDUncqsvNrUOAWAO/EUt9VUnWvGpj8zjBjSledSVpWlC6ILFpY9ktqKx",
+	"vdEuKNhDjXS3sTFJpEa+eeHF5UVQ//IseTo7nZ2qmWYVUFyR5Cx5PjudPdV1d+RKQ/5kCbJVFXMJMrYc",
+	"Zc2p6ABkaKJsc4mJ5jlXoEplEwV7gApcJXw17MpnSv4WjmoKOpRg7Mh/DbK+MMRRE2wLYjWwSGbNQ3vh",
+	"Y9ovH6l0gzSJPOpck2ffXTljet9RAfFbDXzrVOJZI6lE4u6WVVTzRubeDcmRAiK2wSr6JSrMoqk57RIB",
+	"+2Bf2ap0aQ6JmQN4puZT2IpAcWSHLNF6TwPkWeuSl1HKjKrJEy0geTjV3uG7TtFwV//IEy5VEl+XLsMZ",
+	"Z8JeLBGldlM/Oig72l1vK2wvYDWVUXeWRDUVUCMTZ4FsTVw4KT+cnsbpr4ujtuhv66YnZ09jZc4PpOur",
+	"Nnq+JhzcElab8mLptDqwSRwfR8cWSj0eOxCL19d4aW/IDTjK6TSlbYCb+Sz0xQ92qkwNal2fLqs519c4",
+	"d2WOMiB0biFeYtLZ0fiYPF88w3/NZrPZx8ThvQKcA28Qv1ic/MQonLxTAe9d2N9/ShOjaIVRSc9OT9V/",
+	"inK2phiuqoJkWgY/+dVaHU1/I2tl3feCFld1loEQi9rcbmtUVx5VFYoABkMNoiJ8X9f8HYtVb3W4jFSb",
+	"+6VpahMtWyRCpnv3vV5uSr2D0IMPk0/h9fz0+0jMscvWak7VDtESdOndzKhpRqEJmeoVtQO8w+mwH4nv",
+	"J8572y3SlqT+I8jYusUFyTuybO/9o6aniAEVjUBXJjWksQO00CNmaGsy2juAQ4kTlkxguZKOmgJPj0uB",
+	"nymu5YpxdfnUEfEOu521LEttBIU25b8+Kf4WdVlivjX2mLEQQ85MkyeNWfLkd//3/ROtsMtjGHhhFdKO",
+	"kWfvNzBuVhNOMSnV9lo9TSaBKuAl0VtkttYklsEXMWOxiSNPNxuVBtJxgQ52yhLc6dRpiaws5Yjdl4Sz",
+	"bsoeDPPlNzPumxn3zYz7ZsZ9M+O+mXHfzLhvZtywGWeMqMbK0FbdpikKucuGu9KVR4W/TWoJERMDC0Rh",
+	"UxAKJ2rTuyRKV/7j6v1PxpQw1UtNIoswnICRq5PprL12mYrNihUtFg9rBuu6milytTK1zWgLa7rTeO54",
+	"YqtkfEeCG3Rm6jHfornmQbbwWBKBsoIJG2FGuFOdOqg2gwvBjNipgBOWq5zKQhcrWQNUbm1SyMwNsWq3",
+	"35eoD4toOjj11aOGLK76ErbbUUARB9eZMp2Zs4KbciUOAS+AqLdKsHTHeBTckjHEijxFcmiSKGz8RPVN",
+	"6F/aS+hbxHW0WTFu8vV8bbBws5un0bLAEZ9jsDZwjAgdQI5hR00zOO5OaH6g0dEswaj5YejjDogHVeyN",
+	"mEi1alaCynmllfZ5KPyRlUWa/HD6/LiAazL3d8D9dugtJoU9bHYspPyQ3TFSNAe9fWfiBjhbA8395dla",
+	"4ras9QxnK5ioQ/XgrTjI/f3/DgDrf2LliKEAAA==",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
@@ -0,0 +1,61 @@
+package v1
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"knative.dev/backstage-plugins/backends/pkg/util"
+)
+
+var (
+	// sinksGroup is the API group of the sinks of Knative Eventing, e.g. JobSink and IntegrationSink.
+	sinksGroup = "sinks.knative.dev"
+	// kafkaSinkGK is the GroupKind of the KafkaSinks of Knative Eventing Kafka, which are not in the sinks group.
+	kafkaSinkGK = schema.GroupKind{Group: "eventing.knative.dev", Kind: "KafkaSink"}
+)
+
+// isSinkCRD returns true if the CRD defines sinks.
+// Sinks don't have a duck type label of their own, they're the addressables in the sinks group and the KafkaSinks.
+func isSinkCRD(crd *unstructured.Unstructured) bool {
+	gk := crdKind(crd)
+	return gk.Group == sinksGroup || gk == kafkaSinkGK
+}
+
+// filterSinkCRDs returns the CRDs that define sinks.
+func filterSinkCRDs(crds []*unstructured.Unstructured) []*unstructured.Unstructured {
+	sinkCRDs := make([]*unstructured.Unstructured, 0, len(crds))
+	for _, crd := range crds {
+		if isSinkCRD(crd) {
+			sinkCRDs = append(sinkCRDs, crd)
+		}
+	}
+	return sinkCRDs
+}
+
+// sinkKey returns the key of the sink in the maps of the event mesh.
+// key: "<group>/<kind>/<namespace>/<name>"
+func sinkKey(sink *unstructured.Unstructured) string {
+	return util.GKNamespacedName(util.APIVersionToGroup(sink.GetAPIVersion()), sink.GetKind(), sink.GetNamespace(), sink.GetName())
+}
+
+// convertSink converts a sink to a simplified representation that is easier to consume by the Backstage plugin.
+// see Sink.
+func convertSink(sink *unstructured.Unstructured) Sink {
+	converted := Sink{
+		Namespace:   sink.GetNamespace(),
+		Name:        sink.GetName(),
+		UID:         string(sink.GetUID()),
+		Annotations: util.FilterAnnotations(sink.GetAnnotations()),
+		Labels:      sink.GetLabels(),
+		Group:       util.APIVersionToGroup(sink.GetAPIVersion()),
+		Kind:        sink.GetKind(),
+		// these fields will be populated later on, when the triggers and the subscriptions are processed
+		ConsumedEventTypes: []string{},
+		BackstageID:        "",
+		Status:             convertUnstructuredStatus(sink),
+	}
+	if converted.Status != nil {
+		converted.Address = converted.Status.Address
+	}
+	return converted
+}
@@ -2,12 +2,16 @@ package v1
 
 import (
 	"encoding/json"
-	"errors"
+	"fmt"
+	"strings"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"knative.dev/eventing/pkg/apis/eventing"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
 
 	"knative.dev/backstage-plugins/backends/pkg/util"
 )
@@ -19,14 +23,22 @@ type eventTypeEntry struct {
 	Description string `json:"description,omitempty"`
 }
 
-func convertSource(gvr schema.GroupVersionResource, crd unstructured.Unstructured, source *unstructured.Unstructured) (Source, error) {
+// sourceEntry is a converted source along with its sink, which is resolved once all the resources that the source can
+// send the events to are known.
+type sourceEntry struct {
+	*Source
+	// sink is the sink in the spec of the source. nil if the source doesn't have one.
+	sink *duckv1.Destination
+}
+
+func convertSource(gvr schema.GroupVersionResource, crd unstructured.Unstructured, source *unstructured.Unstructured) (sourceEntry, error) {
 	providedEventTypeTypes := []string{}
 
 	crdAnnotations := crd.GetAnnotations()
 	if eventTypesJson, ok := crdAnnotations[eventing.EventTypesAnnotationKey]; ok {
 		var providedEventTypeEntries []eventTypeEntry
 		if err := json.Unmarshal([]byte(eventTypesJson), &providedEventTypeEntries); err != nil {
-			return Source{}, errors.New("failed to unmarshal event types")
+			return sourceEntry{}, fmt.Errorf("failed to unmarshal the event types of CRD %s: %w", crd.GetName(), err)
 		}
 
 		providedEventTypeTypes = make([]string, len(providedEventTypeEntries))
@@ -35,7 +47,7 @@ func convertSource(gvr schema.GroupVersionResource, crd unstructured.Unstructure
 		}
 	}
 
-	src := Source{
+	src := &Source{
 		Namespace:              source.GetNamespace(),
 		Name:                   source.GetName(),
 		UID:                    string(source.GetUID()),
@@ -46,44 +58,82 @@ func convertSource(gvr schema.GroupVersionResource, crd unstructured.Unstructure
 		ProvidedEventTypes: []string{},
 		Group:              gvr.Group,
 		Kind:               source.GetKind(),
+		Status:             convertUnstructuredStatus(source),
 	}
 
-	if sinkRef, ok := getSinkRef(source); ok {
-		src.Sink = &sinkRef
+	sink := getSink(source)
+	if sink != nil {
+		if sink.Ref != nil {
+			// the sink can be in another namespace
+			ref := convertReference(*sink.Ref, source.GetNamespace())
+			src.Sink = &ref
+		} else {
+			src.SinkURI = urlString(sink.URI)
+		}
+	}
+	// the resolved URI is the one that the events are actually sent to
+	if sinkURI, _, _ := unstructured.NestedString(source.Object, "status", "sinkUri"); sinkURI != "" {
+		src.SinkURI = sinkURI
 	}
 
-	return src, nil
+	return sourceEntry{Source: src, sink: sink}, nil
 }
 
-func getSinkRef(u *unstructured.Unstructured) (GroupKindNamespacedName, bool) {
-	stringMap, ok, err := unstructured.NestedStringMap(u.Object, "spec", "sink", "ref")
-	if err != nil {
-		return GroupKindNamespacedName{}, false
+// getSink returns the sink of a source that follows the source duck type.
+// It returns nil if the source doesn't have a sink, or if it can't be parsed.
+func getSink(u *unstructured.Unstructured) *duckv1.Destination {
+	sinkMap, ok, err := unstructured.NestedMap(u.Object, "spec", "sink")
+	if err != nil || !ok {
+		return nil
 	}
 
-	if !ok {
-		return GroupKindNamespacedName{}, false
+	sink := &duckv1.Destination{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(sinkMap, sink); err != nil {
+		return nil
+	}
+	// references without a kind or a name can't be followed, same as the ones without an apiVersion
+	if sink.Ref != nil && (sink.Ref.APIVersion == "" || sink.Ref.Kind == "" || sink.Ref.Name == "") {
+		sink.Ref = nil
+	}
+	if sink.Ref == nil && sink.URI == nil {
+		return nil
+	}
+	return sink
+}
+
+// addressables maps the addresses of the resources that the sources can send the events to, to the resources.
+// map key: the URL of the address, without a trailing slash
+type addressables map[string]GroupKindNamespacedName
+
+// add registers the address of the resource. Empty addresses are ignored.
+func (a addressables) add(address string, ref GroupKindNamespacedName) {
+	if address != "" {
+		a[strings.TrimSuffix(address, "/")] = ref
+	}
+}
+
+// resolve returns the resource that has the address.
+// Addresses that aren't known are resolved to the Kubernetes services by their cluster-local host names, e.g.
+// http://my-service.my-namespace.svc.cluster.local is the address of the service my-service in my-namespace.
+// Addresses with a path aren't, as the path tells the resources apart that share a service, e.g. the brokers behind
+// the broker ingress.
+func (a addressables) resolve(uri string) (GroupKindNamespacedName, bool) {
+	if ref, ok := a[strings.TrimSuffix(uri, "/")]; ok {
+		return ref, true
 	}
 
-	apiVersion, ok := stringMap["apiVersion"]
-	if !ok {
-		// if apiVersion is not present (e.g. using a URL as the sink), we don't care/
-		// same story with others
+	u, err := apis.ParseURL(uri)
+	if err != nil || u == nil || strings.Trim(u.Path, "/") != "" {
 		return GroupKindNamespacedName{}, false
 	}
-	kind, ok := stringMap["kind"]
-	if !ok {
+	// the cluster domain is not always cluster.local
+	host, domain, ok := strings.Cut(u.URL().Hostname(), ".svc")
+	if !ok || (domain != "" && !strings.HasPrefix(domain, ".")) {
 		return GroupKindNamespacedName{}, false
 	}
-	name, ok := stringMap["name"]
-	if !ok {
+	name, namespace, ok := strings.Cut(host, ".")
+	if !ok || name == "" || namespace == "" || strings.Contains(namespace, ".") {
 		return GroupKindNamespacedName{}, false
 	}
-
-	return GroupKindNamespacedName{
-		Group:     util.APIVersionToGroup(apiVersion),
-		Kind:      kind,
-		Namespace: u.GetNamespace(),
-		Name:      name,
-	}, true
+	return GroupKindNamespacedName{Kind: "Service", Namespace: namespace, Name: name}, true
 }
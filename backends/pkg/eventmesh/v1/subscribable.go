@@ -17,5 +17,6 @@ func convertSubscribable(gvr schema.GroupVersionResource, u *unstructured.Unstru
 		Kind:        u.GetKind(),
 		// this field will be populated later on
 		ProvidedEventTypes: []string{},
+		Status:             convertUnstructuredStatus(u),
 	}
 }
@@ -0,0 +1,33 @@
+package v1
+
+import (
+	messagingv1 "knative.dev/eventing/pkg/apis/messaging/v1"
+)
+
+// convertSubscription converts a Knative Messaging Subscription to a simplified representation that is easier to consume by the Backstage plugin.
+// see Subscription.
+func convertSubscription(subscription *messagingv1.Subscription) Subscription {
+	physicalSubscription := subscription.Status.PhysicalSubscription
+
+	converted := Subscription{
+		Namespace:     subscription.Namespace,
+		Name:          subscription.Name,
+		UID:           string(subscription.UID),
+		Channel:       convertReference(subscription.Spec.Channel, subscription.Namespace),
+		SubscriberURI: urlString(physicalSubscription.SubscriberURI),
+		ReplyURI:      urlString(physicalSubscription.ReplyURI),
+		Delivery:      convertDelivery(subscription.Spec.Delivery, physicalSubscription.DeliveryStatus, subscription.Namespace),
+		Conditions:    convertConditions(subscription.Status.Conditions),
+		// this field will be populated later on, when the subscriber is fetched
+		BackstageID: "",
+	}
+	if subscription.Spec.Subscriber != nil {
+		subscriber := convertDestination(*subscription.Spec.Subscriber, subscription.Namespace)
+		converted.Subscriber = &subscriber
+	}
+	if subscription.Spec.Reply != nil {
+		reply := convertDestination(*subscription.Spec.Reply, subscription.Namespace)
+		converted.Reply = &reply
+	}
+	return converted
+}
@@ -0,0 +1,36 @@
+package v1
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"knative.dev/pkg/observability/attributekey"
+)
+
+var (
+	// eventMeshCachedAttr is whether the event mesh was served from the cache rather than built for the request.
+	eventMeshCachedAttr = attributekey.Bool("kn.eventmesh.cached")
+	// triggerNameAttr is the name of the trigger that's processed.
+	triggerNameAttr = attributekey.String("kn.eventmesh.trigger.name")
+	// subscriptionNameAttr is the name of the subscription that's processed.
+	subscriptionNameAttr = attributekey.String("kn.eventmesh.subscription.name")
+	// subscribersAttr is the number of the subscribers that are looked up.
+	subscribersAttr = attributekey.Int("kn.eventmesh.subscribers")
+)
+
+// startSpan starts a span with the global tracer provider. The tracer provider is set up by the observability of
+// knative.dev/pkg, the spans go nowhere until then.
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(scopeName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// recordSpanError marks the span as failed with the error. The errors that are turned into the warnings of the event
+// mesh are recorded as well, the event mesh is incomplete because of them.
+func recordSpanError(span trace.Span, err error) {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
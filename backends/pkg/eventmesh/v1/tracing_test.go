@@ -0,0 +1,98 @@
+package v1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"go.uber.org/zap"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	messagingv1 "knative.dev/eventing/pkg/apis/messaging/v1"
+	fakeclientset "knative.dev/eventing/pkg/client/clientset/versioned/fake"
+	testingv1 "knative.dev/eventing/pkg/reconciler/testing/v1"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+func TestBuildEventMeshSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() {
+		otel.SetTracerProvider(noop.NewTracerProvider())
+	})
+
+	objects := []runtime.Object{
+		testingv1.NewBroker("test-broker", "test-ns"),
+		testingv1.NewTrigger("test-trigger", "test-ns", "test-broker",
+			WithTriggerSubscriber(reference("v1", "Service", "test-ns", "test-subscriber")),
+		),
+		&messagingv1.Subscription{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-subscription", Namespace: "test-ns"},
+			Spec: messagingv1.SubscriptionSpec{
+				Channel:    duckv1.KReference{APIVersion: "messaging.knative.dev/v1", Kind: "InMemoryChannel", Name: "test-imc"},
+				Subscriber: &duckv1.Destination{Ref: reference("v1", "Service", "test-ns", "test-subscriber")},
+			},
+		},
+	}
+
+	sc := runtime.NewScheme()
+	_ = corev1.AddToScheme(sc)
+	_ = eventingv1.AddToScheme(sc)
+	_ = apiextensionsv1.AddToScheme(sc)
+
+	lister := &clientLister{
+		clientset:     fakeclientset.NewSimpleClientset(objects...),
+		dynamicClient: dynamicfake.NewSimpleDynamicClient(sc, backstageService("test-subscriber")),
+	}
+
+	ctx, parent := otel.Tracer("test").Start(context.TODO(), "test")
+	if _, err := buildEventMesh(ctx, lister, nil, nil, nil, zap.NewNop().Sugar()); err != nil {
+		t.Fatalf("buildEventMesh() error = %v", err)
+	}
+	parent.End()
+
+	// the names of the spans are mapped to the names of their parents
+	names := make(map[trace.SpanID]string)
+	for _, span := range recorder.Ended() {
+		names[span.SpanContext().SpanID()] = span.Name()
+	}
+	got := make(map[string]string)
+	for _, span := range recorder.Ended() {
+		if span.Name() == "test" {
+			continue
+		}
+		got[span.Name()] = names[span.Parent().SpanID()]
+	}
+
+	want := map[string]string{
+		"buildEventMesh":           "test",
+		"fetchBrokers":             "buildEventMesh",
+		"fetchSubscribables":       "buildEventMesh",
+		"fetchSources":             "buildEventMesh",
+		"fetchSinks":               "buildEventMesh",
+		"fetchEventTypes":          "buildEventMesh",
+		"fetchEventTypeAttributes": "buildEventMesh",
+		"listSequences":            "buildEventMesh",
+		"listParallels":            "buildEventMesh",
+		"listTriggers":             "buildEventMesh",
+		"listSubscriptions":        "buildEventMesh",
+		"prefetchBackstageIDs":     "buildEventMesh",
+		"fetchSubscribers":         "prefetchBackstageIDs",
+		"processTrigger":           "buildEventMesh",
+		"processSubscription":      "buildEventMesh",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Error("Spans and their parents (-want, +got):", diff)
+	}
+}
@@ -0,0 +1,155 @@
+package v1
+
+import (
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"knative.dev/pkg/observability/attributekey"
+	"knative.dev/pkg/observability/semconv"
+)
+
+const (
+	// apiResourceDiscovery is the resource of the calls to the discovery API, e.g. /apis/eventing.knative.dev/v1.
+	apiResourceDiscovery = "discovery"
+	// apiResourceNone is the resource of the other calls that aren't made to a resource, e.g. /version.
+	apiResourceNone = "none"
+)
+
+var (
+	// apiResourceAttr is the resource that a call to the API server is made to, e.g. "brokers.eventing.knative.dev".
+	apiResourceAttr = attributekey.String("kn.eventmesh.k8s.resource")
+	// apiVerbAttr is the verb of the call to the API server, e.g. "list".
+	apiVerbAttr = attributekey.String("kn.eventmesh.k8s.verb")
+)
+
+// transportMetrics are the instruments of the calls to the API server, see meshMetrics.
+type transportMetrics struct {
+	apiCalls  metric.Int64Counter
+	apiErrors metric.Int64Counter
+}
+
+// newTransportMetrics creates the instruments of the calls to the API server with the meter.
+func newTransportMetrics(meter metric.Meter) transportMetrics {
+	var (
+		m   transportMetrics
+		err error
+	)
+	m.apiCalls, err = meter.Int64Counter(
+		"kn.eventmesh.k8s.calls",
+		metric.WithDescription("The number of calls to the Kubernetes API server."),
+		metric.WithUnit("{call}"),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	m.apiErrors, err = meter.Int64Counter(
+		"kn.eventmesh.k8s.errors",
+		metric.WithDescription("The number of calls to the Kubernetes API server that failed, or that were answered with an error status."),
+		metric.WithUnit("{call}"),
+	)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// instrumentedTransport records the calls that are made through it to the API server per resource.
+type instrumentedTransport struct {
+	next http.RoundTripper
+}
+
+var _ http.RoundTripper = &instrumentedTransport{}
+
+// InstrumentTransport wraps the transport of the clients of the API server, so that the calls are recorded in the
+// metrics and traced. It's meant for rest.Config.Wrap, which the copies of the config keep, so that all the clients
+// are covered.
+func InstrumentTransport(next http.RoundTripper) http.RoundTripper {
+	return &instrumentedTransport{next: otelhttp.NewTransport(next, otelhttp.WithSpanNameFormatter(apiSpanName))}
+}
+
+func (t *instrumentedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resource, verb := apiRequestInfo(req)
+	attrs := []attribute.KeyValue{apiResourceAttr.With(resource), apiVerbAttr.With(verb)}
+
+	resp, err := t.next.RoundTrip(req)
+	if resp != nil {
+		attrs = append(attrs, semconv.HTTPResponseStatusCode(resp.StatusCode))
+	}
+
+	ctx := req.Context()
+	opt := metric.WithAttributes(attrs...)
+	m := instruments.Load()
+	m.apiCalls.Add(ctx, 1, opt)
+	if err != nil || resp.StatusCode >= http.StatusBadRequest {
+		m.apiErrors.Add(ctx, 1, opt)
+	}
+	return resp, err
+}
+
+// apiSpanName names the spans of the calls to the API server after their verb and resource,
+// e.g. "list brokers.eventing.knative.dev".
+func apiSpanName(_ string, req *http.Request) string {
+	resource, verb := apiRequestInfo(req)
+	return verb + " " + resource
+}
+
+// apiRequestInfo returns the resource and the verb of the call to the API server, from the path and the method of the
+// request. The paths are of the form /api/v1/[namespaces/<namespace>/]<resource>[/<name>[/<subresource>]] for the
+// core group and /apis/<group>/<version>/... for the others.
+func apiRequestInfo(req *http.Request) (string, string) {
+	parts := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+
+	var group string
+	switch {
+	case parts[0] == "api" && len(parts) >= 2:
+		parts = parts[2:]
+	case parts[0] == "apis" && len(parts) >= 3:
+		group = parts[1]
+		parts = parts[3:]
+	case parts[0] == "api" || parts[0] == "apis":
+		return apiResourceDiscovery, "get"
+	default:
+		return apiResourceNone, strings.ToLower(req.Method)
+	}
+	if len(parts) == 0 {
+		return apiResourceDiscovery, "get"
+	}
+
+	// the namespaces are resources themselves, as well as the scope of the namespaced resources
+	if parts[0] == "namespaces" && len(parts) >= 3 {
+		parts = parts[2:]
+	}
+	resource := schema.GroupResource{Group: group, Resource: parts[0]}.String()
+	named := len(parts) >= 2
+
+	switch req.Method {
+	case http.MethodGet:
+		if req.URL.Query().Get("watch") == "true" {
+			return resource, "watch"
+		}
+		if named {
+			return resource, "get"
+		}
+		return resource, "list"
+	case http.MethodPost:
+		return resource, "create"
+	case http.MethodPut:
+		return resource, "update"
+	case http.MethodPatch:
+		return resource, "patch"
+	case http.MethodDelete:
+		if named {
+			return resource, "delete"
+		}
+		return resource, "deletecollection"
+	default:
+		return resource, strings.ToLower(req.Method)
+	}
+}
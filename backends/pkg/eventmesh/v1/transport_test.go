@@ -0,0 +1,163 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestAPIRequestInfo(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		url          string
+		wantResource string
+		wantVerb     string
+	}{
+		{
+			name:         "A list of a core resource",
+			method:       http.MethodGet,
+			url:          "/api/v1/namespaces/test-ns/services",
+			wantResource: "services",
+			wantVerb:     "list",
+		},
+		{
+			name:         "A get of a core resource",
+			method:       http.MethodGet,
+			url:          "/api/v1/namespaces/test-ns/services/test-service",
+			wantResource: "services",
+			wantVerb:     "get",
+		},
+		{
+			name:         "A get of a namespace",
+			method:       http.MethodGet,
+			url:          "/api/v1/namespaces/test-ns",
+			wantResource: "namespaces",
+			wantVerb:     "get",
+		},
+		{
+			name:         "A list of a resource in all the namespaces",
+			method:       http.MethodGet,
+			url:          "/apis/eventing.knative.dev/v1/brokers?limit=500",
+			wantResource: "brokers.eventing.knative.dev",
+			wantVerb:     "list",
+		},
+		{
+			name:         "A get of a subresource",
+			method:       http.MethodGet,
+			url:          "/apis/eventing.knative.dev/v1/namespaces/test-ns/brokers/test-broker/status",
+			wantResource: "brokers.eventing.knative.dev",
+			wantVerb:     "get",
+		},
+		{
+			name:         "A watch of a resource",
+			method:       http.MethodGet,
+			url:          "/apis/eventing.knative.dev/v1/triggers?watch=true&resourceVersion=1",
+			wantResource: "triggers.eventing.knative.dev",
+			wantVerb:     "watch",
+		},
+		{
+			name:         "A review of the access",
+			method:       http.MethodPost,
+			url:          "/apis/authorization.k8s.io/v1/selfsubjectaccessreviews",
+			wantResource: "selfsubjectaccessreviews.authorization.k8s.io",
+			wantVerb:     "create",
+		},
+		{
+			name:         "A discovery of the groups",
+			method:       http.MethodGet,
+			url:          "/apis",
+			wantResource: apiResourceDiscovery,
+			wantVerb:     "get",
+		},
+		{
+			name:         "A discovery of the resources of a group",
+			method:       http.MethodGet,
+			url:          "/apis/eventing.knative.dev/v1",
+			wantResource: apiResourceDiscovery,
+			wantVerb:     "get",
+		},
+		{
+			name:         "A call that's not made to a resource",
+			method:       http.MethodGet,
+			url:          "/version",
+			wantResource: apiResourceNone,
+			wantVerb:     "get",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resource, verb := apiRequestInfo(httptest.NewRequest(tt.method, tt.url, nil))
+			if resource != tt.wantResource || verb != tt.wantVerb {
+				t.Errorf("apiRequestInfo() = (%q, %q), want (%q, %q)", resource, verb, tt.wantResource, tt.wantVerb)
+			}
+		})
+	}
+}
+
+func TestInstrumentTransport(t *testing.T) {
+	reader := setupTestMeterProvider(t)
+
+	transport := InstrumentTransport(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		switch req.URL.Path {
+		case "/api/v1/namespaces/test-ns/services/test-service":
+			return &http.Response{StatusCode: http.StatusOK}, nil
+		case "/api/v1/namespaces/test-ns/services/other-service":
+			return &http.Response{StatusCode: http.StatusForbidden}, nil
+		default:
+			return nil, errors.New("connection refused")
+		}
+	}))
+
+	for _, url := range []string{
+		"/api/v1/namespaces/test-ns/services/test-service",
+		"/api/v1/namespaces/test-ns/services/test-service",
+		"/api/v1/namespaces/test-ns/services/other-service",
+		"/apis/eventing.knative.dev/v1/brokers",
+	} {
+		_, _ = transport.RoundTrip(httptest.NewRequest(http.MethodGet, url, nil))
+	}
+
+	wantCalls := map[string]int64{
+		"services/get/200":                   2,
+		"services/get/403":                   1,
+		"brokers.eventing.knative.dev/list/": 1,
+	}
+	if diff := cmp.Diff(wantCalls, apiCallCounts(t, reader, "kn.eventmesh.k8s.calls")); diff != "" {
+		t.Error("Calls (-want, +got):", diff)
+	}
+
+	wantErrors := map[string]int64{
+		"services/get/403":                   1,
+		"brokers.eventing.knative.dev/list/": 1,
+	}
+	if diff := cmp.Diff(wantErrors, apiCallCounts(t, reader, "kn.eventmesh.k8s.errors")); diff != "" {
+		t.Error("Errors (-want, +got):", diff)
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// apiCallCounts returns the recorded calls to the API server, keyed by "<resource>/<verb>/<status code>".
+func apiCallCounts(t *testing.T, reader sdkmetric.Reader, name string) map[string]int64 {
+	t.Helper()
+
+	counts := make(map[string]int64)
+	for _, dp := range collectMetric(t, reader, name).(metricdata.Sum[int64]).DataPoints {
+		resource, _ := dp.Attributes.Value("kn.eventmesh.k8s.resource")
+		verb, _ := dp.Attributes.Value("kn.eventmesh.k8s.verb")
+		code, _ := dp.Attributes.Value("http.response.status_code")
+		counts[resource.Emit()+"/"+verb.Emit()+"/"+code.Emit()] = dp.Value
+	}
+	return counts
+}
@@ -0,0 +1,81 @@
+package v1
+
+import (
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+
+	"knative.dev/backstage-plugins/backends/pkg/util"
+)
+
+// convertTrigger converts a Knative Eventing Trigger to a simplified representation that is easier to consume by the Backstage plugin.
+// see Trigger.
+func convertTrigger(trigger *eventingv1.Trigger) Trigger {
+	converted := Trigger{
+		Namespace:     trigger.Namespace,
+		Name:          trigger.Name,
+		UID:           string(trigger.UID),
+		Broker:        triggerBrokerReference(trigger),
+		Filters:       convertSubscriptionsAPIFilters(trigger.Spec.Filters),
+		Subscriber:    convertDestination(trigger.Spec.Subscriber, trigger.Namespace),
+		SubscriberURI: urlString(trigger.Status.SubscriberURI),
+		Delivery:      convertDelivery(trigger.Spec.Delivery, trigger.Status.DeliveryStatus, trigger.Namespace),
+		Conditions:    convertConditions(trigger.Status.Conditions),
+		// this field will be populated later on, when the subscriber is fetched
+		BackstageID: "",
+	}
+	if trigger.Spec.Filter != nil && len(trigger.Spec.Filter.Attributes) > 0 {
+		converted.Filter = trigger.Spec.Filter.Attributes
+	}
+	return converted
+}
+
+// triggerBrokerReference returns the reference to the broker of the trigger.
+// The broker is either given by its name in the namespace of the trigger, or by a reference.
+func triggerBrokerReference(trigger *eventingv1.Trigger) GroupKindNamespacedName {
+	ref := GroupKindNamespacedName{
+		Group:     eventingv1.SchemeGroupVersion.Group,
+		Kind:      "Broker",
+		Namespace: trigger.Namespace,
+		Name:      trigger.Spec.Broker,
+	}
+	if brokerRef := trigger.Spec.BrokerRef; brokerRef != nil {
+		if brokerRef.APIVersion != "" {
+			ref.Group = util.APIVersionToGroup(brokerRef.APIVersion)
+		}
+		if brokerRef.Kind != "" {
+			ref.Kind = brokerRef.Kind
+		}
+		if brokerRef.Namespace != "" {
+			ref.Namespace = brokerRef.Namespace
+		}
+		ref.Name = brokerRef.Name
+	}
+	return ref
+}
+
+func convertSubscriptionsAPIFilters(filters []eventingv1.SubscriptionsAPIFilter) []SubscriptionsAPIFilter {
+	if len(filters) == 0 {
+		return nil
+	}
+
+	converted := make([]SubscriptionsAPIFilter, 0, len(filters))
+	for _, f := range filters {
+		converted = append(converted, convertSubscriptionsAPIFilter(f))
+	}
+	return converted
+}
+
+func convertSubscriptionsAPIFilter(filter eventingv1.SubscriptionsAPIFilter) SubscriptionsAPIFilter {
+	converted := SubscriptionsAPIFilter{
+		All:    convertSubscriptionsAPIFilters(filter.All),
+		Any:    convertSubscriptionsAPIFilters(filter.Any),
+		Exact:  filter.Exact,
+		Prefix: filter.Prefix,
+		Suffix: filter.Suffix,
+		CESQL:  filter.CESQL,
+	}
+	if filter.Not != nil {
+		not := convertSubscriptionsAPIFilter(*filter.Not)
+		converted.Not = &not
+	}
+	return converted
+}
@@ -0,0 +1,57 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// warnings collects the problems that are encountered while building the event mesh.
+// Instead of failing the whole event mesh, the parts that can't be built are skipped and reported as warnings.
+type warnings struct {
+	mu    sync.Mutex
+	items []Warning
+}
+
+// add records a warning about a single resource.
+func (w *warnings) add(gk schema.GroupKind, namespace, name string, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.items = append(w.items, Warning{
+		Group:       gk.Group,
+		Kind:        gk.Kind,
+		Namespace:   namespace,
+		Name:        name,
+		Reason:      err.Error(),
+		StatusClass: statusClass(err),
+	})
+}
+
+// addKind records a warning about a kind of resources, e.g. when they can't be listed.
+func (w *warnings) addKind(gk schema.GroupKind, err error) {
+	w.add(gk, "", "", err)
+}
+
+// list returns the collected warnings, or nil if there are none.
+func (w *warnings) list() []Warning {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.items
+}
+
+// statusClass returns the class of the HTTP status of the API error, e.g. 4xx, or an empty string if the error is
+// not an API error.
+func statusClass(err error) string {
+	var status apierrors.APIStatus
+	if !errors.As(err, &status) {
+		return ""
+	}
+	code := status.Status().Code
+	if code == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%dxx", code/100)
+}
@@ -0,0 +1,293 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"time"
+
+	"knative.dev/backstage-plugins/backends/pkg/eventmesh/auth"
+	"knative.dev/backstage-plugins/backends/pkg/util"
+
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	eventingv1beta2 "knative.dev/eventing/pkg/apis/eventing/v1beta2"
+	flowsv1 "knative.dev/eventing/pkg/apis/flows/v1"
+	messagingv1 "knative.dev/eventing/pkg/apis/messaging/v1"
+)
+
+// watchBookmarkInterval is how often a BOOKMARK is sent when the event mesh doesn't change, so that the proxies in
+// between don't close the idle connections.
+const watchBookmarkInterval = 30 * time.Second
+
+func (e Endpoint) WatchEventMesh(ctx context.Context, request WatchEventMeshRequestObject) (WatchEventMeshResponseObject, error) {
+	authToken, ok := auth.GetAuthToken(ctx)
+	if !ok {
+		return WatchEventMesh401JSONResponse{
+			Error: "Authorization header is missing",
+		}, nil
+	}
+
+	// the changes are only known from the cache, the event mesh that's built on every request has no history
+	if e.cache == nil || e.authorizer == nil {
+		return WatchEventMesh503JSONResponse{
+			Error: "Watching the event mesh is not available, the event mesh cache is not enabled",
+		}, nil
+	}
+
+	return watchEventMeshStream{
+		ctx:             ctx,
+		endpoint:        e,
+		authToken:       authToken,
+		namespaces:      request.Params.Namespaces,
+		resourceVersion: request.Params.ResourceVersion,
+	}, nil
+}
+
+// watchEventMeshStream streams the changes of the cached event mesh that the caller is allowed to see, until the
+// request is done.
+type watchEventMeshStream struct {
+	ctx             context.Context
+	endpoint        Endpoint
+	authToken       string
+	namespaces      []string
+	resourceVersion string
+}
+
+var _ WatchEventMeshResponseObject = watchEventMeshStream{}
+
+func (s watchEventMeshStream) VisitWatchEventMeshResponse(w http.ResponseWriter) error {
+	logger := s.endpoint.logger
+	cache := s.endpoint.cache
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	encoder := json.NewEncoder(w)
+	controller := http.NewResponseController(w)
+	send := func(events []EventMeshWatchEvent) error {
+		for i := range events {
+			if err := encoder.Encode(events[i]); err != nil {
+				return err
+			}
+		}
+		return controller.Flush()
+	}
+
+	// wait for the event mesh to be built for the first time
+	snapshot, updated := cache.watch()
+	for snapshot == nil {
+		select {
+		case <-s.ctx.Done():
+			return nil
+		case <-updated:
+			snapshot, updated = cache.watch()
+		}
+	}
+
+	current, err := s.endpoint.filterSnapshot(s.ctx, s.authToken, snapshot, s.namespaces)
+	if err != nil {
+		// the headers are sent already, end the stream so that the caller reconnects
+		logger.Errorw("Error reviewing access to the watched event mesh", "error", err)
+		return nil
+	}
+
+	var events []EventMeshWatchEvent
+	if since := s.sinceSnapshot(cache.snapshotAt(s.resourceVersion)); since != nil {
+		events = append(diffEventMesh(*since, current, snapshot.resourceVersion), bookmarkEvent(snapshot.resourceVersion))
+	} else {
+		events = []EventMeshWatchEvent{snapshotEvent(current, snapshot.resourceVersion)}
+	}
+	if err := send(events); err != nil {
+		logger.Debugw("Event mesh watcher is gone", "error", err)
+		return nil
+	}
+
+	bookmarks := time.NewTicker(watchBookmarkInterval)
+	defer bookmarks.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return nil
+		case <-bookmarks.C:
+			events = []EventMeshWatchEvent{bookmarkEvent(snapshot.resourceVersion)}
+		case <-updated:
+			snapshot, updated = cache.watch()
+
+			// the access is reviewed again, the caller may have lost or gained permissions in the meantime
+			next, err := s.endpoint.filterSnapshot(s.ctx, s.authToken, snapshot, s.namespaces)
+			if err != nil {
+				logger.Errorw("Error reviewing access to the watched event mesh", "error", err)
+				return nil
+			}
+			events = append(diffEventMesh(current, next, snapshot.resourceVersion), bookmarkEvent(snapshot.resourceVersion))
+			current = next
+		}
+
+		if err := send(events); err != nil {
+			logger.Debugw("Event mesh watcher is gone", "error", err)
+			return nil
+		}
+	}
+}
+
+// sinceSnapshot returns the event mesh that the caller has seen at the snapshot it resumes from, or nil if there's
+// no such snapshot or the access to it can't be reviewed.
+func (s watchEventMeshStream) sinceSnapshot(snapshot *meshSnapshot) *EventMesh {
+	if snapshot == nil {
+		return nil
+	}
+	eventMesh, err := s.endpoint.filterSnapshot(s.ctx, s.authToken, snapshot, s.namespaces)
+	if err != nil {
+		s.endpoint.logger.Errorw("Error reviewing access to the event mesh the watcher resumes from", "error", err)
+		return nil
+	}
+	return &eventMesh
+}
+
+func snapshotEvent(eventMesh EventMesh, resourceVersion string) EventMeshWatchEvent {
+	return EventMeshWatchEvent{
+		Type:            WatchEventSnapshot,
+		ResourceVersion: resourceVersion,
+		EventMesh:       &eventMesh,
+	}
+}
+
+func bookmarkEvent(resourceVersion string) EventMeshWatchEvent {
+	return EventMeshWatchEvent{
+		Type:            WatchEventBookmark,
+		ResourceVersion: resourceVersion,
+	}
+}
+
+// diffEventMesh returns the events that turn one event mesh into the other.
+// For every kind of objects, the deleted objects come first in their order in the first event mesh, then the added
+// and the modified ones in their order in the second one.
+func diffEventMesh(from, to EventMesh, resourceVersion string) []EventMeshWatchEvent {
+	events := make([]EventMeshWatchEvent, 0)
+
+	events = diffObjects(events, resourceVersion, WatchKindBroker, from.Brokers, to.Brokers,
+		func(br *Broker) string {
+			return util.GKNamespacedName(eventingv1.SchemeGroupVersion.Group, "Broker", br.Namespace, br.Name)
+		},
+		func(e *EventMeshWatchEvent, br *Broker) { e.Broker = br })
+
+	events = diffObjects(events, resourceVersion, WatchKindEventType, from.EventTypes, to.EventTypes,
+		func(et *EventType) string {
+			return util.GKNamespacedName(eventingv1beta2.SchemeGroupVersion.Group, "EventType", et.Namespace, et.Name)
+		},
+		func(e *EventMeshWatchEvent, et *EventType) { e.EventType = et })
+
+	events = diffObjects(events, resourceVersion, WatchKindSubscribable, from.Subscribables, to.Subscribables,
+		func(sb *Subscribable) string {
+			return util.GKNamespacedName(sb.Group, sb.Kind, sb.Namespace, sb.Name)
+		},
+		func(e *EventMeshWatchEvent, sb *Subscribable) { e.Subscribable = sb })
+
+	events = diffObjects(events, resourceVersion, WatchKindSource, from.Sources, to.Sources,
+		func(src *Source) string {
+			return util.GKNamespacedName(src.Group, src.Kind, src.Namespace, src.Name)
+		},
+		func(e *EventMeshWatchEvent, src *Source) { e.Source = src })
+
+	events = diffObjects(events, resourceVersion, WatchKindSink, from.Sinks, to.Sinks,
+		func(sink *Sink) string {
+			return util.GKNamespacedName(sink.Group, sink.Kind, sink.Namespace, sink.Name)
+		},
+		func(e *EventMeshWatchEvent, sink *Sink) { e.Sink = sink })
+
+	events = diffObjects(events, resourceVersion, WatchKindSequence, from.Sequences, to.Sequences,
+		func(sq *Sequence) string {
+			return util.GKNamespacedName(flowsv1.SchemeGroupVersion.Group, "Sequence", sq.Namespace, sq.Name)
+		},
+		func(e *EventMeshWatchEvent, sq *Sequence) { e.Sequence = sq })
+
+	events = diffObjects(events, resourceVersion, WatchKindParallel, from.Parallels, to.Parallels,
+		func(p *Parallel) string {
+			return util.GKNamespacedName(flowsv1.SchemeGroupVersion.Group, "Parallel", p.Namespace, p.Name)
+		},
+		func(e *EventMeshWatchEvent, p *Parallel) { e.Parallel = p })
+
+	events = diffObjects(events, resourceVersion, WatchKindTrigger, from.Triggers, to.Triggers,
+		func(t *Trigger) string {
+			return util.GKNamespacedName(eventingv1.SchemeGroupVersion.Group, "Trigger", t.Namespace, t.Name)
+		},
+		func(e *EventMeshWatchEvent, t *Trigger) { e.Trigger = t })
+
+	events = diffObjects(events, resourceVersion, WatchKindSubscription, from.Subscriptions, to.Subscriptions,
+		func(sub *Subscription) string {
+			return util.GKNamespacedName(messagingv1.SchemeGroupVersion.Group, "Subscription", sub.Namespace, sub.Name)
+		},
+		func(e *EventMeshWatchEvent, sub *Subscription) { e.Subscription = sub })
+
+	events = diffObjects(events, resourceVersion, WatchKindConsumer, consumerEdges(from.EventTypes), consumerEdges(to.EventTypes),
+		consumerEdgeKey,
+		func(e *EventMeshWatchEvent, edge *ConsumerEdge) { e.Consumer = edge })
+
+	return events
+}
+
+// diffObjects appends the events for the objects of a kind that are deleted, added or modified.
+// key identifies the objects across the event meshes and set puts the object into the event.
+func diffObjects[T any](events []EventMeshWatchEvent, resourceVersion string, kind EventMeshWatchEventKind, from, to []T, key func(*T) string, set func(*EventMeshWatchEvent, *T)) []EventMeshWatchEvent {
+	oldByKey := make(map[string]*T, len(from))
+	for i := range from {
+		oldByKey[key(&from[i])] = &from[i]
+	}
+	newKeys := make(map[string]bool, len(to))
+	for i := range to {
+		newKeys[key(&to[i])] = true
+	}
+
+	appendEvent := func(eventType EventMeshWatchEventType, k string, obj *T) {
+		event := EventMeshWatchEvent{
+			Type:            eventType,
+			ResourceVersion: resourceVersion,
+			Kind:            kind,
+			Key:             k,
+		}
+		set(&event, obj)
+		events = append(events, event)
+	}
+
+	for i := range from {
+		if k := key(&from[i]); !newKeys[k] {
+			appendEvent(WatchEventDeleted, k, &from[i])
+		}
+	}
+	for i := range to {
+		k := key(&to[i])
+		prev, ok := oldByKey[k]
+		if !ok {
+			appendEvent(WatchEventAdded, k, &to[i])
+		} else if !reflect.DeepEqual(*prev, to[i]) {
+			appendEvent(WatchEventModified, k, &to[i])
+		}
+	}
+	return events
+}
+
+// consumerEdges returns the connections of the event types to their consumers.
+func consumerEdges(eventTypes []EventType) []ConsumerEdge {
+	edges := make([]ConsumerEdge, 0)
+	for _, et := range eventTypes {
+		for _, consumer := range et.Consumers {
+			edges = append(edges, ConsumerEdge{
+				EventType: et.NamespacedName(),
+				Consumer:  consumer,
+			})
+		}
+	}
+	return edges
+}
+
+// consumerEdgeKey identifies a consumer edge by the event type, the consumer and the hop the events reach it through.
+func consumerEdgeKey(edge *ConsumerEdge) string {
+	key := edge.EventType + "/" + edge.Consumer.BackstageID
+	if edge.Consumer.Hop != nil {
+		key += "/" + edge.Consumer.Hop.String()
+	}
+	return key
+}
@@ -0,0 +1,167 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+)
+
+func TestDiffEventMesh(t *testing.T) {
+	broker := Broker{Namespace: "test-ns", Name: "test-broker", ProvidedEventTypes: []string{}}
+	eventType := EventType{Namespace: "test-ns", Name: "test-et", Type: "test-type", ConsumedBy: []string{}}
+	consumedEventType := EventType{
+		Namespace:  "test-ns",
+		Name:       "test-et",
+		Type:       "test-type",
+		ConsumedBy: []string{"test-consumer"},
+		Consumers: []EventTypeConsumer{
+			{BackstageID: "test-consumer", Hop: &GroupKindNamespacedName{Kind: "Service", Namespace: "test-ns", Name: "test-svc"}},
+		},
+	}
+	source := Source{Group: "sources.knative.dev", Kind: "PingSource", Namespace: "test-ns", Name: "test-src"}
+
+	tests := []struct {
+		name string
+		from EventMesh
+		to   EventMesh
+		want []EventMeshWatchEvent
+	}{
+		{
+			name: "No changes",
+			from: EventMesh{Brokers: []Broker{broker}, EventTypes: []EventType{eventType}},
+			to:   EventMesh{Brokers: []Broker{broker}, EventTypes: []EventType{eventType}},
+			want: []EventMeshWatchEvent{},
+		},
+		{
+			name: "Added, modified and deleted objects",
+			from: EventMesh{
+				Brokers: []Broker{broker},
+				Sources: []Source{source},
+			},
+			to: EventMesh{
+				Brokers:    []Broker{{Namespace: "test-ns", Name: "test-broker", ProvidedEventTypes: []string{"test-ns/test-et"}}},
+				EventTypes: []EventType{eventType},
+			},
+			want: []EventMeshWatchEvent{
+				{
+					Type:            WatchEventModified,
+					ResourceVersion: "1-2",
+					Kind:            WatchKindBroker,
+					Key:             "eventing.knative.dev/Broker/test-ns/test-broker",
+					Broker:          &Broker{Namespace: "test-ns", Name: "test-broker", ProvidedEventTypes: []string{"test-ns/test-et"}},
+				},
+				{
+					Type:            WatchEventAdded,
+					ResourceVersion: "1-2",
+					Kind:            WatchKindEventType,
+					Key:             "eventing.knative.dev/EventType/test-ns/test-et",
+					EventType:       &eventType,
+				},
+				{
+					Type:            WatchEventDeleted,
+					ResourceVersion: "1-2",
+					Kind:            WatchKindSource,
+					Key:             "sources.knative.dev/PingSource/test-ns/test-src",
+					Source:          &source,
+				},
+			},
+		},
+		{
+			name: "Consumers are added along with the modified event type",
+			from: EventMesh{EventTypes: []EventType{eventType}},
+			to:   EventMesh{EventTypes: []EventType{consumedEventType}},
+			want: []EventMeshWatchEvent{
+				{
+					Type:            WatchEventModified,
+					ResourceVersion: "1-2",
+					Kind:            WatchKindEventType,
+					Key:             "eventing.knative.dev/EventType/test-ns/test-et",
+					EventType:       &consumedEventType,
+				},
+				{
+					Type:            WatchEventAdded,
+					ResourceVersion: "1-2",
+					Kind:            WatchKindConsumer,
+					Key:             "test-ns/test-et/test-consumer//Service/test-ns/test-svc",
+					Consumer: &ConsumerEdge{
+						EventType: "test-ns/test-et",
+						Consumer:  consumedEventType.Consumers[0],
+					},
+				},
+			},
+		},
+		{
+			name: "Consumers are deleted along with their event type",
+			from: EventMesh{EventTypes: []EventType{consumedEventType}},
+			to:   EventMesh{},
+			want: []EventMeshWatchEvent{
+				{
+					Type:            WatchEventDeleted,
+					ResourceVersion: "1-2",
+					Kind:            WatchKindEventType,
+					Key:             "eventing.knative.dev/EventType/test-ns/test-et",
+					EventType:       &consumedEventType,
+				},
+				{
+					Type:            WatchEventDeleted,
+					ResourceVersion: "1-2",
+					Kind:            WatchKindConsumer,
+					Key:             "test-ns/test-et/test-consumer//Service/test-ns/test-svc",
+					Consumer: &ConsumerEdge{
+						EventType: "test-ns/test-et",
+						Consumer:  consumedEventType.Consumers[0],
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := diffEventMesh(tt.from, tt.to, "1-2")
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Error("diffEventMesh() (-want, +got):", diff)
+			}
+		})
+	}
+}
+
+func TestEventMeshCacheWatch(t *testing.T) {
+	c := NewEventMeshCache(nil, nil, nil, nil, nil, zap.NewNop().Sugar())
+	c.watchHistory = 2
+
+	snapshot, updated := c.watch()
+	if snapshot != nil {
+		t.Fatalf("watch() returned a snapshot before the event mesh is built: %+v", snapshot)
+	}
+
+	for i := 0; i < 3; i++ {
+		c.store(EventMesh{}, nil, nil)
+	}
+
+	select {
+	case <-updated:
+	default:
+		t.Fatal("watchers weren't notified about the new snapshots")
+	}
+
+	snapshot, _ = c.watch()
+	first := c.history[0].resourceVersion
+	if snapshot == nil || snapshot != c.currentSnapshot() {
+		t.Fatalf("watch() = %+v, want the current snapshot", snapshot)
+	}
+	if got := c.snapshotAt(snapshot.resourceVersion); got != snapshot {
+		t.Errorf("snapshotAt(%q) = %+v, want the current snapshot", snapshot.resourceVersion, got)
+	}
+	if got := c.snapshotAt(first); got == nil || got.resourceVersion != first {
+		t.Errorf("snapshotAt(%q) = %+v, want the oldest kept snapshot", first, got)
+	}
+	// only the last 2 snapshots are kept
+	if len(c.history) != 2 {
+		t.Errorf("len(history) = %d, want 2", len(c.history))
+	}
+	if got := c.snapshotAt("unknown"); got != nil {
+		t.Errorf("snapshotAt(unknown) = %+v, want nil", got)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"log"
 	"net/http"
+	"os"
+	"time"
 
 	"github.com/getkin/kin-openapi/openapi3filter"
 
@@ -14,9 +16,26 @@ import (
 	"knative.dev/backstage-plugins/backends/pkg/eventmesh/auth"
 	eventmeshv1 "knative.dev/backstage-plugins/backends/pkg/eventmesh/v1"
 
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
 	"knative.dev/eventing/pkg/kncloudevents"
+	configmapinformer "knative.dev/pkg/configmap/informer"
 	"knative.dev/pkg/injection"
 	"knative.dev/pkg/logging"
+	"knative.dev/pkg/system"
+)
+
+const (
+	// authorizationTTL is how long the authorization decisions for a token are cached.
+	authorizationTTL = time.Minute
+	// backstageIDTTL is how long the Backstage IDs of the subscribers are cached. Changes of the labels and
+	// annotations of the subscribers show up in the event mesh after this long at most.
+	backstageIDTTL = time.Minute
 )
 
 func NewController(ctx context.Context) {
@@ -34,8 +53,51 @@ func startWebServer(ctx context.Context) {
 
 	logger.Infow("Starting eventmesh-backend webserver")
 
-	noTokenConfig := injection.ParseAndGetRESTConfigOrDie()
+	inClusterConfig := injection.ParseAndGetRESTConfigOrDie()
+	// the calls to the API server are recorded in the metrics and traced. the copies of the config below keep the
+	// wrapper, so that the calls that are made with the tokens of the callers are covered as well.
+	inClusterConfig.Wrap(eventmeshv1.InstrumentTransport)
+	kubeClient := kubernetes.NewForConfigOrDie(inClusterConfig)
+
+	// the metrics and the traces are exported as configured in the config-observability ConfigMap.
+	if err := setupObservability(ctx, kubeClient, logger); err != nil {
+		log.Fatalf("Error setting up the observability: %v", err)
+	}
+
+	// the Backstage IDs of the subscribers are resolved as configured in the config-eventmesh ConfigMap.
+	// the defaults are used when the ConfigMap doesn't exist.
+	backstageIDConfig := eventmeshv1.NewBackstageIDConfigStore(logger)
+	cmWatcher := configmapinformer.NewInformedWatcher(kubeClient, system.Namespace())
+	cmWatcher.WatchWithDefault(corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: eventmeshv1.ConfigMapName}}, backstageIDConfig.OnConfigMapChanged)
+	if err := cmWatcher.Start(ctx.Done()); err != nil {
+		log.Fatalf("Error starting the ConfigMap watcher: %v", err)
+	}
+
+	// the Backstage IDs of the subscribers are kept for a while, so that they're not fetched again on every build.
+	backstageIDs := eventmeshv1.NewBackstageIDCache(backstageIDTTL)
+	// the kinds of the subscribers are mapped to their resources with the discovery API, which is cached.
+	discoveryClient := discovery.NewDiscoveryClientForConfigOrDie(inClusterConfig)
+	mapper := eventmeshv1.NewResourceMapper(discoveryClient)
 
+	// the kinds of the sources, subscribables and sinks are discovered from the CRDs by default.
+	// the discovery API is used instead when the CRDs can't be listed, both by the cache and for the callers.
+	discoveryMode, err := eventmeshv1.ParseDiscoveryMode(os.Getenv(eventmeshv1.DiscoveryModeEnvVar))
+	if err != nil {
+		log.Fatalf("Error parsing %s: %v", eventmeshv1.DiscoveryModeEnvVar, err)
+	}
+	logger.Infow("Discovering the event mesh kinds", "mode", discoveryMode)
+
+	var cacheDiscoveryClient discovery.DiscoveryInterface
+	if discoveryMode == eventmeshv1.APIDiscoveryMode {
+		cacheDiscoveryClient = discoveryClient
+	}
+
+	// the cache watches the resources with the credentials of the backend itself.
+	// the endpoint filters the cached data down to what the callers are allowed to see.
+	eventMeshCache := eventmeshv1.NewEventMeshCache(dynamic.NewForConfigOrDie(inClusterConfig), cacheDiscoveryClient, backstageIDConfig, backstageIDs, mapper, logger)
+	go eventMeshCache.Run(ctx)
+
+	noTokenConfig := rest.CopyConfig(inClusterConfig)
 	noTokenConfig.BearerToken = ""
 	noTokenConfig.Username = ""
 	noTokenConfig.Password = ""
@@ -50,9 +112,12 @@ func startWebServer(ctx context.Context) {
 	// this spec is used by the request validator middleware
 	prefixSwaggerPaths(v1swagger, "/v1")
 
-	v1endpoint := eventmeshv1.NewEndpoint(noTokenConfig, logger)
+	authorizer := auth.NewAuthorizer(noTokenConfig, authorizationTTL)
+
+	v1endpoint := eventmeshv1.NewEndpoint(noTokenConfig, eventMeshCache, authorizer, backstageIDConfig, backstageIDs, mapper, discoveryMode, logger)
 	v1strictHandler := eventmeshv1.NewStrictHandler(v1endpoint, []eventmeshv1.StrictMiddlewareFunc{})
 	v1router := mux.NewRouter()
+	v1router.Use(routeLabeler)
 	v1router.Use(auth.AuthTokenMiddleware())
 	v1router.Use(requestValidator(v1swagger))
 	v1handlerWithMiddleware := eventmeshv1.HandlerFromMuxWithBaseURL(v1strictHandler, v1router, "/v1")
@@ -61,7 +126,7 @@ func startWebServer(ctx context.Context) {
 	parentRouter.PathPrefix("/v1/").Handler(v1handlerWithMiddleware)
 
 	r := kncloudevents.NewHTTPEventReceiver(8080)
-	log.Fatal(r.StartListen(ctx, parentRouter))
+	log.Fatal(r.StartListen(ctx, instrumentHandler(parentRouter)))
 }
 
 func requestValidator(swagger *openapi3.T) func(next http.Handler) http.Handler {
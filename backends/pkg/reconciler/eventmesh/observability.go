@@ -0,0 +1,156 @@
+package eventmesh
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"knative.dev/pkg/observability"
+	o11yconfigmap "knative.dev/pkg/observability/configmap"
+	"knative.dev/pkg/observability/metrics"
+	"knative.dev/pkg/observability/resource"
+	"knative.dev/pkg/observability/semconv"
+	"knative.dev/pkg/observability/tracing"
+	"knative.dev/pkg/system"
+)
+
+const (
+	// serviceName is the name that the backend reports its telemetry with.
+	serviceName = "eventmesh-backend"
+	// metricsProtocolKey is the key of the metrics protocol in the config-observability ConfigMap.
+	metricsProtocolKey = "metrics-protocol"
+	// observabilityShutdownTimeout is how long the last metrics and spans are flushed for when the backend stops.
+	observabilityShutdownTimeout = 5 * time.Second
+)
+
+// setupObservability sets up the global meter and tracer providers as configured in the config-observability
+// ConfigMap of the system namespace, which the Knative Eventing components are configured with as well.
+// Unlike for those, the metrics are served at :9090/metrics by default, when the ConfigMap doesn't set the protocol.
+// The trace context of the callers is propagated in the W3C format. The providers are shut down when the context is
+// done.
+func setupObservability(ctx context.Context, kubeClient kubernetes.Interface, logger *zap.SugaredLogger) error {
+	cfg, err := observabilityConfig(ctx, kubeClient)
+	if err != nil {
+		return err
+	}
+
+	meterProvider, err := metrics.NewMeterProvider(ctx, cfg.Metrics, sdkmetric.WithResource(resource.Default(serviceName)))
+	if err != nil {
+		return fmt.Errorf("error setting up the metrics: %w", err)
+	}
+	otel.SetMeterProvider(meterProvider)
+	logger.Infow("Exporting metrics", "protocol", cfg.Metrics.Protocol, "endpoint", cfg.Metrics.Endpoint)
+
+	tracerProvider, err := tracing.NewTracerProvider(ctx, cfg.Tracing, sdktrace.WithResource(resource.Default(serviceName)))
+	if err != nil {
+		return fmt.Errorf("error setting up the tracing: %w", err)
+	}
+	otel.SetTextMapPropagator(tracing.DefaultTextMapPropagator())
+	otel.SetTracerProvider(tracerProvider)
+	logger.Infow("Exporting traces", "protocol", cfg.Tracing.Protocol, "endpoint", cfg.Tracing.Endpoint)
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), observabilityShutdownTimeout)
+		defer cancel()
+		if err := meterProvider.Shutdown(shutdownCtx); err != nil {
+			logger.Errorw("Error shutting down the meter provider", "error", err)
+		}
+		if err := tracerProvider.Shutdown(shutdownCtx); err != nil {
+			logger.Errorw("Error shutting down the tracer provider", "error", err)
+		}
+	}()
+	return nil
+}
+
+// observabilityConfig reads the config-observability ConfigMap. The defaults of knative.dev/pkg are used when it
+// doesn't exist, except for the metrics protocol, which is Prometheus.
+func observabilityConfig(ctx context.Context, kubeClient kubernetes.Interface) (*observability.Config, error) {
+	cm, err := kubeClient.CoreV1().ConfigMaps(system.Namespace()).Get(ctx, o11yconfigmap.Name(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		cm = &corev1.ConfigMap{}
+	} else if err != nil {
+		return nil, fmt.Errorf("error getting the %s ConfigMap: %w", o11yconfigmap.Name(), err)
+	}
+
+	data := make(map[string]string, len(cm.Data)+1)
+	data[metricsProtocolKey] = metrics.ProtocolPrometheus
+	for k, v := range cm.Data {
+		data[k] = v
+	}
+
+	cfg, err := observability.NewFromMap(data)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing the %s ConfigMap: %w", o11yconfigmap.Name(), err)
+	}
+
+	cfg.Tracing, err = tracingFromEnv(cfg.Tracing)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing the OpenTelemetry environment variables: %w", err)
+	}
+	return cfg, nil
+}
+
+// tracingFromEnv exports the traces with OTLP when the standard OpenTelemetry environment variables point at a
+// collector, and the ConfigMap doesn't configure the tracing itself. OTEL_TRACES_EXPORTER=none turns the tracing off
+// either way. Without any of them, the traces aren't exported.
+func tracingFromEnv(cfg tracing.Config) (tracing.Config, error) {
+	if os.Getenv("OTEL_TRACES_EXPORTER") == tracing.ProtocolNone {
+		return tracing.Config{Protocol: tracing.ProtocolNone}, nil
+	}
+
+	endpoint := cmp.Or(os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"), os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"))
+	if cfg.Protocol != tracing.ProtocolNone || endpoint == "" {
+		return cfg, nil
+	}
+
+	cfg = tracing.Config{
+		// the default protocol of the OpenTelemetry SDKs
+		Protocol: cmp.Or(os.Getenv("OTEL_EXPORTER_OTLP_TRACES_PROTOCOL"), os.Getenv("OTEL_EXPORTER_OTLP_PROTOCOL"), tracing.ProtocolHTTPProtobuf),
+		Endpoint: endpoint,
+		// all the traces are sampled, unless OTEL_TRACES_SAMPLER says otherwise
+		SamplingRate: 1,
+	}
+	return cfg, cfg.Validate()
+}
+
+// instrumentHandler records the requests to the handler in the metrics, by route and status, and traces them.
+// The trace context of the caller is picked up from the request, so that the traces of the callers continue here.
+func instrumentHandler(handler http.Handler) http.Handler {
+	return otelhttp.NewHandler(handler, serviceName)
+}
+
+// routeLabeler is a middleware that adds the route of the request to its metrics and names its span after it. The
+// routes of the gorilla/mux router aren't known to the instrumentation of the handler, which only sees the patterns of
+// the http.ServeMux.
+func routeLabeler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if route := mux.CurrentRoute(r); route != nil {
+			if template, err := route.GetPathTemplate(); err == nil {
+				if labeler, ok := otelhttp.LabelerFromContext(r.Context()); ok {
+					labeler.Add(semconv.HTTPRoute(template))
+				}
+				span := trace.SpanFromContext(r.Context())
+				span.SetName(r.Method + " " + template)
+				span.SetAttributes(semconv.HTTPRoute(template))
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,221 @@
+package eventmesh
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	"knative.dev/pkg/observability/metrics"
+	"knative.dev/pkg/observability/tracing"
+	"knative.dev/pkg/system"
+	_ "knative.dev/pkg/system/testing"
+)
+
+func TestObservabilityConfig(t *testing.T) {
+	tests := []struct {
+		name         string
+		data         map[string]string
+		noConfigMap  bool
+		wantProtocol string
+		wantEndpoint string
+		wantErr      bool
+	}{
+		{
+			name:         "The metrics are served with Prometheus without the ConfigMap",
+			noConfigMap:  true,
+			wantProtocol: metrics.ProtocolPrometheus,
+		},
+		{
+			name:         "The metrics are served with Prometheus when the ConfigMap doesn't set the protocol",
+			data:         map[string]string{"tracing-protocol": "none"},
+			wantProtocol: metrics.ProtocolPrometheus,
+		},
+		{
+			name:         "The metrics can be turned off",
+			data:         map[string]string{"metrics-protocol": "none"},
+			wantProtocol: metrics.ProtocolNone,
+		},
+		{
+			name:         "The metrics can be exported with OTLP",
+			data:         map[string]string{"metrics-protocol": "grpc", "metrics-endpoint": "http://otel-collector:4317"},
+			wantProtocol: metrics.ProtocolGRPC,
+			wantEndpoint: "http://otel-collector:4317",
+		},
+		{
+			name:    "An invalid configuration is an error",
+			data:    map[string]string{"metrics-protocol": "carrier-pigeon"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var objects []runtime.Object
+			if !tt.noConfigMap {
+				objects = append(objects, &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: "config-observability", Namespace: system.Namespace()},
+					Data:       tt.data,
+				})
+			}
+
+			cfg, err := observabilityConfig(context.TODO(), kubefake.NewSimpleClientset(objects...))
+			if tt.wantErr {
+				if err == nil {
+					t.Error("observabilityConfig() error = nil, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("observabilityConfig() error = %v", err)
+			}
+			if cfg.Metrics.Protocol != tt.wantProtocol || cfg.Metrics.Endpoint != tt.wantEndpoint {
+				t.Errorf("observabilityConfig() metrics = (%q, %q), want (%q, %q)", cfg.Metrics.Protocol, cfg.Metrics.Endpoint, tt.wantProtocol, tt.wantEndpoint)
+			}
+		})
+	}
+}
+
+func TestTracingFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     tracing.Config
+		env     map[string]string
+		want    tracing.Config
+		wantErr bool
+	}{
+		{
+			name: "The traces aren't exported without the environment variables",
+			cfg:  tracing.Config{Protocol: tracing.ProtocolNone},
+			want: tracing.Config{Protocol: tracing.ProtocolNone},
+		},
+		{
+			name: "The traces are exported with OTLP over HTTP to the endpoint",
+			cfg:  tracing.Config{Protocol: tracing.ProtocolNone},
+			env:  map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://otel-collector:4318"},
+			want: tracing.Config{Protocol: tracing.ProtocolHTTPProtobuf, Endpoint: "http://otel-collector:4318", SamplingRate: 1},
+		},
+		{
+			name: "The endpoint and the protocol of the traces take precedence",
+			cfg:  tracing.Config{Protocol: tracing.ProtocolNone},
+			env: map[string]string{
+				"OTEL_EXPORTER_OTLP_ENDPOINT":        "http://otel-collector:4318",
+				"OTEL_EXPORTER_OTLP_PROTOCOL":        "http/protobuf",
+				"OTEL_EXPORTER_OTLP_TRACES_ENDPOINT": "http://otel-collector:4317",
+				"OTEL_EXPORTER_OTLP_TRACES_PROTOCOL": "grpc",
+			},
+			want: tracing.Config{Protocol: tracing.ProtocolGRPC, Endpoint: "http://otel-collector:4317", SamplingRate: 1},
+		},
+		{
+			name: "The ConfigMap takes precedence",
+			cfg:  tracing.Config{Protocol: tracing.ProtocolGRPC, Endpoint: "http://jaeger:4317", SamplingRate: 0.1},
+			env:  map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://otel-collector:4318"},
+			want: tracing.Config{Protocol: tracing.ProtocolGRPC, Endpoint: "http://jaeger:4317", SamplingRate: 0.1},
+		},
+		{
+			name: "The traces can be turned off",
+			cfg:  tracing.Config{Protocol: tracing.ProtocolGRPC, Endpoint: "http://jaeger:4317", SamplingRate: 0.1},
+			env:  map[string]string{"OTEL_TRACES_EXPORTER": "none", "OTEL_EXPORTER_OTLP_ENDPOINT": "http://otel-collector:4318"},
+			want: tracing.Config{Protocol: tracing.ProtocolNone},
+		},
+		{
+			name:    "An unknown protocol is an error",
+			cfg:     tracing.Config{Protocol: tracing.ProtocolNone},
+			env:     map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://otel-collector:4318", "OTEL_EXPORTER_OTLP_PROTOCOL": "carrier-pigeon"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{
+				"OTEL_TRACES_EXPORTER",
+				"OTEL_EXPORTER_OTLP_ENDPOINT",
+				"OTEL_EXPORTER_OTLP_PROTOCOL",
+				"OTEL_EXPORTER_OTLP_TRACES_ENDPOINT",
+				"OTEL_EXPORTER_OTLP_TRACES_PROTOCOL",
+			} {
+				t.Setenv(key, tt.env[key])
+			}
+
+			got, err := tracingFromEnv(tt.cfg)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("tracingFromEnv() error = nil, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("tracingFromEnv() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("tracingFromEnv() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRouteLabeler(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	recorder := tracetest.NewSpanRecorder()
+
+	router := mux.NewRouter()
+	router.Use(routeLabeler)
+	router.HandleFunc("/v1/getEventMesh", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	})
+	handler := otelhttp.NewHandler(router, serviceName,
+		otelhttp.WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+		otelhttp.WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))),
+		otelhttp.WithPropagators(tracing.DefaultTextMapPropagator()),
+	)
+
+	// the trace context of the caller, in the W3C format
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	req := httptest.NewRequest(http.MethodGet, "/v1/getEventMesh", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	if got := spans[0].Name(); got != "GET /v1/getEventMesh" {
+		t.Errorf("span name = %q, want %q", got, "GET /v1/getEventMesh")
+	}
+	if got := spans[0].SpanContext().TraceID().String(); got != traceID {
+		t.Errorf("span trace ID = %q, want the trace ID of the caller %q", got, traceID)
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.TODO(), &rm); err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != "http.server.request.duration" {
+				continue
+			}
+			dp := m.Data.(metricdata.Histogram[float64]).DataPoints[0]
+			route, _ := dp.Attributes.Value("http.route")
+			code, _ := dp.Attributes.Value("http.response.status_code")
+			if route.AsString() != "/v1/getEventMesh" || code.AsInt64() != http.StatusForbidden {
+				t.Errorf("request recorded with route %q and status %d, want %q and %d", route.AsString(), code.AsInt64(), "/v1/getEventMesh", http.StatusForbidden)
+			}
+			return
+		}
+	}
+	t.Error("the request was not recorded")
+}
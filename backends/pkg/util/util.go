@@ -6,6 +6,7 @@ import (
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/version"
 
 	v1 "knative.dev/pkg/apis/duck/v1"
 )
@@ -83,31 +84,47 @@ func groupFromUnstructured(u *unstructured.Unstructured) (string, error) {
 	return group, nil
 }
 
+// versionFromUnstructured returns the version of the CRD that the resources are listed with.
+// That's the storage version if it's served, otherwise the served version that the API discovery prefers, which is
+// the most stable and latest one, e.g. v1 over v1beta1 over v1alpha2. The order of the versions in the CRD doesn't
+// matter, it can change when the CRD is upgraded.
 func versionFromUnstructured(u *unstructured.Unstructured) (string, error) {
 	content := u.UnstructuredContent()
-	var version string
 	versions, found, err := unstructured.NestedSlice(content, "spec", "versions")
 	if !found || err != nil || len(versions) == 0 {
-		version, found, err = unstructured.NestedString(content, "spec", "version")
-		if !found || err != nil {
+		// CRDs of apiextensions.k8s.io/v1beta1 may only have a single version
+		version, found, err := unstructured.NestedString(content, "spec", "version")
+		if !found || err != nil || version == "" {
 			return "", fmt.Errorf("can't find source version from source CRD: %w", err)
 		}
-	} else {
-		for _, v := range versions {
-			if vmap, ok := v.(map[string]interface{}); ok {
-				if vmap["served"] == true {
-					version = vmap["name"].(string)
-					break
-				}
-			}
-		}
+		return version, nil
 	}
 
-	if version == "" {
-		return "", fmt.Errorf("can't find source version from source CRD: %w", err)
+	var storage, preferred string
+	for _, v := range versions {
+		vmap, ok := v.(map[string]interface{})
+		if !ok || vmap["served"] != true {
+			continue
+		}
+		name, ok := vmap["name"].(string)
+		if !ok || name == "" {
+			continue
+		}
+		if vmap["storage"] == true {
+			storage = name
+		}
+		if preferred == "" || version.CompareKubeAwareVersionStrings(name, preferred) > 0 {
+			preferred = name
+		}
 	}
 
-	return version, nil
+	if storage != "" {
+		return storage, nil
+	}
+	if preferred != "" {
+		return preferred, nil
+	}
+	return "", fmt.Errorf("can't find a served version in source CRD %s", u.GetName())
 }
 
 func resourceFromUnstructured(u *unstructured.Unstructured) (string, error) {
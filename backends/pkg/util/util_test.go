@@ -0,0 +1,73 @@
+package util
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestGVRFromUnstructured(t *testing.T) {
+	tests := []struct {
+		name     string
+		versions []interface{}
+		version  string
+		want     schema.GroupVersionResource
+		error    bool
+	}{
+		{
+			name: "storage version",
+			versions: []interface{}{
+				map[string]interface{}{"name": "v1alpha1", "served": true, "storage": false},
+				map[string]interface{}{"name": "v1beta1", "served": true, "storage": true},
+				map[string]interface{}{"name": "v1", "served": true, "storage": false},
+			},
+			want: schema.GroupVersionResource{Group: "sources.knative.dev", Version: "v1beta1", Resource: "pingsources"},
+		},
+		{
+			name: "preferred version when the storage version is not served",
+			versions: []interface{}{
+				map[string]interface{}{"name": "v1alpha1", "served": true, "storage": false},
+				map[string]interface{}{"name": "v1", "served": true, "storage": false},
+				map[string]interface{}{"name": "v1beta2", "served": true, "storage": false},
+				map[string]interface{}{"name": "v2alpha1", "served": false, "storage": true},
+			},
+			want: schema.GroupVersionResource{Group: "sources.knative.dev", Version: "v1", Resource: "pingsources"},
+		},
+		{
+			name:    "single version",
+			version: "v1beta1",
+			want:    schema.GroupVersionResource{Group: "sources.knative.dev", Version: "v1beta1", Resource: "pingsources"},
+		},
+		{
+			name: "no served version",
+			versions: []interface{}{
+				map[string]interface{}{"name": "v1", "served": false, "storage": true},
+			},
+			error: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := map[string]interface{}{
+				"group": "sources.knative.dev",
+				"names": map[string]interface{}{"kind": "PingSource", "plural": "pingsources"},
+			}
+			if tt.versions != nil {
+				spec["versions"] = tt.versions
+			}
+			if tt.version != "" {
+				spec["version"] = tt.version
+			}
+			crd := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+
+			got, err := GVRFromUnstructured(crd)
+			if (err != nil) != tt.error {
+				t.Fatalf("GVRFromUnstructured() error = %v, error %v", err, tt.error)
+			}
+			if got != tt.want {
+				t.Errorf("GVRFromUnstructured() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}